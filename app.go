@@ -11,7 +11,9 @@ import (
 	_ "github.com/micro-plat/hydra/registry/watcher/wvalue"
 
 	_ "github.com/micro-plat/hydra/hydra/cmds/conf"
+	_ "github.com/micro-plat/hydra/hydra/cmds/ctl"
 	_ "github.com/micro-plat/hydra/hydra/cmds/install"
+	_ "github.com/micro-plat/hydra/hydra/cmds/mqc"
 	"github.com/micro-plat/hydra/hydra/cmds/pkgs/service"
 	_ "github.com/micro-plat/hydra/hydra/cmds/remove"
 	_ "github.com/micro-plat/hydra/hydra/cmds/run"
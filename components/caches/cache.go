@@ -49,5 +49,5 @@ func (s *StandardCache) GetCache(names ...string) (c ICache, err error) {
 	if err != nil {
 		return nil, err
 	}
-	return obj.(ICache), nil
+	return withTrace(obj.(ICache), name), nil
 }
@@ -0,0 +1,119 @@
+package caches
+
+import (
+	"strings"
+
+	"github.com/micro-plat/hydra/context"
+)
+
+//withTrace 当前请求启用链路跟踪或慢请求分析时，记录缓存操作的跟踪数据与耗时
+func withTrace(c ICache, name string) ICache {
+	ctx, ok := context.GetContext()
+	if !ok {
+		return c
+	}
+	if _, profiling := context.GetProfile(); !ctx.Tracer().Available() && !profiling {
+		return c
+	}
+	return &traceCache{c: c, name: name}
+}
+
+//traceCache 为每次缓存操作创建子跨度
+type traceCache struct {
+	c    ICache
+	name string
+}
+
+//Get 获取缓存数据
+func (t *traceCache) Get(key string) (v string, err error) {
+	done := t.start("get", key)
+	v, err = t.c.Get(key)
+	done(err)
+	return
+}
+
+//Decrement 减少计数
+func (t *traceCache) Decrement(key string, delta int64) (n int64, err error) {
+	done := t.start("decrement", key)
+	n, err = t.c.Decrement(key, delta)
+	done(err)
+	return
+}
+
+//Increment 增加计数
+func (t *traceCache) Increment(key string, delta int64) (n int64, err error) {
+	done := t.start("increment", key)
+	n, err = t.c.Increment(key, delta)
+	done(err)
+	return
+}
+
+//Gets 获取多个缓存数据
+func (t *traceCache) Gets(key ...string) (r []string, err error) {
+	done := t.start("gets", key...)
+	r, err = t.c.Gets(key...)
+	done(err)
+	return
+}
+
+//Add 添加缓存数据，已存在时返回错误
+func (t *traceCache) Add(key string, value string, expiresAt int) (err error) {
+	done := t.start("add", key)
+	err = t.c.Add(key, value, expiresAt)
+	done(err)
+	return
+}
+
+//Set 设置缓存数据
+func (t *traceCache) Set(key string, value string, expiresAt int) (err error) {
+	done := t.start("set", key)
+	err = t.c.Set(key, value, expiresAt)
+	done(err)
+	return
+}
+
+//Delete 删除缓存数据
+func (t *traceCache) Delete(key string) (err error) {
+	done := t.start("delete", key)
+	err = t.c.Delete(key)
+	done(err)
+	return
+}
+
+//Exists 检查缓存数据是否存在
+func (t *traceCache) Exists(key string) bool {
+	done := t.start("exists", key)
+	b := t.c.Exists(key)
+	done(nil)
+	return b
+}
+
+//Delay 延长缓存数据的过期时间
+func (t *traceCache) Delay(key string, expiresAt int) (err error) {
+	done := t.start("delay", key)
+	err = t.c.Delay(key, expiresAt)
+	done(err)
+	return
+}
+
+//Close 关闭缓存连接
+func (t *traceCache) Close() error {
+	return t.c.Close()
+}
+
+func (t *traceCache) start(operation string, key ...string) func(error) {
+	span := context.StartSpan("CACHE " + operation)
+	if span != nil {
+		span.SetTag("cache.name", t.name)
+		span.SetTag("cache.operation", operation)
+		span.SetTag("cache.key", strings.Join(key, ","))
+	}
+	profiling := context.Profiling(context.KindCache, t.name+"."+operation)
+	return func(err error) {
+		if span != nil {
+			span.SetError(err)
+			span.End()
+		}
+		profiling(err)
+	}
+}
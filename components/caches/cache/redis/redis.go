@@ -96,16 +96,14 @@ func (c *Client) Add(key string, value string, expiresAt int) error {
 	if expiresAt == 0 {
 		expires = 0
 	}
-	i, err := c.client.Exists(key).Result()
+	ok, err := c.client.SetNX(key, value, expires).Result()
 	if err != nil {
 		return err
 	}
-	if i == 1 {
-		err = fmt.Errorf("key:%s已存在", key)
-		return err
+	if !ok {
+		return fmt.Errorf("key:%s已存在", key)
 	}
-	_, err = c.client.Set(key, value, expires).Result()
-	return err
+	return nil
 }
 
 // Set 更新数据到redis中，没有则添加
@@ -0,0 +1,139 @@
+package respcache
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/micro-plat/hydra/components/caches/cache"
+)
+
+//IRespCache 响应缓存管理
+type IRespCache interface {
+
+	//RemoveByPath 清除指定请求路径的缓存(包含该路径下所有参数,请求头,用户的缓存结果)
+	RemoveByPath(paths ...string) error
+
+	//RemoveByTag 清除指定标签的缓存
+	RemoveByTag(tags ...string) error
+}
+
+//Entry 缓存的响应结果
+type Entry struct {
+	Status      int               `json:"status"`
+	Content     string            `json:"content,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+	ETag        string            `json:"etag,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Versions    map[string]string `json:"versions,omitempty"`
+}
+
+//RespCache 响应缓存,通过路径与标签的版本号控制缓存失效
+type RespCache struct {
+	c      cache.ICache
+	prefix string
+}
+
+//New 构建响应缓存
+func New(c cache.ICache, serverType string) *RespCache {
+	return &RespCache{c: c, prefix: fmt.Sprintf("hydra:respcache:%s", serverType)}
+}
+
+//GetKey 根据缓存键原串构建缓存键
+func (r *RespCache) GetKey(raw string) string {
+	return fmt.Sprintf("%s:%s", r.prefix, md5Hex(raw))
+}
+
+//GetVersions 获取请求路径与标签的当前版本号
+func (r *RespCache) GetVersions(path string, tags ...string) (map[string]string, error) {
+	names := r.getVersionKeys(path, tags...)
+	versions := make(map[string]string, len(names))
+	for _, name := range names {
+		v, err := r.c.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		versions[name] = v
+	}
+	return versions, nil
+}
+
+//Get 获取缓存的响应结果,版本号已变更的结果视为失效
+func (r *RespCache) Get(key string, versions map[string]string) (*Entry, bool, error) {
+	data, err := r.c.Get(key)
+	if err != nil || data == "" {
+		return nil, false, err
+	}
+	entry := &Entry{}
+	if err := json.Unmarshal([]byte(data), entry); err != nil {
+		return nil, false, fmt.Errorf("缓存结果格式有误:%w", err)
+	}
+	for k, v := range versions {
+		if entry.Versions[k] != v {
+			return nil, false, nil
+		}
+	}
+	return entry, true, nil
+}
+
+//Save 保存响应结果
+func (r *RespCache) Save(key string, entry *Entry, expire int) error {
+	buff, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return r.c.Set(key, string(buff), expire)
+}
+
+//RemoveByPath 清除指定请求路径的缓存
+func (r *RespCache) RemoveByPath(paths ...string) error {
+	for _, path := range paths {
+		if err := r.renew(r.getPathKey(path)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//RemoveByTag 清除指定标签的缓存
+func (r *RespCache) RemoveByTag(tags ...string) error {
+	for _, tag := range tags {
+		if err := r.renew(r.getTagKey(tag)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RespCache) renew(name string) error {
+	return r.c.Set(name, fmt.Sprint(time.Now().UnixNano()), 0)
+}
+
+func (r *RespCache) getVersionKeys(path string, tags ...string) []string {
+	names := make([]string, 0, len(tags)+1)
+	names = append(names, r.getPathKey(path))
+	for _, tag := range tags {
+		names = append(names, r.getTagKey(tag))
+	}
+	return names
+}
+
+func (r *RespCache) getPathKey(path string) string {
+	return fmt.Sprintf("%s:path:%s", r.prefix, path)
+}
+
+func (r *RespCache) getTagKey(tag string) string {
+	return fmt.Sprintf("%s:tag:%s", r.prefix, tag)
+}
+
+//MakeETag 根据响应内容生成ETag
+func MakeETag(content string) string {
+	return fmt.Sprintf(`"%s"`, md5Hex(content))
+}
+
+func md5Hex(s string) string {
+	h := md5.Sum([]byte(s))
+	return hex.EncodeToString(h[:])
+}
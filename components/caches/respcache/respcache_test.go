@@ -0,0 +1,38 @@
+package respcache
+
+import (
+	"testing"
+
+	"github.com/micro-plat/hydra/components/caches/cache/gocache"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestRespCache_Remove(t *testing.T) {
+	c, err := gocache.NewByOpts()
+	assert.Equal(t, nil, err, "构建gocache")
+
+	store := New(c, "api")
+	key := store.GetKey("/order/query|p:id=1")
+
+	versions, err := store.GetVersions("/order/query", "order")
+	assert.Equal(t, nil, err, "1. 获取版本号")
+	_, ok, err := store.Get(key, versions)
+	assert.Equal(t, false, ok, "1. 未保存结果")
+
+	err = store.Save(key, &Entry{Status: 200, Content: "{}", ETag: MakeETag("{}"), Versions: versions}, 60)
+	assert.Equal(t, nil, err, "2. 保存结果")
+	entry, ok, err := store.Get(key, versions)
+	assert.Equal(t, true, ok, "2. 获取已保存的结果")
+	assert.Equal(t, "{}", entry.Content, "2. 获取已保存的结果")
+
+	store.RemoveByTag("order")
+	versions, _ = store.GetVersions("/order/query", "order")
+	_, ok, _ = store.Get(key, versions)
+	assert.Equal(t, false, ok, "3. 按标签清除后缓存失效")
+
+	store.Save(key, &Entry{Status: 200, Content: "{}", Versions: versions}, 60)
+	store.RemoveByPath("/order/query")
+	versions, _ = store.GetVersions("/order/query", "order")
+	_, ok, _ = store.Get(key, versions)
+	assert.Equal(t, false, ok, "4. 按路径清除后缓存失效")
+}
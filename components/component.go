@@ -6,20 +6,26 @@ import (
 	"fmt"
 
 	"github.com/micro-plat/hydra/components/caches"
+	"github.com/micro-plat/hydra/components/caches/respcache"
 	"github.com/micro-plat/hydra/components/container"
 	"github.com/micro-plat/hydra/components/dbs"
+	"github.com/micro-plat/hydra/components/delay"
 	"github.com/micro-plat/hydra/components/dlock"
 	"github.com/micro-plat/hydra/components/http"
+	"github.com/micro-plat/hydra/components/metrics"
 	"github.com/micro-plat/hydra/components/queues"
 	"github.com/micro-plat/hydra/components/rpcs"
 	"github.com/micro-plat/hydra/components/uuid"
+	"github.com/micro-plat/hydra/conf/app"
 	"github.com/micro-plat/hydra/context"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/hydra/registry"
 
 	_ "github.com/micro-plat/hydra/components/queues/mq/lmq"
 	_ "github.com/micro-plat/hydra/components/queues/mq/mqtt"
+	_ "github.com/micro-plat/hydra/components/queues/mq/nats"
 	_ "github.com/micro-plat/hydra/components/queues/mq/redis"
+	_ "github.com/micro-plat/hydra/components/queues/mq/redisstream"
 	_ "github.com/micro-plat/hydra/components/queues/mq/xmq"
 )
 
@@ -29,10 +35,15 @@ type IComponent interface {
 	RPC() rpcs.IComponentRPC
 	Queue() queues.IComponentQueue
 	Cache() caches.IComponentCache
+	GetRespCache(serverType ...string) (respcache.IRespCache, error)
+	GetRegularRespCache(serverType ...string) respcache.IRespCache
 	HTTP() http.IComponentHTTPClient
 	DB() dbs.IComponentDB
 	DLock(name string) (dlock.ILock, error)
 	UUID() uuid.UUID
+	GetMetric(serverType ...string) (metrics.IMetric, error)
+	GetRegularMetric(serverType ...string) metrics.IMetric
+	Delay() delay.IComponentDelay
 }
 
 //Def 默认组件
@@ -46,6 +57,7 @@ type Component struct {
 	cache      caches.IComponentCache
 	db         dbs.IComponentDB
 	httpClient http.IComponentHTTPClient
+	delay      delay.IComponentDelay
 }
 
 //NewComponent 创建组件
@@ -58,6 +70,7 @@ func NewComponent() *Component {
 	c.cache = caches.NewStandardCache(c.c)
 	c.db = dbs.NewStandardDB(c.c)
 	c.httpClient = http.NewStandardHTTPClient(c.c)
+	c.delay = delay.NewStandardDelay(c.c)
 	return c
 }
 
@@ -81,6 +94,59 @@ func (c *Component) Cache() caches.IComponentCache {
 	return c.cache
 }
 
+//GetRegularRespCache 获取响应缓存管理组件，未指定服务器类型时使用当前请求的服务器，获取失败时panic
+func (c *Component) GetRegularRespCache(serverType ...string) respcache.IRespCache {
+	r, err := c.GetRespCache(serverType...)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+//GetRespCache 获取响应缓存管理组件，未指定服务器类型时使用当前请求的服务器
+func (c *Component) GetRespCache(serverType ...string) (respcache.IRespCache, error) {
+	appConf, err := getAPPConf(serverType...)
+	if err != nil {
+		return nil, err
+	}
+	conf, err := appConf.GetCacheConf()
+	if err != nil {
+		return nil, fmt.Errorf("获取响应缓存配置失败:%w", err)
+	}
+	cache, err := c.cache.GetCache(conf.Cache)
+	if err != nil {
+		return nil, fmt.Errorf("获取响应缓存组件失败:%w", err)
+	}
+	return respcache.New(cache, appConf.GetServerConf().GetServerType()), nil
+}
+
+//getAPPConf 获取指定服务器类型的配置，未指定时使用当前请求的服务器
+func getAPPConf(serverType ...string) (app.IAPPConf, error) {
+	if len(serverType) > 0 {
+		return app.Cache.GetAPPConf(serverType[0])
+	}
+	return context.Current().APPConf(), nil
+}
+
+//GetRegularMetric 获取业务统计组件，未指定服务器类型时使用当前请求的服务器，获取失败时panic
+func (c *Component) GetRegularMetric(serverType ...string) metrics.IMetric {
+	m, err := c.GetMetric(serverType...)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+//GetMetric 获取业务统计组件，未指定服务器类型时使用当前请求的服务器
+func (c *Component) GetMetric(serverType ...string) (metrics.IMetric, error) {
+	appConf, err := getAPPConf(serverType...)
+	if err != nil {
+		return nil, fmt.Errorf("获取业务统计组件失败:%w", err)
+	}
+	server := appConf.GetServerConf()
+	return metrics.New(server.GetServerType(), server.GetServerName()), nil
+}
+
 //DB 获取DB组件
 func (c *Component) DB() dbs.IComponentDB {
 	return c.db
@@ -91,6 +157,11 @@ func (c *Component) HTTP() http.IComponentHTTPClient {
 	return c.httpClient
 }
 
+//Delay 获取延迟任务组件
+func (c *Component) Delay() delay.IComponentDelay {
+	return c.delay
+}
+
 //DLock 获取分布式鍞
 func (c *Component) DLock(name string) (dlock.ILock, error) {
 	return dlock.NewLock(registry.Join(global.Def.PlatName, "dlock", name), global.Def.RegistryAddr, context.Current().Log())
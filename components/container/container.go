@@ -3,6 +3,7 @@ package container
 import (
 	"fmt"
 	"io"
+	"sort"
 	"time"
 
 	"github.com/micro-plat/hydra/conf"
@@ -19,12 +20,24 @@ type ICloser interface {
 //IContainer 组件容器
 type IContainer interface {
 	GetOrCreate(typ string, name string, creator func(conf *conf.RawConf) (interface{}, error)) (interface{}, error)
+	Items() []*Item
 	ICloser
 }
 
+//Item 已创建的组件信息
+type Item struct {
+	Type    string    `json:"type"`
+	Name    string    `json:"name"`
+	Version int32     `json:"version"`
+	Object  string    `json:"object"`
+	Current bool      `json:"current"`
+	Created time.Time `json:"created"`
+}
+
 //Container 容器用于缓存公共组件
 type Container struct {
 	cache     cmap.ConcurrentMap
+	items     cmap.ConcurrentMap
 	histories *histories
 }
 
@@ -32,6 +45,7 @@ type Container struct {
 func NewContainer() *Container {
 	c := &Container{
 		cache:     cmap.New(8),
+		items:     cmap.New(8),
 		histories: newHistories(),
 	}
 	go c.clear()
@@ -63,11 +77,32 @@ func (c *Container) GetOrCreate(typ string, name string, creator func(conf *conf
 			return nil, err
 		}
 		c.histories.Add(fmt.Sprintf("%s_%s", typ, name), key)
+		c.items.Set(key, &Item{Type: typ, Name: name, Version: jconf.GetVersion(), Object: fmt.Sprintf("%T", v), Created: time.Now()})
 		return v, nil
 	}, jconf)
 	return obj, err
 }
 
+//Items 获取已创建的组件，Current表示是否为当前配置版本创建的组件
+func (c *Container) Items() []*Item {
+	list := make([]*Item, 0, c.items.Count())
+	for k, v := range c.items.Items() {
+		item := *(v.(*Item))
+		item.Current = c.histories.IsCurrent(fmt.Sprintf("%s_%s", item.Type, item.Name), k)
+		list = append(list, &item)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Type != list[j].Type {
+			return list[i].Type < list[j].Type
+		}
+		if list[i].Name != list[j].Name {
+			return list[i].Name < list[j].Name
+		}
+		return list[i].Version < list[j].Version
+	})
+	return list
+}
+
 //Close 释放组件资源
 func (c *Container) Close() error {
 	c.cache.RemoveIterCb(func(key string, v interface{}) bool {
@@ -76,6 +111,7 @@ func (c *Container) Close() error {
 		}
 		return true
 	})
+	c.items.RemoveIterCb(func(key string, v interface{}) bool { return true })
 	return nil
 }
 
@@ -92,9 +128,10 @@ LOOP:
 				if !ok {
 					return true
 				}
-				if c, ok := v.(io.Closer); ok {
-					c.Close()
+				if closer, ok := v.(io.Closer); ok {
+					closer.Close()
 				}
+				c.items.Remove(key)
 				return true
 			})
 		}
@@ -13,6 +13,7 @@ func TestNewContainer(t *testing.T) {
 	l := NewContainer()
 	if !reflect.DeepEqual(l, &Container{
 		cache:     cmap.New(8),
+		items:     cmap.New(8),
 		histories: newHistories(),
 	}) {
 		t.Error("NewContainer() didn't return *Container")
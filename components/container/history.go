@@ -35,6 +35,14 @@ func (v *histories) Add(group string, key string) {
 	his.keys = append(his.keys, key)
 }
 
+//IsCurrent 是否为分组当前的key
+func (v *histories) IsCurrent(group string, key string) bool {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	his, ok := v.records[group]
+	return ok && his.current == key
+}
+
 //Remove 移除key信息
 func (v *histories) Remove(f func(key string) bool) {
 	v.lock.Lock()
@@ -54,5 +54,5 @@ func (s *StandardDB) GetDB(names ...string) (d IDB, err error) {
 	if err != nil {
 		return nil, err
 	}
-	return obj.(IDB), nil
+	return withTrace(obj.(IDB), name), nil
 }
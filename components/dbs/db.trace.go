@@ -0,0 +1,117 @@
+package dbs
+
+import (
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/lib4go/db"
+)
+
+//withTrace 当前请求启用链路跟踪或慢请求分析时，记录数据库操作的跟踪数据与耗时
+func withTrace(d IDB, name string) IDB {
+	ctx, ok := context.GetContext()
+	if !ok {
+		return d
+	}
+	if _, profiling := context.GetProfile(); !ctx.Tracer().Available() && !profiling {
+		return d
+	}
+	return &traceDB{traceExecuter: &traceExecuter{e: d, name: name}, db: d}
+}
+
+//traceDB 记录链路跟踪数据的数据库操作对象
+type traceDB struct {
+	*traceExecuter
+	db IDB
+}
+
+//traceExecuter 为每次数据库操作创建子跨度
+type traceExecuter struct {
+	e    db.IDBExecuter
+	name string
+}
+
+//Query 查询数据
+func (t *traceExecuter) Query(sql string, input map[string]interface{}) (data db.QueryRows, query string, args []interface{}, err error) {
+	done := t.start("query", sql)
+	data, query, args, err = t.e.Query(sql, input)
+	done(err)
+	return
+}
+
+//Scalar 查询第一行第一列数据
+func (t *traceExecuter) Scalar(sql string, input map[string]interface{}) (data interface{}, query string, args []interface{}, err error) {
+	done := t.start("scalar", sql)
+	data, query, args, err = t.e.Scalar(sql, input)
+	done(err)
+	return
+}
+
+//Execute 执行SQL语句
+func (t *traceExecuter) Execute(sql string, input map[string]interface{}) (row int64, query string, args []interface{}, err error) {
+	done := t.start("execute", sql)
+	row, query, args, err = t.e.Execute(sql, input)
+	done(err)
+	return
+}
+
+//Executes 执行SQL语句，并返回新增记录编号
+func (t *traceExecuter) Executes(sql string, input map[string]interface{}) (lastInsertID int64, affectedRow int64, query string, args []interface{}, err error) {
+	done := t.start("executes", sql)
+	lastInsertID, affectedRow, query, args, err = t.e.Executes(sql, input)
+	done(err)
+	return
+}
+
+func (t *traceExecuter) start(operation string, sql string) func(error) {
+	span := context.StartSpan("DB " + operation)
+	if span != nil {
+		span.SetTag("db.name", t.name)
+		span.SetTag("db.operation", operation)
+		span.SetTag("db.statement", sql)
+	}
+	profiling := context.Profiling(context.KindDB, t.name+"."+operation)
+	return func(err error) {
+		if span != nil {
+			span.SetError(err)
+			span.End()
+		}
+		profiling(err)
+	}
+}
+
+//ExecuteSP 执行存储过程
+func (t *traceDB) ExecuteSP(procName string, input map[string]interface{}, output ...interface{}) (row int64, query string, err error) {
+	done := t.start("procedure", procName)
+	row, query, err = t.db.ExecuteSP(procName, input, output...)
+	done(err)
+	return
+}
+
+//Begin 开始事务
+func (t *traceDB) Begin() (db.IDBTrans, error) {
+	trans, err := t.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &traceTrans{traceExecuter: &traceExecuter{e: trans, name: t.name}, trans: trans}, nil
+}
+
+//Close 关闭数据库连接
+func (t *traceDB) Close() {
+	t.db.Close()
+}
+
+//traceTrans 记录链路跟踪数据的数据库事务
+type traceTrans struct {
+	*traceExecuter
+	trans db.IDBTrans
+}
+
+//Rollback 回滚事务
+func (t *traceTrans) Rollback() error {
+	return t.trans.Rollback()
+}
+
+//Commit 提交事务
+func (t *traceTrans) Commit() error {
+	return t.trans.Commit()
+}
@@ -0,0 +1,192 @@
+package delay
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/micro-plat/hydra/components/container"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/conf/app"
+	vardelay "github.com/micro-plat/hydra/conf/vars/delay"
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/lib4go/types"
+	"github.com/micro-plat/lib4go/utility"
+)
+
+//maxBackoff 最大重试间隔
+const maxBackoff = time.Hour
+
+//Job 延迟任务
+type Job struct {
+	ID       string                 `json:"id"`
+	Key      string                 `json:"key,omitempty"`
+	Service  string                 `json:"service"`
+	Params   map[string]interface{} `json:"params,omitempty"`
+	Header   map[string]string      `json:"header,omitempty"`
+	At       int64                  `json:"at"`
+	Attempts int                    `json:"attempts,omitempty"`
+	Retry    int                    `json:"retry,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+}
+
+//IDelay 延迟任务，由cron服务器在指定时间执行服务
+type IDelay interface {
+	//At 在指定时间执行服务，返回任务编号，指定去重键时返回未执行的相同任务编号
+	At(service string, at time.Time, params map[string]interface{}, opts ...Option) (string, error)
+
+	//After 延迟指定时长后执行服务
+	After(service string, d time.Duration, params map[string]interface{}, opts ...Option) (string, error)
+
+	//Cancel 取消未执行的任务
+	Cancel(id string) error
+}
+
+//IExecutor 延迟任务执行器，供cron服务器获取并执行到期任务
+type IExecutor interface {
+	//Claim 获取到期的任务，获取后在执行超时时长内不会被再次获取
+	Claim(limit int) ([]*Job, error)
+
+	//Done 任务执行成功
+	Done(job *Job) error
+
+	//Fail 任务执行失败，按退避策略重试，超过最大重试次数后移除并返回false
+	Fail(job *Job, reason string) (bool, error)
+}
+
+//IComponentDelay 延迟任务组件
+type IComponentDelay interface {
+	GetRegularDelay(names ...string) IDelay
+	GetDelay(names ...string) (IDelay, error)
+	GetExecutor(names ...string) (IExecutor, error)
+}
+
+//StandardDelay 延迟任务组件
+type StandardDelay struct {
+	c container.IContainer
+}
+
+//NewStandardDelay 创建延迟任务组件
+func NewStandardDelay(c container.IContainer) *StandardDelay {
+	return &StandardDelay{c: c}
+}
+
+//GetRegularDelay 获取正式的没有异常的延迟任务实例
+func (s *StandardDelay) GetRegularDelay(names ...string) IDelay {
+	d, err := s.GetDelay(names...)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+//GetDelay 获取延迟任务实例
+func (s *StandardDelay) GetDelay(names ...string) (IDelay, error) {
+	return s.get(names...)
+}
+
+//GetExecutor 获取延迟任务执行器
+func (s *StandardDelay) GetExecutor(names ...string) (IExecutor, error) {
+	return s.get(names...)
+}
+
+func (s *StandardDelay) get(names ...string) (*delay, error) {
+	name := types.GetStringByIndex(names, 0, vardelay.DefName)
+	obj, err := s.c.GetOrCreate(vardelay.TypeNodeName, name, func(c *conf.RawConf) (interface{}, error) {
+		if c.IsEmpty() {
+			return nil, fmt.Errorf("节点/%s/%s未配置，或不可用", vardelay.TypeNodeName, name)
+		}
+		varConf, err := app.Cache.GetVarConf()
+		if err != nil {
+			return nil, err
+		}
+		dconf, err := vardelay.GetConf(varConf, name)
+		if err != nil {
+			return nil, err
+		}
+		store, err := newStore(s.c, name, dconf)
+		if err != nil {
+			return nil, err
+		}
+		return newDelay(store, dconf), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*delay), nil
+}
+
+//delay 延迟任务
+type delay struct {
+	store IStore
+	conf  *vardelay.Delay
+}
+
+func newDelay(store IStore, conf *vardelay.Delay) *delay {
+	return &delay{store: store, conf: conf}
+}
+
+//At 在指定时间执行服务
+func (d *delay) At(service string, at time.Time, params map[string]interface{}, opts ...Option) (string, error) {
+	if service == "" {
+		return "", errors.New("延迟任务的服务名不能为空")
+	}
+	job := &Job{
+		ID:      utility.GetGUID(),
+		Service: service,
+		Params:  params,
+		Header:  map[string]string{context.XRequestID: context.GetRequestID()},
+		At:      at.Unix(),
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+	return d.store.Add(job)
+}
+
+//After 延迟指定时长后执行服务
+func (d *delay) After(service string, t time.Duration, params map[string]interface{}, opts ...Option) (string, error) {
+	return d.At(service, time.Now().Add(t), params, opts...)
+}
+
+//Cancel 取消未执行的任务
+func (d *delay) Cancel(id string) error {
+	return d.store.Remove(id)
+}
+
+//Claim 获取到期的任务
+func (d *delay) Claim(limit int) ([]*Job, error) {
+	return d.store.Claim(time.Now(), time.Duration(d.conf.Timeout)*time.Second, limit)
+}
+
+//Done 任务执行成功，移除任务
+func (d *delay) Done(job *Job) error {
+	return d.store.Remove(job.ID)
+}
+
+//Fail 任务执行失败，按退避策略重试
+func (d *delay) Fail(job *Job, reason string) (bool, error) {
+	job.Attempts++
+	job.Error = reason
+	retry := job.Retry
+	if retry <= 0 {
+		retry = d.conf.Retry
+	}
+	if job.Attempts > retry {
+		return false, d.store.Remove(job.ID)
+	}
+	job.At = time.Now().Add(getBackoff(d.conf.Backoff, job.Attempts)).Unix()
+	return true, d.store.Save(job)
+}
+
+//getBackoff 获取第n次重试的间隔，每次加倍，最大不超过maxBackoff
+func getBackoff(backoff int, n int) time.Duration {
+	d := time.Duration(backoff) * time.Second
+	for i := 1; i < n && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
@@ -0,0 +1,115 @@
+package delay
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	vardelay "github.com/micro-plat/hydra/conf/vars/delay"
+	"github.com/micro-plat/hydra/registry"
+	"github.com/micro-plat/lib4go/logger"
+
+	_ "github.com/micro-plat/hydra/registry/registry/localmemory"
+)
+
+func TestGetBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff int
+		n       int
+		want    time.Duration
+	}{
+		{name: "1. 首次重试", backoff: 10, n: 1, want: 10 * time.Second},
+		{name: "2. 第三次重试", backoff: 10, n: 3, want: 40 * time.Second},
+		{name: "3. 超过最大间隔", backoff: 10, n: 20, want: maxBackoff},
+	}
+	for _, tt := range tests {
+		if got := getBackoff(tt.backoff, tt.n); got != tt.want {
+			t.Errorf("%s:got %v,want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func newTestDelay(t *testing.T) *delay {
+	r, err := registry.GetRegistry("lm://.", logger.New("hydra"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := registry.Join("delay_test", fmt.Sprint(time.Now().UnixNano()))
+	return newDelay(newRegistryStore(r, root), vardelay.New(vardelay.ProtoRegistry, vardelay.WithRetry(1, 10)))
+}
+
+func TestDelay_Claim(t *testing.T) {
+	d := newTestDelay(t)
+	due, err := d.At("/order/close", time.Now().Add(-time.Second), map[string]interface{}{"id": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.After("/order/close", time.Hour, nil); err != nil {
+		t.Fatal(err)
+	}
+	jobs, err := d.Claim(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != due || jobs[0].Params["id"] != float64(1) {
+		t.Fatalf("应只获取到期的任务:%+v", jobs)
+	}
+	if jobs, _ := d.Claim(10); len(jobs) != 0 {
+		t.Errorf("已获取的任务在超时前不应再次获取:%+v", jobs)
+	}
+	if err := d.Done(jobs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := d.store.(*registryStore).registry.Exists(registry.Join(d.store.(*registryStore).jobs, due)); ok {
+		t.Error("执行成功的任务应被移除")
+	}
+}
+
+func TestDelay_Key(t *testing.T) {
+	d := newTestDelay(t)
+	id1, err := d.After("/order/close", time.Minute, nil, WithKey("order-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id2, err := d.After("/order/close", time.Minute, nil, WithKey("order-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id1 != id2 {
+		t.Errorf("相同去重键应返回已有任务:%s,%s", id1, id2)
+	}
+	if err := d.Cancel(id1); err != nil {
+		t.Fatal(err)
+	}
+	id3, err := d.After("/order/close", time.Minute, nil, WithKey("order-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id3 == id1 {
+		t.Error("取消后应重新添加任务")
+	}
+}
+
+func TestDelay_Fail(t *testing.T) {
+	d := newTestDelay(t)
+	if _, err := d.At("/order/close", time.Now().Add(-time.Second), nil); err != nil {
+		t.Fatal(err)
+	}
+	jobs, err := d.Claim(10)
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("获取任务失败:%v,%d", err, len(jobs))
+	}
+	job := jobs[0]
+	retry, err := d.Fail(job, "timeout")
+	if err != nil || !retry {
+		t.Fatalf("首次失败应重试:%v,%v", retry, err)
+	}
+	if job.Attempts != 1 || job.At < time.Now().Add(9*time.Second).Unix() {
+		t.Errorf("重试时间有误:%+v", job)
+	}
+	retry, err = d.Fail(job, "timeout")
+	if err != nil || retry {
+		t.Errorf("超过最大重试次数后不应重试:%v,%v", retry, err)
+	}
+}
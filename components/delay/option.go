@@ -0,0 +1,25 @@
+package delay
+
+//Option 延迟任务选项
+type Option func(*Job)
+
+//WithKey 设置去重键，相同去重键的任务未执行前不重复添加
+func WithKey(key string) Option {
+	return func(j *Job) {
+		j.Key = key
+	}
+}
+
+//WithRetry 设置执行失败后的最大重试次数，未设置时使用配置的重试次数
+func WithRetry(max int) Option {
+	return func(j *Job) {
+		j.Retry = max
+	}
+}
+
+//WithHeader 设置执行任务时传入的头信息
+func WithHeader(name string, value string) Option {
+	return func(j *Job) {
+		j.Header[name] = value
+	}
+}
@@ -0,0 +1,128 @@
+package delay
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/micro-plat/hydra/components/container"
+	"github.com/micro-plat/hydra/components/dbs"
+)
+
+//dbStore 数据库存储，领取任务时按原执行时间更新，保证同一任务只被一个节点领取
+type dbStore struct {
+	db          dbs.IComponentDB
+	name        string
+	get         string
+	insert      string
+	insertNoKey string
+	update      string
+	claim       string
+	remove      string
+	due         string
+}
+
+func newDBStore(c container.IContainer, name string, table string) *dbStore {
+	return &dbStore{
+		db:          dbs.NewStandardDB(c),
+		name:        name,
+		get:         fmt.Sprintf(`select id from %s where job_key=@key`, table),
+		insert:      fmt.Sprintf(`insert into %s(id,job_key,at,content) values(@id,@key,@at,@content)`, table),
+		insertNoKey: fmt.Sprintf(`insert into %s(id,at,content) values(@id,@at,@content)`, table),
+		update:      fmt.Sprintf(`update %s set at=@at,content=@content where id=@id`, table),
+		claim:       fmt.Sprintf(`update %s set at=@next where id=@id and at=@at`, table),
+		remove:      fmt.Sprintf(`delete from %s where id=@id`, table),
+		due:         fmt.Sprintf(`select id,at,content from %s where at<=@now order by at`, table),
+	}
+}
+
+//Add 添加任务，job_key需建立唯一索引，未指定任务标识时job_key保存为NULL，不受唯一索引限制
+func (s *dbStore) Add(job *Job) (string, error) {
+	db, err := s.db.GetDB(s.name)
+	if err != nil {
+		return "", err
+	}
+	if job.Key != "" {
+		id, _, _, err := db.Scalar(s.get, map[string]interface{}{"key": job.Key})
+		if err != nil {
+			return "", err
+		}
+		if id := fmt.Sprint(id); id != "" && id != "<nil>" {
+			return id, nil
+		}
+	}
+	content, err := marshal(job)
+	if err != nil {
+		return "", err
+	}
+	insert := s.insert
+	if job.Key == "" {
+		insert = s.insertNoKey
+	}
+	_, _, _, err = db.Execute(insert, map[string]interface{}{
+		"id":      job.ID,
+		"key":     job.Key,
+		"at":      job.At,
+		"content": content,
+	})
+	if err != nil {
+		return "", fmt.Errorf("添加延迟任务失败:%w", err)
+	}
+	return job.ID, nil
+}
+
+//Save 保存任务
+func (s *dbStore) Save(job *Job) error {
+	db, err := s.db.GetDB(s.name)
+	if err != nil {
+		return err
+	}
+	content, err := marshal(job)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = db.Execute(s.update, map[string]interface{}{"id": job.ID, "at": job.At, "content": content})
+	return err
+}
+
+//Remove 移除任务
+func (s *dbStore) Remove(id string) error {
+	db, err := s.db.GetDB(s.name)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = db.Execute(s.remove, map[string]interface{}{"id": id})
+	return err
+}
+
+//Claim 获取到期的任务
+func (s *dbStore) Claim(now time.Time, timeout time.Duration, limit int) ([]*Job, error) {
+	db, err := s.db.GetDB(s.name)
+	if err != nil {
+		return nil, err
+	}
+	rows, _, _, err := db.Query(s.due, map[string]interface{}{"now": now.Unix()})
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*Job, 0, limit)
+	for i := 0; i < rows.Len() && len(jobs) < limit; i++ {
+		row := rows.Get(i)
+		n, _, _, err := db.Execute(s.claim, map[string]interface{}{
+			"id":   row.GetString("id"),
+			"at":   row.GetInt64("at"),
+			"next": now.Add(timeout).Unix(),
+		})
+		if err != nil {
+			return jobs, err
+		}
+		if n == 0 {
+			continue
+		}
+		job, err := unmarshal([]byte(row.GetString("content")))
+		if err != nil {
+			return jobs, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,53 @@
+package delay
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/micro-plat/hydra/components/container"
+	vardelay "github.com/micro-plat/hydra/conf/vars/delay"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/hydra/registry"
+)
+
+//IStore 延迟任务存储
+type IStore interface {
+	//Add 添加任务，指定去重键且相同任务未执行时返回已有的任务编号
+	Add(job *Job) (string, error)
+
+	//Save 保存任务的执行时间、重试次数等信息
+	Save(job *Job) error
+
+	//Remove 移除任务
+	Remove(id string) error
+
+	//Claim 获取now之前到期的任务，并将其执行时间推迟timeout，避免被其它节点重复获取
+	Claim(now time.Time, timeout time.Duration, limit int) ([]*Job, error)
+}
+
+//newStore 根据配置构建延迟任务存储
+func newStore(c container.IContainer, name string, conf *vardelay.Delay) (IStore, error) {
+	switch conf.Proto {
+	case vardelay.ProtoRedis:
+		return newRedisStore(conf.Redis, fmt.Sprintf("%s:%s", conf.Key, name))
+	case vardelay.ProtoDB:
+		return newDBStore(c, conf.DB, conf.Table), nil
+	case vardelay.ProtoRegistry:
+		return newRegistryStore(registry.GetCurrent(), registry.Join(global.Def.PlatName, vardelay.TypeNodeName, name)), nil
+	}
+	return nil, fmt.Errorf("不支持的延迟任务存储方式:%s", conf.Proto)
+}
+
+func marshal(job *Job) (string, error) {
+	buff, err := json.Marshal(job)
+	return string(buff), err
+}
+
+func unmarshal(buff []byte) (*Job, error) {
+	job := &Job{}
+	if err := json.Unmarshal(buff, job); err != nil {
+		return nil, fmt.Errorf("延迟任务数据有误:%w", err)
+	}
+	return job, nil
+}
@@ -0,0 +1,137 @@
+package delay
+
+import (
+	"fmt"
+	"time"
+
+	rds "github.com/go-redis/redis"
+	"github.com/micro-plat/hydra/components/pkgs/redis"
+	"github.com/micro-plat/hydra/conf/app"
+	varredis "github.com/micro-plat/hydra/conf/vars/redis"
+)
+
+//addScript 添加任务，KEYS:任务集合,任务数据,去重键 ARGV:编号,数据,执行时间,去重键
+var addScript = rds.NewScript(`
+if ARGV[4] ~= '' then
+	local old = redis.call('HGET', KEYS[3], ARGV[4])
+	if old and redis.call('HEXISTS', KEYS[2], old) == 1 then
+		return old
+	end
+	redis.call('HSET', KEYS[3], ARGV[4], ARGV[1])
+end
+redis.call('HSET', KEYS[2], ARGV[1], ARGV[2])
+redis.call('ZADD', KEYS[1], ARGV[3], ARGV[1])
+return ARGV[1]`)
+
+//removeScript 移除任务，KEYS:任务集合,任务数据,去重键 ARGV:编号
+var removeScript = rds.NewScript(`
+local v = redis.call('HGET', KEYS[2], ARGV[1])
+if v then
+	local job = cjson.decode(v)
+	if job.key and redis.call('HGET', KEYS[3], job.key) == ARGV[1] then
+		redis.call('HDEL', KEYS[3], job.key)
+	end
+end
+redis.call('HDEL', KEYS[2], ARGV[1])
+redis.call('ZREM', KEYS[1], ARGV[1])
+return 1`)
+
+//claimScript 获取到期任务并推迟执行时间，KEYS:任务集合 ARGV:当前时间,推迟后的时间,数量
+var claimScript = rds.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[3])
+for _, id in ipairs(ids) do
+	redis.call('ZADD', KEYS[1], ARGV[2], id)
+end
+return ids`)
+
+//redisStore redis存储，任务编号按执行时间保存在有序集合中，任务数据保存在hash中，
+//键名使用相同的hash tag，集群模式下脚本操作的键位于同一分片
+type redisStore struct {
+	client *redis.Client
+	keys   []string
+}
+
+func newRedisStore(name string, prefix string) (*redisStore, error) {
+	varConf, err := app.Cache.GetVarConf()
+	if err != nil {
+		return nil, err
+	}
+	conf, err := varredis.GetConf(varConf, name)
+	if err != nil {
+		return nil, err
+	}
+	client, err := redis.NewByConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	return &redisStore{
+		client: client,
+		keys:   []string{"{" + prefix + "}:jobs", "{" + prefix + "}:data", "{" + prefix + "}:keys"},
+	}, nil
+}
+
+//Add 添加任务
+func (s *redisStore) Add(job *Job) (string, error) {
+	value, err := marshal(job)
+	if err != nil {
+		return "", err
+	}
+	id, err := addScript.Run(s.client, s.keys, job.ID, value, job.At, job.Key).String()
+	if err != nil {
+		return "", fmt.Errorf("添加延迟任务失败:%w", err)
+	}
+	return id, nil
+}
+
+//Save 保存任务
+func (s *redisStore) Save(job *Job) error {
+	value, err := marshal(job)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.TxPipelined(func(p rds.Pipeliner) error {
+		p.HSet(s.keys[1], job.ID, value)
+		p.ZAdd(s.keys[0], rds.Z{Score: float64(job.At), Member: job.ID})
+		return nil
+	})
+	return err
+}
+
+//Remove 移除任务
+func (s *redisStore) Remove(id string) error {
+	return removeScript.Run(s.client, s.keys, id).Err()
+}
+
+//Claim 获取到期的任务
+func (s *redisStore) Claim(now time.Time, timeout time.Duration, limit int) ([]*Job, error) {
+	ids, err := claimScript.Run(s.client, s.keys[:1], now.Unix(), now.Add(timeout).Unix(), limit).Result()
+	if err != nil {
+		return nil, err
+	}
+	list, _ := ids.([]interface{})
+	if len(list) == 0 {
+		return nil, nil
+	}
+	fields := make([]string, 0, len(list))
+	for _, id := range list {
+		fields = append(fields, fmt.Sprint(id))
+	}
+	values, err := s.client.HMGet(s.keys[1], fields...).Result()
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*Job, 0, len(values))
+	for i, v := range values {
+		str, ok := v.(string)
+		if !ok {
+			s.client.ZRem(s.keys[0], fields[i])
+			continue
+		}
+		job, err := unmarshal([]byte(str))
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,109 @@
+package delay
+
+import (
+	"sort"
+	"time"
+
+	"github.com/micro-plat/hydra/registry"
+)
+
+//registryStore 注册中心存储，任务保存在根路径/jobs/任务编号节点，去重键保存在根路径/keys/去重键节点，
+//领取任务时不能保证原子性，多个节点可能重复执行同一任务，适用于任务较少的场景
+type registryStore struct {
+	registry registry.IRegistry
+	jobs     string
+	keys     string
+}
+
+func newRegistryStore(r registry.IRegistry, root string) *registryStore {
+	return &registryStore{
+		registry: r,
+		jobs:     registry.Join(root, "jobs"),
+		keys:     registry.Join(root, "keys"),
+	}
+}
+
+//Add 添加任务
+func (s *registryStore) Add(job *Job) (string, error) {
+	if job.Key != "" {
+		path := registry.Join(s.keys, job.Key)
+		if ok, _ := s.registry.Exists(path); ok {
+			id, _, err := s.registry.GetValue(path)
+			if err != nil {
+				return "", err
+			}
+			if ok, _ := s.registry.Exists(registry.Join(s.jobs, string(id))); ok {
+				return string(id), nil
+			}
+			s.registry.Delete(path)
+		}
+		if err := s.registry.CreatePersistentNode(path, job.ID); err != nil {
+			return "", err
+		}
+	}
+	value, err := marshal(job)
+	if err != nil {
+		return "", err
+	}
+	if err := s.registry.CreatePersistentNode(registry.Join(s.jobs, job.ID), value); err != nil {
+		return "", err
+	}
+	return job.ID, nil
+}
+
+//Save 保存任务
+func (s *registryStore) Save(job *Job) error {
+	value, err := marshal(job)
+	if err != nil {
+		return err
+	}
+	return s.registry.Update(registry.Join(s.jobs, job.ID), value)
+}
+
+//Remove 移除任务
+func (s *registryStore) Remove(id string) error {
+	path := registry.Join(s.jobs, id)
+	if ok, _ := s.registry.Exists(path); !ok {
+		return nil
+	}
+	buff, _, err := s.registry.GetValue(path)
+	if err != nil {
+		return err
+	}
+	if job, err := unmarshal(buff); err == nil && job.Key != "" {
+		s.registry.Delete(registry.Join(s.keys, job.Key))
+	}
+	return s.registry.Delete(path)
+}
+
+//Claim 获取到期的任务
+func (s *registryStore) Claim(now time.Time, timeout time.Duration, limit int) ([]*Job, error) {
+	ids, _, err := s.registry.GetChildren(s.jobs)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*Job, 0, limit)
+	for _, id := range ids {
+		buff, _, err := s.registry.GetValue(registry.Join(s.jobs, id))
+		if err != nil {
+			continue
+		}
+		job, err := unmarshal(buff)
+		if err != nil || job.At > now.Unix() {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].At < jobs[j].At })
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	for _, job := range jobs {
+		next := *job
+		next.At = now.Add(timeout).Unix()
+		if err := s.Save(&next); err != nil {
+			return nil, err
+		}
+	}
+	return jobs, nil
+}
@@ -20,6 +20,7 @@ type DLock struct {
 	done      bool
 	closeChan chan struct{}
 	master    bool
+	owned     bool
 }
 
 //NewLock 构建分布式锁
@@ -28,10 +29,12 @@ func NewLock(lockName string, registryAddr string, l logger.ILogging) (lk *DLock
 	if err != nil {
 		return nil, err
 	}
-	return NewLockByRegistry(lockName, r), nil
+	lk = NewLockByRegistry(lockName, r)
+	lk.owned = true
+	return lk, nil
 }
 
-//NewLockByRegistry 根据当前注册中心创建分布式锁
+//NewLockByRegistry 根据当前注册中心创建分布式锁，释放锁时不关闭注册中心
 func NewLockByRegistry(lockName string, r registry.IRegistry) (lk *DLock) {
 	lk = &DLock{name: lockName, registry: r, closeChan: make(chan struct{})}
 	return lk
@@ -126,7 +129,9 @@ func (d *DLock) Unlock() {
 	d.done = true
 	close(d.closeChan)
 	d.registry.Delete(d.path)
-	d.registry.Close()
+	if d.owned {
+		d.registry.Close()
+	}
 }
 
 func isMaster(path string, root string, cldrs []string) bool {
@@ -0,0 +1,122 @@
+/*
+业务统计，与服务器metric中间件共用统计器及上报服务(influxdb,graphite,opentsdb,statsd等)。
+服务器未启用metric配置时返回空统计器，不进行统计。
+*/
+
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/micro-plat/hydra/components/pkgs/metrics"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/lib4go/concurrent/cmap"
+)
+
+//registries 各服务器类型对应的统计器
+var registries = cmap.New(2)
+
+//Register 设置服务器类型对应的统计器
+func Register(serverType string, r metrics.Registry) {
+	registries.Set(serverType, r)
+}
+
+//Unregister 移除服务器类型对应的统计器
+func Unregister(serverType string) {
+	registries.Remove(serverType)
+}
+
+//GetRegistry 获取服务器类型对应的统计器
+func GetRegistry(serverType string) (metrics.Registry, bool) {
+	r, ok := registries.Get(serverType)
+	if !ok {
+		return nil, false
+	}
+	return r.(metrics.Registry), true
+}
+
+//IMetric 业务统计，labels为成对的标签名、标签值，标签未成对或标签名为空时panic
+type IMetric interface {
+	//Counter 计数器，如：正在处理的订单数
+	Counter(name string, labels ...string) metrics.Counter
+
+	//Gauge 瞬时值，如：队列长度
+	Gauge(name string, labels ...string) metrics.Gauge
+
+	//Histogram 数值分布，如：支付渠道的处理时长
+	Histogram(name string, labels ...string) metrics.Histogram
+
+	//Meter 速率，如：创建的订单数
+	Meter(name string, labels ...string) metrics.Meter
+}
+
+//Metric 业务统计
+type Metric struct {
+	serverType string
+	serverName string
+}
+
+//New 构建服务器的业务统计
+func New(serverType string, serverName string) *Metric {
+	return &Metric{serverType: serverType, serverName: serverName}
+}
+
+//Counter 获取计数器
+func (m *Metric) Counter(name string, labels ...string) metrics.Counter {
+	checkLabels(name, labels)
+	r, ok := GetRegistry(m.serverType)
+	if !ok {
+		return metrics.NilCounter{}
+	}
+	return metrics.GetOrRegisterCounter(m.makeName(name, metrics.COUNTER, labels...), r)
+}
+
+//Gauge 获取瞬时值统计器
+func (m *Metric) Gauge(name string, labels ...string) metrics.Gauge {
+	checkLabels(name, labels)
+	r, ok := GetRegistry(m.serverType)
+	if !ok {
+		return metrics.NilGauge{}
+	}
+	return metrics.GetOrRegisterGauge(m.makeName(name, metrics.GAUGE, labels...), r)
+}
+
+//Histogram 获取数值分布统计器
+func (m *Metric) Histogram(name string, labels ...string) metrics.Histogram {
+	checkLabels(name, labels)
+	r, ok := GetRegistry(m.serverType)
+	if !ok {
+		return metrics.NilHistogram{}
+	}
+	return r.GetOrRegister(m.makeName(name, metrics.HISTOGRAM, labels...), func() metrics.Histogram {
+		return metrics.NewHistogram(metrics.NewExpDecaySample(1028, 0.015))
+	}).(metrics.Histogram)
+}
+
+//Meter 获取速率统计器
+func (m *Metric) Meter(name string, labels ...string) metrics.Meter {
+	checkLabels(name, labels)
+	r, ok := GetRegistry(m.serverType)
+	if !ok {
+		return metrics.NilMeter{}
+	}
+	return metrics.GetOrRegisterMeter(m.makeName(name, metrics.METER, labels...), r)
+}
+
+//makeName 构建统计项名称，添加服务器名称及主机标签
+func (m *Metric) makeName(name string, tp string, labels ...string) string {
+	tags := append([]string{"server", m.serverName, "host", global.LocalIP()}, labels...)
+	return metrics.MakeName(m.serverType+".biz."+name, tp, tags...)
+}
+
+//checkLabels 检查标签是否成对设置，未启用统计时同样检查，避免启用后才出现错误
+func checkLabels(name string, labels []string) {
+	if len(labels)%2 != 0 {
+		panic(fmt.Errorf("统计项%s的标签必须成对设置:%v", name, labels))
+	}
+	for i := 0; i < len(labels); i += 2 {
+		if labels[i] == "" {
+			panic(fmt.Errorf("统计项%s的标签名不能为空:%v", name, labels))
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/micro-plat/hydra/components/pkgs/metrics"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestMetric_Disabled(t *testing.T) {
+	m := New("api", "order")
+	m.Counter("order.created", "channel", "alipay").Inc(1)
+	m.Meter("order.created").Mark(1)
+	m.Histogram("pay.latency").Update(10)
+	m.Gauge("queue.length").Update(5)
+	if _, ok := m.Counter("order.created").(metrics.NilCounter); !ok {
+		t.Error("未注册统计器时应返回空计数器")
+	}
+}
+
+func TestMetric_Register(t *testing.T) {
+	r := metrics.NewRegistry()
+	Register("api", r)
+	defer Unregister("api")
+
+	m := New("api", "order")
+	m.Counter("order.created", "channel", "alipay").Inc(2)
+	m.Counter("order.created", "channel", "alipay").Inc(1)
+	m.Counter("order.created", "channel", "wxpay").Inc(1)
+	m.Meter("order.paid").Mark(3)
+	for i := int64(1); i <= 10; i++ {
+		m.Histogram("pay.latency", "channel", "alipay").Update(i)
+	}
+	m.Gauge("queue.length").Update(7)
+
+	if c := m.Counter("order.created", "channel", "alipay").Count(); c != 3 {
+		t.Errorf("计数器的值为%d,应为3", c)
+	}
+	if c := m.Counter("order.created", "channel", "wxpay").Count(); c != 1 {
+		t.Errorf("不同标签的计数器值为%d,应为1", c)
+	}
+	if c := m.Meter("order.paid").Count(); c != 3 {
+		t.Errorf("速率统计器的次数为%d,应为3", c)
+	}
+	if c := m.Histogram("pay.latency", "channel", "alipay").Count(); c != 10 {
+		t.Errorf("数值分布统计器的次数为%d,应为10", c)
+	}
+	if v := m.Gauge("queue.length").Value(); v != 7 {
+		t.Errorf("瞬时值为%d,应为7", v)
+	}
+	count := 0
+	r.Each(func(name string, i interface{}) { count++ })
+	if count != 5 {
+		t.Errorf("统计项数量为%d,应为5", count)
+	}
+
+	Unregister("api")
+	if _, ok := m.Meter("order.paid").(metrics.NilMeter); !ok {
+		t.Error("移除统计器后应返回空统计器")
+	}
+}
+
+func TestMetric_Labels(t *testing.T) {
+	m := New("api", "order")
+	assert.Panics(t, func() { m.Counter("order.created", "channel") }, "1. 标签未成对")
+	assert.Panics(t, func() { m.Meter("order.created", "", "alipay") }, "2. 标签名为空")
+	assert.NotPanics(t, func() { m.Gauge("queue.length", "channel", "alipay") }, "3. 标签成对")
+}
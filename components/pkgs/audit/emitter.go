@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/micro-plat/lib4go/logger"
+)
+
+//reportInterval 丢弃数量的输出周期
+const reportInterval = time.Minute
+
+//Emitter 异步输出审计事件，缓冲区满时丢弃事件，保证不阻塞请求处理
+type Emitter struct {
+	writer  IWriter
+	queue   chan *Event
+	closeCh chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	dropped uint64
+	logger  logger.ILogging
+}
+
+//NewEmitter 构建审计事件输出器，buffer为等待输出的最大事件数
+func NewEmitter(w IWriter, buffer int, l logger.ILogging) *Emitter {
+	e := &Emitter{
+		writer:  w,
+		queue:   make(chan *Event, buffer),
+		closeCh: make(chan struct{}),
+		done:    make(chan struct{}),
+		logger:  l,
+	}
+	go e.loop()
+	return e
+}
+
+//Emit 提交审计事件，缓冲区已满或已关闭时丢弃并返回false
+func (e *Emitter) Emit(ev *Event) bool {
+	select {
+	case <-e.closeCh:
+		atomic.AddUint64(&e.dropped, 1)
+		return false
+	default:
+	}
+	select {
+	case e.queue <- ev:
+		return true
+	default:
+		atomic.AddUint64(&e.dropped, 1)
+		return false
+	}
+}
+
+//Dropped 已丢弃的事件数
+func (e *Emitter) Dropped() uint64 {
+	return atomic.LoadUint64(&e.dropped)
+}
+
+//Close 输出剩余的事件并关闭输出
+func (e *Emitter) Close() error {
+	e.once.Do(func() {
+		close(e.closeCh)
+		<-e.done
+	})
+	return e.writer.Close()
+}
+
+func (e *Emitter) loop() {
+	defer close(e.done)
+	tk := time.NewTicker(reportInterval)
+	defer tk.Stop()
+	var reported uint64
+	for {
+		select {
+		case ev := <-e.queue:
+			e.write(ev)
+		case <-tk.C:
+			if n := e.Dropped(); n != reported {
+				e.logger.Warnf("审计缓冲区已满，累计丢弃%d条审计事件", n)
+				reported = n
+			}
+		case <-e.closeCh:
+			for {
+				select {
+				case ev := <-e.queue:
+					e.write(ev)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *Emitter) write(ev *Event) {
+	buff, err := json.Marshal(ev)
+	if err != nil {
+		e.logger.Errorf("审计事件转换失败:%s %v", ev.RequestID, err)
+		return
+	}
+	if err := e.writer.Write(buff); err != nil {
+		e.logger.Errorf("输出审计事件失败:%s %v", ev.RequestID, err)
+	}
+}
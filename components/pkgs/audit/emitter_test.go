@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro-plat/lib4go/logger"
+)
+
+func TestEmitter(t *testing.T) {
+	var lock sync.Mutex
+	list := make([]string, 0, 2)
+	block := make(chan struct{})
+	w := WriterFunc(func(p []byte) error {
+		<-block
+		lock.Lock()
+		defer lock.Unlock()
+		list = append(list, string(p))
+		return nil
+	})
+	e := NewEmitter(w, 1, logger.New("audit"))
+
+	//输出协程阻塞时，超出缓冲区的事件被丢弃
+	e.Emit(&Event{RequestID: "1"})
+	time.Sleep(10 * time.Millisecond)
+	if !e.Emit(&Event{RequestID: "2"}) {
+		t.Fatal("缓冲区未满,事件应提交成功")
+	}
+	if e.Emit(&Event{RequestID: "3"}) {
+		t.Fatal("缓冲区已满,事件应被丢弃")
+	}
+	close(block)
+	e.Close()
+	if e.Dropped() != 1 {
+		t.Fatalf("丢弃数量有误:%d", e.Dropped())
+	}
+	if len(list) != 2 {
+		t.Fatalf("输出数量有误:%v", list)
+	}
+	ev := &Event{}
+	if err := json.Unmarshal([]byte(list[1]), ev); err != nil || ev.RequestID != "2" {
+		t.Fatalf("输出内容有误:%s %v", list[1], err)
+	}
+	if e.Emit(&Event{RequestID: "4"}) {
+		t.Fatal("已关闭,事件应被丢弃")
+	}
+}
+
+func TestFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	w := NewFile(filepath.Join(dir, "audit", "%date.log"))
+	w.Write([]byte(`{"request_id":"1"}`))
+	w.Write([]byte(`{"request_id":"2"}`))
+	w.Close()
+
+	buff, err := ioutil.ReadFile(filepath.Join(dir, "audit", time.Now().Format("20060102")+".log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(buff)), "\n"); len(lines) != 2 {
+		t.Fatalf("文件内容有误:%s", buff)
+	}
+}
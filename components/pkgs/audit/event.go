@@ -0,0 +1,17 @@
+package audit
+
+import "time"
+
+//Event 审计事件
+type Event struct {
+	Time       time.Time              `json:"time"`
+	RequestID  string                 `json:"request_id"`
+	ServerType string                 `json:"server_type"`
+	Service    string                 `json:"service"`
+	Method     string                 `json:"method,omitempty"`
+	ClientIP   string                 `json:"client_ip,omitempty"`
+	User       string                 `json:"user,omitempty"`
+	Params     map[string]interface{} `json:"params,omitempty"`
+	Status     int                    `json:"status"`
+	Latency    int64                  `json:"latency"`
+}
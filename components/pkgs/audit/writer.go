@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+//IWriter 审计事件输出
+type IWriter interface {
+	Write(p []byte) error
+	Close() error
+}
+
+//WriterFunc 使用函数输出审计事件
+type WriterFunc func(p []byte) error
+
+//Write 输出审计事件
+func (f WriterFunc) Write(p []byte) error {
+	return f(p)
+}
+
+//Close 关闭输出
+func (f WriterFunc) Close() error {
+	return nil
+}
+
+//file 按日期滚动的本地文件
+type file struct {
+	path    string
+	current string
+	f       *os.File
+	lock    sync.Mutex
+}
+
+//NewFile 构建本地文件输出，路径中的%date替换为当前日期(20060102)，日期变化后写入新的文件
+func NewFile(path string) IWriter {
+	return &file{path: path}
+}
+
+//Write 写入一行审计事件
+func (f *file) Write(p []byte) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	name := strings.Replace(f.path, "%date", time.Now().Format("20060102"), -1)
+	if f.f == nil || name != f.current {
+		if err := f.open(name); err != nil {
+			return err
+		}
+	}
+	if _, err := f.f.Write(append(p, '\n')); err != nil {
+		return fmt.Errorf("写入审计文件失败:%s %w", f.current, err)
+	}
+	return nil
+}
+
+//Close 关闭文件
+func (f *file) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.f == nil {
+		return nil
+	}
+	err := f.f.Close()
+	f.f = nil
+	return err
+}
+
+func (f *file) open(name string) error {
+	if f.f != nil {
+		f.f.Close()
+		f.f = nil
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return fmt.Errorf("创建审计文件目录失败:%s %w", name, err)
+	}
+	nf, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("打开审计文件失败:%s %w", name, err)
+	}
+	f.f = nf
+	f.current = name
+	return nil
+}
@@ -16,8 +16,8 @@ func GetStringByHeader(content interface{}, hd ...string) string {
 
 	header := make(map[string]string, 0)
 	if len(hd)%2 == 0 {
-		for i := 0; i < len(hd)/2; i++ {
-			header[fmt.Sprint(hd[i])] = hd[i+1]
+		for i := 0; i < len(hd); i += 2 {
+			header[hd[i]] = hd[i+1]
 		}
 	}
 
@@ -13,7 +13,6 @@ import (
 
 	varhttp "github.com/micro-plat/hydra/conf/vars/http"
 	"github.com/micro-plat/hydra/context"
-	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/lib4go/encoding"
 )
 
@@ -39,7 +38,25 @@ func (c *Client) Request(method string, url string, params string, charset strin
 		req.Header.Set(i, strings.Join(v, ","))
 	}
 
-	req.Header.Set(context.XRequestID, global.RID.GetXRequestID())
+	req.Header.Set(context.XRequestID, context.GetRequestID())
+
+	//记录请求耗时
+	profiling := context.Profiling(context.KindHTTP, method+" "+req.URL.Host+req.URL.Path)
+	defer func() { profiling(err) }()
+
+	//处理链路跟踪
+	if span := context.StartSpan("HTTP " + method); span != nil {
+		defer func() {
+			span.SetTag("http.status_code", status)
+			span.SetError(err)
+			span.End()
+		}()
+		span.SetTag("http.method", method)
+		span.SetTag("http.url", req.URL.String())
+		for k, v := range span.GetHeaders() {
+			req.Header.Set(k, v)
+		}
+	}
 	c.Response, err = c.client.Do(req)
 	if c.Response != nil {
 		defer c.Response.Body.Close()
@@ -100,13 +117,13 @@ func getCharset(charset ...string) (encoding string) {
 	return "UTF-8"
 }
 func (c *Client) printRequest(r ...interface{}) {
-	c.print(context.Current().Log().Debug, " > http request:", r...)
+	c.print(context.GetLogger().Debug, " > http request:", r...)
 }
 func (c *Client) printResponse(r ...interface{}) {
-	c.print(context.Current().Log().Debug, " > http response:", r...)
+	c.print(context.GetLogger().Debug, " > http response:", r...)
 }
 func (c *Client) printResponseError(r ...interface{}) {
-	c.print(context.Current().Log().Error, " > http response:", r...)
+	c.print(context.GetLogger().Error, " > http response:", r...)
 }
 
 func (c *Client) print(p func(...interface{}), h string, r ...interface{}) {
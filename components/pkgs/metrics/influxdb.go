@@ -143,8 +143,18 @@ LOOP:
 }
 
 func (r *reporter) send() error {
+	bps := influxdb.BatchPoints{
+		Points:   makePoints(r.reg),
+		Database: r.database,
+	}
+	_, err := r.client.Write(bps)
+	return err
+}
+
+//makePoints 将统计器中的数据转换为influxdb数据点
+func makePoints(reg metrics.Registry) []influxdb.Point {
 	var pts []influxdb.Point
-	r.reg.Each(func(name string, obj interface{}) {
+	reg.Each(func(name string, obj interface{}) {
 		now := time.Now()
 		rname, tags := splitGroup(name)
 		switch metric := obj.(type) {
@@ -254,13 +264,7 @@ func (r *reporter) send() error {
 			})
 		}
 	})
-
-	bps := influxdb.BatchPoints{
-		Points:   pts,
-		Database: r.database,
-	}
-	_, err := r.client.Write(bps)
-	return err
+	return pts
 }
 func (r *reporter) Close() error {
 	r.done = true
@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//PrometheusTextType prometheus文本格式
+	PrometheusTextType = "text/plain; version=0.0.4; charset=utf-8"
+
+	//OpenMetricsTextType OpenMetrics文本格式
+	OpenMetricsTextType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+//DefBuckets 默认的请求处理时长分布(秒)
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+//DefaultPrometheus 默认的prometheus统计器，同一进程的所有服务器共用
+var DefaultPrometheus = NewPrometheus(DefBuckets...)
+
+//Prometheus 按服务器类型、服务、请求方法、状态码统计请求数与处理时长，并以prometheus文本格式输出
+type Prometheus struct {
+	buckets  []float64
+	lock     sync.RWMutex
+	requests map[string]*promSeries
+	runtime  Registry
+	once     sync.Once
+}
+
+type promSeries struct {
+	lock    sync.Mutex
+	labels  string
+	count   uint64
+	sum     float64
+	buckets []uint64
+}
+
+//NewPrometheus 构建prometheus统计器
+func NewPrometheus(buckets ...float64) *Prometheus {
+	b := append([]float64{}, buckets...)
+	sort.Float64s(b)
+	return &Prometheus{
+		buckets:  b,
+		requests: make(map[string]*promSeries),
+		runtime:  NewRegistry(),
+	}
+}
+
+//Observe 记录一次请求的处理结果
+func (p *Prometheus) Observe(serverType string, service string, method string, status int, d time.Duration) {
+	labels := fmt.Sprintf(`server_type="%s",service="%s",method="%s",status="%d"`,
+		escapeLabel(serverType), escapeLabel(service), escapeLabel(method), status)
+	p.lock.RLock()
+	s, ok := p.requests[labels]
+	p.lock.RUnlock()
+	if !ok {
+		p.lock.Lock()
+		if s, ok = p.requests[labels]; !ok {
+			s = &promSeries{labels: labels, buckets: make([]uint64, len(p.buckets))}
+			p.requests[labels] = s
+		}
+		p.lock.Unlock()
+	}
+	v := d.Seconds()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.count++
+	s.sum += v
+	for i, b := range p.buckets {
+		if v <= b {
+			s.buckets[i]++
+		}
+	}
+}
+
+//Write 输出prometheus文本格式的统计数据，openMetrics为true时使用OpenMetrics格式
+func (p *Prometheus) Write(w io.Writer, openMetrics bool) error {
+	p.lock.RLock()
+	series := make([]*promSeries, 0, len(p.requests))
+	for _, s := range p.requests {
+		series = append(series, s)
+	}
+	p.lock.RUnlock()
+	sort.Slice(series, func(i, j int) bool { return series[i].labels < series[j].labels })
+
+	//1. 请求数
+	b := &strings.Builder{}
+	if openMetrics {
+		b.WriteString("# HELP hydra_requests 请求数\n# TYPE hydra_requests counter\n")
+	} else {
+		b.WriteString("# HELP hydra_requests_total 请求数\n# TYPE hydra_requests_total counter\n")
+	}
+	for _, s := range series {
+		s.lock.Lock()
+		fmt.Fprintf(b, "hydra_requests_total{%s} %d\n", s.labels, s.count)
+		s.lock.Unlock()
+	}
+
+	//2. 处理时长分布
+	b.WriteString("# HELP hydra_request_duration_seconds 请求处理时长(秒)\n# TYPE hydra_request_duration_seconds histogram\n")
+	for _, s := range series {
+		s.lock.Lock()
+		for i, le := range p.buckets {
+			fmt.Fprintf(b, "hydra_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", s.labels, formatFloat(le), s.buckets[i])
+		}
+		fmt.Fprintf(b, "hydra_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", s.labels, s.count)
+		fmt.Fprintf(b, "hydra_request_duration_seconds_sum{%s} %s\n", s.labels, formatFloat(s.sum))
+		fmt.Fprintf(b, "hydra_request_duration_seconds_count{%s} %d\n", s.labels, s.count)
+		s.lock.Unlock()
+	}
+
+	//3. 运行时指标
+	p.writeRuntime(b)
+	if openMetrics {
+		b.WriteString("# EOF\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+//writeRuntime 采集并输出go运行时指标
+func (p *Prometheus) writeRuntime(b *strings.Builder) {
+	p.once.Do(func() {
+		RegisterRuntimeMemStats(p.runtime)
+	})
+	CaptureRuntimeMemStatsOnce(p.runtime)
+	names := make([]string, 0, 32)
+	metrics := make(map[string]interface{})
+	p.runtime.Each(func(name string, i interface{}) {
+		names = append(names, name)
+		metrics[name] = i
+	})
+	sort.Strings(names)
+	for _, name := range names {
+		pname := "go_" + sanitizeName(name)
+		switch m := metrics[name].(type) {
+		case Gauge:
+			fmt.Fprintf(b, "# TYPE %s gauge\n%s %d\n", pname, pname, m.Value())
+		case GaugeFloat64:
+			fmt.Fprintf(b, "# TYPE %s gauge\n%s %s\n", pname, pname, formatFloat(m.Value()))
+		case Histogram:
+			h := m.Snapshot()
+			writeSummary(b, pname, h.Percentiles([]float64{0.5, 0.9, 0.99}), float64(h.Sum()), h.Count())
+		case Timer:
+			t := m.Snapshot()
+			writeSummary(b, pname, t.Percentiles([]float64{0.5, 0.9, 0.99}), float64(t.Sum()), t.Count())
+		}
+	}
+}
+
+func writeSummary(b *strings.Builder, name string, ps []float64, sum float64, count int64) {
+	fmt.Fprintf(b, "# TYPE %s summary\n", name)
+	for i, q := range []string{"0.5", "0.9", "0.99"} {
+		fmt.Fprintf(b, "%s{quantile=\"%s\"} %s\n", name, q, formatFloat(ps[i]))
+	}
+	fmt.Fprintf(b, "%s_sum %s\n%s_count %d\n", name, formatFloat(sum), name, count)
+}
+
+//sanitizeName 转换为prometheus支持的指标名称
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '_'
+	}, name)
+}
+
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func formatFloat(v float64) string {
+	return fmt.Sprintf("%g", v)
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusWrite(t *testing.T) {
+	p := NewPrometheus(0.1, 1)
+	p.Observe("api", "/order/create", "POST", 200, 50*time.Millisecond)
+	p.Observe("api", "/order/create", "POST", 200, 500*time.Millisecond)
+	b := &bytes.Buffer{}
+	if err := p.Write(b, false); err != nil {
+		t.Fatal(err)
+	}
+	s := b.String()
+	labels := `server_type="api",service="/order/create",method="POST",status="200"`
+	for _, line := range []string{
+		"hydra_requests_total{" + labels + "} 2",
+		"hydra_request_duration_seconds_bucket{" + labels + `,le="0.1"} 1`,
+		"hydra_request_duration_seconds_bucket{" + labels + `,le="1"} 2`,
+		"hydra_request_duration_seconds_bucket{" + labels + `,le="+Inf"} 2`,
+		"hydra_request_duration_seconds_count{" + labels + "} 2",
+		"go_runtime_numgoroutine ",
+	} {
+		if !strings.Contains(s, line) {
+			t.Fatalf("missing %s in:\n%s", line, s)
+		}
+	}
+	if strings.Contains(s, "# EOF") {
+		t.Fatal("unexpected # EOF")
+	}
+}
+
+func TestPrometheusWriteOpenMetrics(t *testing.T) {
+	p := NewPrometheus(DefBuckets...)
+	p.Observe("rpc", "/order/query", "GET", 500, time.Millisecond)
+	b := &bytes.Buffer{}
+	if err := p.Write(b, true); err != nil {
+		t.Fatal(err)
+	}
+	if s := b.String(); !strings.HasSuffix(s, "# EOF\n") || !strings.Contains(s, "# TYPE hydra_requests counter") {
+		t.Fatalf("invalid openmetrics output:\n%s", s)
+	}
+}
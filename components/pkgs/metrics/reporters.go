@@ -0,0 +1,304 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	uurl "net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro-plat/lib4go/logger"
+	"github.com/robfig/cron"
+)
+
+//cronReporter 按cron周期执行上报函数的上报服务
+type cronReporter struct {
+	name     string
+	schedule cron.Schedule
+	send     func() error
+	close    func() error
+	logger   *logger.Logger
+	closeCh  chan struct{}
+	once     sync.Once
+}
+
+func newCronReporter(name string, c string, l *logger.Logger, send func() error, close func() error) (*cronReporter, error) {
+	sch, err := cron.ParseStandard(c)
+	if err != nil {
+		return nil, fmt.Errorf("%s上报周期%s有误:%w", name, c, err)
+	}
+	return &cronReporter{
+		name:     name,
+		schedule: sch,
+		send:     send,
+		close:    close,
+		logger:   l,
+		closeCh:  make(chan struct{}),
+	}, nil
+}
+
+//Run 按周期上报数据，直到关闭
+func (r *cronReporter) Run() {
+	for {
+		next := r.schedule.Next(time.Now())
+		select {
+		case <-r.closeCh:
+			return
+		case <-time.After(time.Until(next)):
+			if err := r.send(); err != nil {
+				r.logger.Errorf("%s上报数据失败:%v", r.name, err)
+			}
+		}
+	}
+}
+
+//Close 关闭上报服务
+func (r *cronReporter) Close() error {
+	r.once.Do(func() {
+		close(r.closeCh)
+	})
+	if r.close != nil {
+		return r.close()
+	}
+	return nil
+}
+
+//InfluxDB2 构建influxdb(v2)上报服务
+func InfluxDB2(r Registry, c string, url string, org string, bucket string, token string, l *logger.Logger) (IReporter, error) {
+	u, err := uurl.Parse(url)
+	if err != nil {
+		return nil, fmt.Errorf("influxdb2地址%s有误:%w", url, err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/api/v2/write"
+	u.RawQuery = uurl.Values{"org": []string{org}, "bucket": []string{bucket}, "precision": []string{"ns"}}.Encode()
+	client := &http.Client{Timeout: 10 * time.Second}
+	return newCronReporter("influxdb2", c, l, func() error {
+		buff := &bytes.Buffer{}
+		for _, p := range makePoints(r) {
+			buff.WriteString(p.MarshalString())
+			buff.WriteByte('\n')
+		}
+		req, err := http.NewRequest(http.MethodPost, u.String(), buff)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Token "+token)
+		req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("influxdb2返回状态码:%d", resp.StatusCode)
+		}
+		return nil
+	}, nil)
+}
+
+//GraphiteReporter 构建graphite上报服务
+func GraphiteReporter(r Registry, c string, address string, prefix string, l *logger.Logger) (IReporter, error) {
+	addr, err := net.ResolveTCPAddr("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("graphite地址%s有误:%w", address, err)
+	}
+	conf := &GraphiteConfig{
+		Addr:         addr,
+		Registry:     &flatRegistry{Registry: r},
+		DurationUnit: time.Millisecond,
+		Prefix:       getPrefix(prefix),
+		Percentiles:  []float64{0.5, 0.75, 0.95, 0.99, 0.999},
+	}
+	return newCronReporter("graphite", c, l, func() error {
+		return graphite(conf)
+	}, nil)
+}
+
+//OpenTSDBReporter 构建opentsdb上报服务
+func OpenTSDBReporter(r Registry, c string, address string, prefix string, l *logger.Logger) (IReporter, error) {
+	addr, err := net.ResolveTCPAddr("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("opentsdb地址%s有误:%w", address, err)
+	}
+	conf := &OpenTSDBConfig{
+		Addr:         addr,
+		Registry:     &flatRegistry{Registry: r},
+		DurationUnit: time.Millisecond,
+		Prefix:       getPrefix(prefix),
+	}
+	return newCronReporter("opentsdb", c, l, func() error {
+		return openTSDB(conf)
+	}, nil)
+}
+
+//LogReporter 构建日志上报服务，将统计数据输出到日志
+func LogReporter(r Registry, c string, l *logger.Logger) (IReporter, error) {
+	return newCronReporter("log", c, l, func() error {
+		buff := &bytes.Buffer{}
+		WriteOnce(r, buff)
+		if buff.Len() > 0 {
+			l.Info(buff.String())
+		}
+		return nil
+	}, nil)
+}
+
+//JSONFileReporter 构建json文件上报服务，每个周期追加一行json格式的统计数据
+func JSONFileReporter(r Registry, c string, path string, l *logger.Logger) (IReporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0664)
+	if err != nil {
+		return nil, fmt.Errorf("打开metric文件%s失败:%w", path, err)
+	}
+	return newCronReporter("json", c, l, func() error {
+		WriteJSONOnce(r, f)
+		return nil
+	}, f.Close)
+}
+
+//StatsD 构建statsd(udp)上报服务，计数器上报增量，其它指标作为gauge上报
+func StatsD(r Registry, c string, address string, prefix string, l *logger.Logger) (IReporter, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("statsd地址%s有误:%w", address, err)
+	}
+	s := &statsd{reg: &flatRegistry{Registry: r}, conn: conn, prefix: getPrefix(prefix), last: make(map[string]int64)}
+	return newCronReporter("statsd", c, l, s.send, conn.Close)
+}
+
+//statsd 发送statsd格式的数据
+type statsd struct {
+	reg    Registry
+	conn   net.Conn
+	prefix string
+	last   map[string]int64
+	lines  []string
+}
+
+func (s *statsd) send() error {
+	s.lines = s.lines[:0]
+	s.reg.Each(func(name string, i interface{}) {
+		name = s.prefix + "." + name
+		switch metric := i.(type) {
+		case IQPS:
+			metric.Mark(0)
+			s.gauge(name+".m1", metric.M1())
+		case Counter:
+			s.gauge(name, metric.Count())
+		case Gauge:
+			s.gauge(name, metric.Value())
+		case GaugeFloat64:
+			s.gauge(name, metric.Value())
+		case Meter:
+			m := metric.Snapshot()
+			s.count(name+".count", m.Count())
+			s.gauge(name+".m1", m.Rate1())
+		case Histogram:
+			h := metric.Snapshot()
+			ps := h.Percentiles([]float64{0.5, 0.95, 0.99})
+			s.count(name+".count", h.Count())
+			s.gauge(name+".mean", h.Mean())
+			s.gauge(name+".p50", ps[0])
+			s.gauge(name+".p95", ps[1])
+			s.gauge(name+".p99", ps[2])
+		case Timer:
+			t := metric.Snapshot()
+			ps := t.Percentiles([]float64{0.5, 0.95, 0.99})
+			du := float64(time.Millisecond)
+			s.count(name+".count", t.Count())
+			s.timing(name+".mean", t.Mean()/du)
+			s.timing(name+".p50", ps[0]/du)
+			s.timing(name+".p95", ps[1]/du)
+			s.timing(name+".p99", ps[2]/du)
+		}
+	})
+
+	//按udp包大小分批发送
+	buff := &bytes.Buffer{}
+	for _, line := range s.lines {
+		if buff.Len() > 0 && buff.Len()+len(line)+1 > 1432 {
+			if _, err := s.conn.Write(buff.Bytes()); err != nil {
+				return err
+			}
+			buff.Reset()
+		}
+		if buff.Len() > 0 {
+			buff.WriteByte('\n')
+		}
+		buff.WriteString(line)
+	}
+	if buff.Len() > 0 {
+		_, err := s.conn.Write(buff.Bytes())
+		return err
+	}
+	return nil
+}
+
+func (s *statsd) gauge(name string, v interface{}) {
+	s.lines = append(s.lines, fmt.Sprintf("%s:%v|g", name, v))
+}
+
+func (s *statsd) timing(name string, v float64) {
+	s.lines = append(s.lines, fmt.Sprintf("%s:%f|ms", name, v))
+}
+
+//count 上报与上次相比的增量
+func (s *statsd) count(name string, v int64) {
+	delta := v - s.last[name]
+	s.last[name] = v
+	if delta > 0 {
+		s.lines = append(s.lines, fmt.Sprintf("%s:%d|c", name, delta))
+	}
+}
+
+//flatRegistry 将带标签的指标名称转换为以.分隔的名称，用于不支持标签的上报服务
+type flatRegistry struct {
+	Registry
+}
+
+//Each 遍历所有指标
+func (r *flatRegistry) Each(f func(string, interface{})) {
+	r.Registry.Each(func(name string, i interface{}) {
+		f(flatName(name), i)
+	})
+}
+
+//flatName 将名称与标签转换为以.分隔的名称,如:api.server.request.qps.server.x.host.192_168_0_1
+func flatName(name string) string {
+	rname, tags := splitGroup(name)
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	names := []string{rname}
+	for _, k := range keys {
+		names = append(names, flatValue(k), flatValue(tags[k]))
+	}
+	return strings.Join(names, ".")
+}
+
+func flatValue(v string) string {
+	v = strings.Trim(strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '/', ':', ' ', '=', '|', '\n':
+			return '_'
+		}
+		return r
+	}, v), "_")
+	if v == "" {
+		return "_"
+	}
+	return v
+}
+
+func getPrefix(prefix string) string {
+	if prefix == "" {
+		return "hydra"
+	}
+	return prefix
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micro-plat/lib4go/logger"
+)
+
+func TestFlatName(t *testing.T) {
+	name := MakeName("api.server.request", QPS, "server", "order", "host", "192.168.0.1", "url", "/order/create")
+	if s := flatName(name); s != "api.server.request.qps.host.192_168_0_1.server.order.url.order_create" {
+		t.Fatal(s)
+	}
+	if s := flatName("runtime.NumGoroutine"); s != "runtime.NumGoroutine" {
+		t.Fatal(s)
+	}
+}
+
+func TestStatsD(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	r := NewRegistry()
+	GetOrRegisterCounter(MakeName("api.server.request", WORKING, "url", "/a"), r).Inc(2)
+	GetOrRegisterMeter(MakeName("api.server.response", METER, "url", "/a"), r).Mark(3)
+	reporter, err := StatsD(r, "@every 1s", conn.LocalAddr().String(), "", logger.New("metric"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reporter.Close()
+	if err := reporter.(*cronReporter).send(); err != nil {
+		t.Fatal(err)
+	}
+	buff := make([]byte, 1500)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(buff[:n])
+	for _, line := range []string{"hydra.api.server.request.working.url.a:2|g", "hydra.api.server.response.meter.url.a.count:3|c"} {
+		if !strings.Contains(s, line) {
+			t.Fatalf("missing %s in %s", line, s)
+		}
+	}
+}
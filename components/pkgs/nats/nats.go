@@ -0,0 +1,150 @@
+package nats
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	varnats "github.com/micro-plat/hydra/conf/vars/queue/nats"
+	"github.com/micro-plat/lib4go/logger"
+	"github.com/nats-io/nats.go"
+)
+
+//reconnectWait 重连间隔
+const reconnectWait = 2 * time.Second
+
+//Client nats客户端，jetstream模式下使用工作队列流保存消息，消息确认后从流中删除
+type Client struct {
+	*nats.Conn
+	JS  nats.JetStreamContext
+	opt *varnats.NATS
+}
+
+//NewByConfig 构建客户端，连接断开后自动重连并恢复订阅，jetstream模式下检查流是否存在，不存在时创建
+func NewByConfig(config *varnats.NATS) (c *Client, err error) {
+	log := logger.GetSession("nats", logger.CreateSession())
+	opts := []nats.Option{
+		nats.Name("hydra"),
+		nats.Timeout(time.Duration(config.DialTimeout) * time.Second),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(reconnectWait),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				log.Warn("nats连接断开:", err)
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			log.Info("nats恢复连接:", conn.ConnectedUrl())
+		}),
+	}
+	if config.UserName != "" {
+		opts = append(opts, nats.UserInfo(config.UserName, config.Password))
+	}
+	if config.Token != "" {
+		opts = append(opts, nats.Token(config.Token))
+	}
+	c = &Client{opt: config}
+	if c.Conn, err = nats.Connect(config.Address, opts...); err != nil {
+		return nil, fmt.Errorf("nats: 无法连接到服务器%s:%w", config.Address, err)
+	}
+	if config.Proto != varnats.ProtoJetStream {
+		return c, nil
+	}
+	if c.JS, err = c.Conn.JetStream(nats.MaxWait(time.Duration(config.DialTimeout) * time.Second)); err != nil {
+		c.Conn.Close()
+		return nil, err
+	}
+	if err = c.ensureStream(); err != nil {
+		c.Conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+//ensureStream 检查流是否存在，不存在时创建工作队列流，流的主题为"流名称.>"
+func (c *Client) ensureStream() error {
+	info, err := c.JS.StreamInfo(c.opt.Stream)
+	if errors.Is(err, nats.ErrStreamNotFound) {
+		_, err = c.JS.AddStream(&nats.StreamConfig{
+			Name:        c.opt.Stream,
+			Subjects:    []string{c.opt.Stream + ".>"},
+			Retention:   nats.WorkQueuePolicy,
+			Storage:     nats.FileStorage,
+			AllowDirect: true,
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if info.Config.Retention != nats.WorkQueuePolicy {
+		return fmt.Errorf("nats: 流%s的保留策略为%s，需为%s", c.opt.Stream, info.Config.Retention, nats.WorkQueuePolicy)
+	}
+	return nil
+}
+
+//Subject 获取队列在流中的主题
+func (c *Client) Subject(queue string) string {
+	return c.opt.Stream + "." + queue
+}
+
+var invalidName = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+//Durable 获取队列的持久消费者名称，同时作为投递的队列组名称
+func (c *Client) Durable(queue string) string {
+	return invalidName.ReplaceAllString(c.opt.Group+"_"+queue, "_")
+}
+
+//EnsureConsumer 创建或更新队列的持久消费者，返回消费者名称。工作队列流中每个队列只能有一个持久消费者，
+//maxAckPending为未确认消息的最大数量，为0时使用服务器默认值
+func (c *Client) EnsureConsumer(queue string, maxAckPending int) (string, error) {
+	durable := c.Durable(queue)
+	cfg := &nats.ConsumerConfig{
+		Durable:        durable,
+		DeliverSubject: "_HYDRA.DELIVER." + c.opt.Stream + "." + durable,
+		DeliverGroup:   durable,
+		DeliverPolicy:  nats.DeliverAllPolicy,
+		AckPolicy:      nats.AckExplicitPolicy,
+		AckWait:        time.Duration(c.opt.AckWait) * time.Second,
+		MaxDeliver:     c.opt.MaxDeliver,
+		MaxAckPending:  maxAckPending,
+		FilterSubject:  c.Subject(queue),
+	}
+	_, err := c.JS.ConsumerInfo(c.opt.Stream, durable)
+	if errors.Is(err, nats.ErrConsumerNotFound) {
+		_, err = c.JS.AddConsumer(c.opt.Stream, cfg)
+		return durable, err
+	}
+	if err != nil {
+		return "", err
+	}
+	_, err = c.JS.UpdateConsumer(c.opt.Stream, cfg)
+	return durable, err
+}
+
+//Count 获取流中队列的消息数
+func (c *Client) Count(queue string) (int64, error) {
+	subject := c.Subject(queue)
+	info, err := c.JS.StreamInfo(c.opt.Stream, &nats.StreamInfoRequest{SubjectsFilter: subject})
+	if err != nil {
+		return 0, err
+	}
+	return int64(info.State.Subjects[subject]), nil
+}
+
+//Pop 移除并返回流中队列最早的消息，无消息时返回nats.ErrMsgNotFound
+func (c *Client) Pop(queue string) (*nats.RawStreamMsg, error) {
+	msg, err := c.JS.GetMsg(c.opt.Stream, 1, nats.DirectGetNext(c.Subject(queue)))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.JS.DeleteMsg(c.opt.Stream, msg.Sequence); err != nil {
+		return nil, err
+	}
+	//移除服务器添加的流信息头
+	for _, k := range []string{nats.JSStream, nats.JSSequence, nats.JSTimeStamp, nats.JSSubject, nats.JSLastSequence} {
+		msg.Header.Del(k)
+	}
+	return msg, nil
+}
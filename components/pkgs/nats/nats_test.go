@@ -0,0 +1,110 @@
+package nats
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	varnats "github.com/micro-plat/hydra/conf/vars/queue/nats"
+	"github.com/micro-plat/lib4go/assert"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+//runTestServer 启动进程内开启jetstream的nats服务器
+func runTestServer(t *testing.T) *server.Server {
+	s, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: t.TempDir(), NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Start()
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats服务器启动失败")
+	}
+	return s
+}
+
+func TestNewByConfig(t *testing.T) {
+	s := runTestServer(t)
+	defer s.Shutdown()
+
+	c, err := NewByConfig(varnats.NewJetStream(s.ClientURL(), varnats.WithStream("ORDER")))
+	assert.Equal(t, nil, err, "1. 连接服务器并创建流")
+	defer c.Close()
+	info, err := c.JS.StreamInfo("ORDER")
+	assert.Equal(t, nil, err, "2. 获取流信息")
+	assert.Equal(t, nats.WorkQueuePolicy, info.Config.Retention, "3. 流使用工作队列保留策略")
+	assert.Equal(t, []string{"ORDER.>"}, info.Config.Subjects, "4. 流的主题")
+
+	c2, err := NewByConfig(varnats.NewJetStream(s.ClientURL(), varnats.WithStream("ORDER")))
+	assert.Equal(t, nil, err, "5. 流已存在")
+	c2.Close()
+
+	c.JS.AddStream(&nats.StreamConfig{Name: "LIMITS", Subjects: []string{"LIMITS.>"}})
+	_, err = NewByConfig(varnats.NewJetStream(s.ClientURL(), varnats.WithStream("LIMITS")))
+	assert.NotEqual(t, nil, err, "6. 已存在的流不是工作队列保留策略")
+
+	c3, err := NewByConfig(varnats.New(s.ClientURL()))
+	assert.Equal(t, nil, err, "7. nats模式不创建流")
+	assert.Equal(t, nil, c3.JS, "8. nats模式不使用jetstream")
+	c3.Close()
+
+	_, err = NewByConfig(varnats.New("nats://127.0.0.1:1"))
+	assert.NotEqual(t, nil, err, "9. 无法连接服务器")
+}
+
+func TestClient_Stream(t *testing.T) {
+	s := runTestServer(t)
+	defer s.Shutdown()
+	c, err := NewByConfig(varnats.NewJetStream(s.ClientURL(), varnats.WithStream("ORDER")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	_, err = c.Pop("order")
+	assert.Equal(t, true, errors.Is(err, nats.ErrMsgNotFound), "1. 无消息时返回ErrMsgNotFound", err)
+	for _, v := range []string{"1", "2"} {
+		if _, err := c.JS.Publish(c.Subject("order"), []byte(v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.JS.Publish(c.Subject("user"), []byte("3"))
+	n, err := c.Count("order")
+	assert.Equal(t, nil, err, "2. 获取队列的消息数")
+	assert.Equal(t, int64(2), n, "3. 队列的消息数")
+
+	m, err := c.Pop("order")
+	assert.Equal(t, nil, err, "4. 移除最早的消息")
+	assert.Equal(t, "1", string(m.Data), "5. 最早的消息")
+	assert.Equal(t, 0, len(m.Header), "6. 不包含服务器添加的流信息头", m.Header)
+	m, _ = c.Pop("order")
+	assert.Equal(t, "2", string(m.Data), "7. 下一条消息")
+	n, _ = c.Count("user")
+	assert.Equal(t, int64(1), n, "8. 其它队列的消息不受影响")
+}
+
+func TestClient_EnsureConsumer(t *testing.T) {
+	s := runTestServer(t)
+	defer s.Shutdown()
+	c, err := NewByConfig(varnats.NewJetStream(s.ClientURL(), varnats.WithStream("ORDER"), varnats.WithGroup("api"), varnats.WithAck(5, 3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	durable, err := c.EnsureConsumer("order:create", 4)
+	assert.Equal(t, nil, err, "1. 创建持久消费者")
+	assert.Equal(t, "api_order_create", durable, "2. 持久消费者名称")
+	info, _ := c.JS.ConsumerInfo("ORDER", durable)
+	assert.Equal(t, 4, info.Config.MaxAckPending, "3. 未确认消息的最大数量")
+	assert.Equal(t, 5*time.Second, info.Config.AckWait, "4. 确认超时时长")
+	assert.Equal(t, 3, info.Config.MaxDeliver, "5. 最大投递次数")
+	assert.Equal(t, durable, info.Config.DeliverGroup, "6. 投递的队列组")
+	assert.Equal(t, "ORDER.order:create", info.Config.FilterSubject, "7. 过滤主题")
+
+	_, err = c.EnsureConsumer("order:create", 8)
+	assert.Equal(t, nil, err, "8. 持久消费者已存在时更新配置")
+	info, _ = c.JS.ConsumerInfo("ORDER", durable)
+	assert.Equal(t, 8, info.Config.MaxAckPending, "9. 更新后的未确认消息的最大数量")
+}
@@ -0,0 +1,85 @@
+package otel
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+//Exporter 链路跟踪数据上报服务
+type Exporter interface {
+
+	//Export 上报一批已结束的跨度
+	Export(service string, spans []*SpanData) error
+
+	//Close 关闭上报服务
+	Close() error
+}
+
+//stdout 以json格式逐行输出跨度数据
+type stdout struct {
+	w    io.Writer
+	lock sync.Mutex
+}
+
+//NewStdout 构建输出到控制台或文件的上报服务
+func NewStdout(w io.Writer) Exporter {
+	return &stdout{w: w}
+}
+
+type stdoutSpan struct {
+	Service    string                 `json:"service"`
+	Name       string                 `json:"name"`
+	Kind       string                 `json:"kind"`
+	TraceID    string                 `json:"trace_id"`
+	SpanID     string                 `json:"span_id"`
+	ParentID   string                 `json:"parent_id,omitempty"`
+	Start      string                 `json:"start"`
+	Duration   int64                  `json:"duration_us"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Status     string                 `json:"status,omitempty"`
+	Message    string                 `json:"message,omitempty"`
+}
+
+//Export 输出跨度数据
+func (s *stdout) Export(service string, spans []*SpanData) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	enc := json.NewEncoder(s.w)
+	for _, span := range spans {
+		v := &stdoutSpan{
+			Service:  service,
+			Name:     span.Name,
+			Kind:     span.Kind.String(),
+			TraceID:  span.Context.TraceID.String(),
+			SpanID:   span.Context.SpanID.String(),
+			Start:    span.Start.Format("2006-01-02 15:04:05.000000"),
+			Duration: span.End.Sub(span.Start).Microseconds(),
+			Message:  span.StatusMessage,
+		}
+		if span.Parent.IsValid() {
+			v.ParentID = span.Parent.String()
+		}
+		if len(span.Attributes) > 0 {
+			v.Attributes = make(map[string]interface{}, len(span.Attributes))
+			for _, attr := range span.Attributes {
+				v.Attributes[attr.Key] = attr.Value
+			}
+		}
+		switch span.StatusCode {
+		case StatusOK:
+			v.Status = "OK"
+		case StatusError:
+			v.Status = "ERROR"
+		}
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Close 关闭上报服务
+func (s *stdout) Close() error {
+	return nil
+}
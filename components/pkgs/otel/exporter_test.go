@@ -0,0 +1,97 @@
+package otel
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func testSpans() []*SpanData {
+	now := time.Now()
+	return []*SpanData{{
+		Name:       "/order/request",
+		Kind:       KindServer,
+		Context:    SpanContext{TraceID: newTraceID(), SpanID: newSpanID(), Flags: flagSampled},
+		Start:      now,
+		End:        now.Add(time.Millisecond),
+		Attributes: []Attribute{{Key: "http.status_code", Value: 200}},
+	}}
+}
+
+func TestZipkin_Export(t *testing.T) {
+	var body []map[string]interface{}
+	var path, token string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, token = r.URL.Path, r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	spans := testSpans()
+	if err := NewZipkin(srv.URL, map[string]string{"Authorization": "Bearer x"}).Export("apiserver", spans); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/api/v2/spans" || token != "Bearer x" || len(body) != 1 {
+		t.Fatalf("上报请求有误:%s %s %v", path, token, body)
+	}
+	if body[0]["traceId"] != spans[0].Context.TraceID.String() || body[0]["kind"] != "SERVER" || body[0]["duration"].(float64) != 1000 {
+		t.Errorf("上报数据有误:%v", body[0])
+	}
+}
+
+func TestOTLPHTTP_Export(t *testing.T) {
+	var body []byte
+	var path, ctp string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, ctp = r.URL.Path, r.Header.Get("Content-Type")
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	spans := testSpans()
+	if err := NewOTLPHTTP(srv.URL, nil).Export("apiserver", spans); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/v1/traces" || ctp != "application/x-protobuf" {
+		t.Fatalf("上报请求有误:%s %s", path, ctp)
+	}
+
+	//resource_spans.scope_spans.spans.trace_id
+	b := field(t, body, 1)
+	b = field(t, b, 2)
+	b = field(t, b, 2)
+	if tid := field(t, b, 1); string(tid) != string(spans[0].Context.TraceID[:]) {
+		t.Errorf("trace_id有误:%x", tid)
+	}
+	if name := field(t, b, 5); string(name) != "/order/request" {
+		t.Errorf("name有误:%s", name)
+	}
+}
+
+//field 获取第一个指定编号的bytes字段
+func field(t *testing.T, b []byte, num protowire.Number) []byte {
+	for len(b) > 0 {
+		n, tp, l := protowire.ConsumeTag(b)
+		if l < 0 {
+			t.Fatal("protobuf格式有误")
+		}
+		b = b[l:]
+		if tp == protowire.BytesType {
+			v, m := protowire.ConsumeBytes(b)
+			if n == num {
+				return v
+			}
+			b = b[m:]
+			continue
+		}
+		b = b[protowire.ConsumeFieldValue(n, tp, b):]
+	}
+	t.Fatalf("未找到字段:%d", num)
+	return nil
+}
@@ -0,0 +1,220 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+//otlpExportMethod OTLP/gRPC上报方法
+const otlpExportMethod = "/opentelemetry.proto.collector.trace.v1.TraceService/Export"
+
+//otlpGRPC 通过OTLP/gRPC上报跟踪数据
+type otlpGRPC struct {
+	conn    *grpc.ClientConn
+	headers map[string]string
+}
+
+//NewOTLPGRPC 构建OTLP/gRPC上报服务，address格式为host:port
+func NewOTLPGRPC(address string, headers map[string]string) (Exporter, error) {
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("连接otlp服务%s失败:%w", address, err)
+	}
+	return &otlpGRPC{conn: conn, headers: headers}, nil
+}
+
+//Export 上报跟踪数据
+func (o *otlpGRPC) Export(service string, spans []*SpanData) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if len(o.headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(o.headers))
+	}
+	req := marshalOTLP(service, spans)
+	var resp []byte
+	return o.conn.Invoke(ctx, otlpExportMethod, &req, &resp, grpc.ForceCodec(rawCodec{}))
+}
+
+//Close 关闭连接
+func (o *otlpGRPC) Close() error {
+	return o.conn.Close()
+}
+
+//otlpHTTP 通过OTLP/HTTP(protobuf)上报跟踪数据
+type otlpHTTP struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+//NewOTLPHTTP 构建OTLP/HTTP上报服务，未指定路径时使用/v1/traces
+func NewOTLPHTTP(url string, headers map[string]string) Exporter {
+	return &otlpHTTP{url: withPath(url, "/v1/traces"), headers: headers, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+//Export 上报跟踪数据
+func (o *otlpHTTP) Export(service string, spans []*SpanData) error {
+	return post(o.client, o.url, "application/x-protobuf", o.headers, marshalOTLP(service, spans))
+}
+
+//Close 关闭上报服务
+func (o *otlpHTTP) Close() error {
+	o.client.CloseIdleConnections()
+	return nil
+}
+
+//marshalOTLP 转换为opentelemetry.proto.collector.trace.v1.ExportTraceServiceRequest
+func marshalOTLP(service string, spans []*SpanData) []byte {
+
+	//Resource{attributes=1}
+	resource := appendKeyValue(nil, "service.name", service)
+
+	//ScopeSpans{scope=1,spans=2}
+	scope := appendMessage(nil, 1, protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), "hydra"))
+	for _, s := range spans {
+		scope = appendMessage(scope, 2, marshalSpan(s))
+	}
+
+	//ResourceSpans{resource=1,scope_spans=2}
+	rs := appendMessage(nil, 1, resource)
+	rs = appendMessage(rs, 2, scope)
+
+	//ExportTraceServiceRequest{resource_spans=1}
+	return appendMessage(nil, 1, rs)
+}
+
+func marshalSpan(s *SpanData) []byte {
+	b := appendBytes(nil, 1, s.Context.TraceID[:])
+	b = appendBytes(b, 2, s.Context.SpanID[:])
+	if s.Context.TraceState != "" {
+		b = appendBytes(b, 3, []byte(s.Context.TraceState))
+	}
+	if s.Parent.IsValid() {
+		b = appendBytes(b, 4, s.Parent[:])
+	}
+	b = appendBytes(b, 5, []byte(s.Name))
+	b = protowire.AppendVarint(protowire.AppendTag(b, 6, protowire.VarintType), uint64(s.Kind))
+	b = protowire.AppendFixed64(protowire.AppendTag(b, 7, protowire.Fixed64Type), uint64(s.Start.UnixNano()))
+	b = protowire.AppendFixed64(protowire.AppendTag(b, 8, protowire.Fixed64Type), uint64(s.End.UnixNano()))
+	for _, attr := range s.Attributes {
+		b = appendMessage(b, 9, appendAnyValue(appendBytes(nil, 1, []byte(attr.Key)), attr.Value))
+	}
+
+	//Status{message=2,code=3}
+	if s.StatusCode != StatusUnset {
+		status := protowire.AppendVarint(protowire.AppendTag(nil, 3, protowire.VarintType), uint64(s.StatusCode))
+		if s.StatusMessage != "" {
+			status = appendBytes(status, 2, []byte(s.StatusMessage))
+		}
+		b = appendMessage(b, 15, status)
+	}
+	return b
+}
+
+//appendKeyValue 添加KeyValue{key=1,value=2}
+func appendKeyValue(b []byte, key string, value interface{}) []byte {
+	return appendMessage(b, 1, appendAnyValue(appendBytes(nil, 1, []byte(key)), value))
+}
+
+//appendAnyValue 添加AnyValue{string_value=1,bool_value=2,int_value=3,double_value=4}
+func appendAnyValue(b []byte, value interface{}) []byte {
+	var v []byte
+	switch x := value.(type) {
+	case bool:
+		n := uint64(0)
+		if x {
+			n = 1
+		}
+		v = protowire.AppendVarint(protowire.AppendTag(nil, 2, protowire.VarintType), n)
+	case int:
+		v = protowire.AppendVarint(protowire.AppendTag(nil, 3, protowire.VarintType), uint64(x))
+	case int32:
+		v = protowire.AppendVarint(protowire.AppendTag(nil, 3, protowire.VarintType), uint64(x))
+	case int64:
+		v = protowire.AppendVarint(protowire.AppendTag(nil, 3, protowire.VarintType), uint64(x))
+	case float32:
+		v = protowire.AppendFixed64(protowire.AppendTag(nil, 4, protowire.Fixed64Type), math.Float64bits(float64(x)))
+	case float64:
+		v = protowire.AppendFixed64(protowire.AppendTag(nil, 4, protowire.Fixed64Type), math.Float64bits(x))
+	default:
+		v = appendBytes(nil, 1, []byte(fmt.Sprint(x)))
+	}
+	return appendMessage(b, 2, v)
+}
+
+func appendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	return protowire.AppendBytes(protowire.AppendTag(b, num, protowire.BytesType), msg)
+}
+
+func appendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	return protowire.AppendBytes(protowire.AppendTag(b, num, protowire.BytesType), v)
+}
+
+//rawCodec 直接发送已编码的protobuf数据
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("不支持的数据类型:%T", v)
+	}
+	return *b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("不支持的数据类型:%T", v)
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+//post 发送上报请求
+func post(client *http.Client, url string, contentType string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("%s返回状态码:%d %s", url, resp.StatusCode, msg)
+	}
+	return nil
+}
+
+//withPath 地址未包含路径时添加默认路径
+func withPath(url string, path string) string {
+	i := strings.Index(url, "://")
+	if i < 0 {
+		url = "http://" + url
+		i = 4
+	}
+	if !strings.Contains(url[i+3:], "/") {
+		return url + path
+	}
+	return url
+}
@@ -0,0 +1,128 @@
+package otel
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+//SpanKind 跨度类型
+type SpanKind int
+
+const (
+	//KindInternal 内部处理
+	KindInternal SpanKind = 1
+
+	//KindServer 服务端处理请求
+	KindServer SpanKind = 2
+
+	//KindClient 客户端发送请求
+	KindClient SpanKind = 3
+
+	//KindProducer 消息生产
+	KindProducer SpanKind = 4
+
+	//KindConsumer 消息消费
+	KindConsumer SpanKind = 5
+)
+
+//String 跨度类型名称
+func (k SpanKind) String() string {
+	switch k {
+	case KindServer:
+		return "SERVER"
+	case KindClient:
+		return "CLIENT"
+	case KindProducer:
+		return "PRODUCER"
+	case KindConsumer:
+		return "CONSUMER"
+	}
+	return "INTERNAL"
+}
+
+//StatusCode 处理状态
+type StatusCode int
+
+const (
+	//StatusUnset 未设置
+	StatusUnset StatusCode = 0
+
+	//StatusOK 处理成功
+	StatusOK StatusCode = 1
+
+	//StatusError 处理失败
+	StatusError StatusCode = 2
+)
+
+//Attribute 跨度标签
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+//SpanData 已结束的跨度数据，用于上报
+type SpanData struct {
+	Name          string
+	Kind          SpanKind
+	Context       SpanContext
+	Parent        SpanID
+	Start         time.Time
+	End           time.Time
+	Attributes    []Attribute
+	StatusCode    StatusCode
+	StatusMessage string
+}
+
+//Span 一次处理的跨度
+type Span struct {
+	tracer *Tracer
+	data   SpanData
+	lock   sync.Mutex
+	once   sync.Once
+}
+
+//SpanContext 获取当前跨度的跟踪信息
+func (s *Span) SpanContext() *SpanContext {
+	sc := s.data.Context
+	return &sc
+}
+
+//SetAttribute 设置标签，值为数字、字符串或bool类型，其它类型转换为字符串
+func (s *Span) SetAttribute(key string, value interface{}) {
+	switch value.(type) {
+	case string, bool, int, int32, int64, float32, float64:
+	default:
+		value = fmt.Sprint(value)
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for i, attr := range s.data.Attributes {
+		if attr.Key == key {
+			s.data.Attributes[i].Value = value
+			return
+		}
+	}
+	s.data.Attributes = append(s.data.Attributes, Attribute{Key: key, Value: value})
+}
+
+//SetStatus 设置处理状态
+func (s *Span) SetStatus(code StatusCode, msg string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.data.StatusCode = code
+	s.data.StatusMessage = msg
+}
+
+//End 结束跨度，已采样的跨度提交到上报服务
+func (s *Span) End() {
+	s.once.Do(func() {
+		s.lock.Lock()
+		s.data.End = time.Now()
+		data := s.data
+		s.lock.Unlock()
+		if data.Context.Flags&flagSampled == flagSampled {
+			s.tracer.export(&data)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package otel
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	//TraceParent W3C trace-context跟踪头
+	TraceParent = "traceparent"
+
+	//TraceState W3C trace-context厂商扩展信息头
+	TraceState = "tracestate"
+
+	//flagSampled 采样标志
+	flagSampled byte = 0x01
+)
+
+//TraceID 跟踪编号
+type TraceID [16]byte
+
+//SpanID 跨度编号
+type SpanID [8]byte
+
+//IsValid 是否为有效的跟踪编号
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+//String 十六进制格式的跟踪编号
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+//IsValid 是否为有效的跨度编号
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+//String 十六进制格式的跨度编号
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+//SpanContext 需要在服务间传递的跟踪信息
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	Flags      byte
+	TraceState string
+}
+
+//IsValid 是否为有效的跟踪信息
+func (c *SpanContext) IsValid() bool {
+	return c != nil && c.TraceID.IsValid() && c.SpanID.IsValid()
+}
+
+//IsSampled 是否需要采样
+func (c *SpanContext) IsSampled() bool {
+	return c != nil && c.Flags&flagSampled == flagSampled
+}
+
+//TraceParent 转换为traceparent头，格式:00-[trace-id]-[span-id]-[flags]
+func (c *SpanContext) TraceParent() string {
+	return fmt.Sprintf("00-%s-%s-%02x", c.TraceID, c.SpanID, c.Flags)
+}
+
+//Headers 获取需要向下游传递的跟踪头
+func (c *SpanContext) Headers() map[string]string {
+	if !c.IsValid() {
+		return nil
+	}
+	hd := map[string]string{TraceParent: c.TraceParent()}
+	if c.TraceState != "" {
+		hd[TraceState] = c.TraceState
+	}
+	return hd
+}
+
+//Extract 从请求头中获取上游传递的跟踪信息，未传递或格式错误时返回nil
+func Extract(get func(string) string) *SpanContext {
+	sc, err := ParseTraceParent(get(TraceParent))
+	if err != nil {
+		return nil
+	}
+	sc.TraceState = strings.TrimSpace(get(TraceState))
+	return sc
+}
+
+//ParseTraceParent 解析traceparent头
+func ParseTraceParent(v string) (*SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return nil, fmt.Errorf("traceparent格式有误:%s", v)
+	}
+	version, err := hex.DecodeString(parts[0])
+	if err != nil || version[0] == 0xff || (version[0] == 0 && len(parts) != 4) {
+		return nil, fmt.Errorf("traceparent版本有误:%s", v)
+	}
+	sc := &SpanContext{}
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return nil, fmt.Errorf("traceparent跟踪编号有误:%s", v)
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return nil, fmt.Errorf("traceparent跨度编号有误:%s", v)
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return nil, fmt.Errorf("traceparent标志有误:%s", v)
+	}
+	sc.Flags = flags[0]
+	if !sc.IsValid() {
+		return nil, fmt.Errorf("traceparent编号不能全为0:%s", v)
+	}
+	return sc, nil
+}
+
+func newTraceID() (t TraceID) {
+	for !t.IsValid() {
+		rand.Read(t[:])
+	}
+	return t
+}
+
+func newSpanID() (s SpanID) {
+	for !s.IsValid() {
+		rand.Read(s[:])
+	}
+	return s
+}
+
+//sampled 根据跟踪编号按比例采样，同一跟踪编号的结果相同
+func sampled(t TraceID, ratio float64) bool {
+	if ratio >= 1 {
+		return true
+	}
+	if ratio <= 0 {
+		return false
+	}
+	return binary.BigEndian.Uint64(t[8:])>>1 < uint64(ratio*(1<<63))
+}
@@ -0,0 +1,100 @@
+package otel
+
+import (
+	"testing"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+		sampled bool
+	}{
+		{name: "1. 已采样", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sampled: true},
+		{name: "2. 未采样", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", sampled: false},
+		{name: "3. 长度错误", value: "00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01", wantErr: true},
+		{name: "4. 编号全为0", value: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", wantErr: true},
+		{name: "5. 非法版本", value: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantErr: true},
+		{name: "6. 非十六进制", value: "00-4bf92f3577b34da6a3ce929d0e0e473x-00f067aa0ba902b7-01", wantErr: true},
+		{name: "7. 空值", value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		sc, err := ParseTraceParent(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: err=%v", tt.name, err)
+		}
+		if err != nil {
+			continue
+		}
+		if sc.IsSampled() != tt.sampled {
+			t.Errorf("%s: sampled=%v", tt.name, sc.IsSampled())
+		}
+		if sc.TraceParent() != tt.value {
+			t.Errorf("%s: traceparent=%s", tt.name, sc.TraceParent())
+		}
+	}
+}
+
+func TestTracer_Start(t *testing.T) {
+	exp := &memory{}
+	tracer := NewTracer("test", 1, exp, nil)
+	parent := Extract(func(k string) string {
+		return map[string]string{
+			TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			TraceState:  "vendor=1",
+		}[k]
+	})
+
+	root := tracer.Start("/order/request", KindServer, parent)
+	child := tracer.Start("db.query", KindClient, root.SpanContext())
+	child.SetAttribute("db.statement", "select 1")
+	child.End()
+	root.SetStatus(StatusError, "failed")
+	root.End()
+	if err := tracer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(exp.spans) != 2 {
+		t.Fatalf("上报的跨度数:%d", len(exp.spans))
+	}
+	c, r := exp.spans[0], exp.spans[1]
+	if r.Context.TraceID != parent.TraceID || r.Parent != parent.SpanID || r.Context.TraceState != "vendor=1" {
+		t.Errorf("未继承上游跟踪信息:%+v", r.Context)
+	}
+	if c.Context.TraceID != parent.TraceID || c.Parent != r.Context.SpanID {
+		t.Errorf("子跨度的上级有误:%+v", c)
+	}
+	if r.StatusCode != StatusError || len(c.Attributes) != 1 {
+		t.Errorf("跨度数据有误:%+v %+v", r, c)
+	}
+	hd := root.SpanContext().Headers()
+	if hd[TraceParent] != "00-4bf92f3577b34da6a3ce929d0e0e4736-"+r.Context.SpanID.String()+"-01" || hd[TraceState] != "vendor=1" {
+		t.Errorf("跟踪头有误:%v", hd)
+	}
+}
+
+func TestTracer_Sampled(t *testing.T) {
+	exp := &memory{}
+	tracer := NewTracer("test", 0, exp, nil)
+	tracer.Start("/order/request", KindServer, nil).End()
+	tracer.Start("/order/query", KindServer, &SpanContext{TraceID: newTraceID(), SpanID: newSpanID(), Flags: flagSampled}).End()
+	tracer.Close()
+	if len(exp.spans) != 1 || exp.spans[0].Name != "/order/query" {
+		t.Errorf("采样结果有误:%d", len(exp.spans))
+	}
+}
+
+type memory struct {
+	spans []*SpanData
+}
+
+func (m *memory) Export(service string, spans []*SpanData) error {
+	m.spans = append(m.spans, spans...)
+	return nil
+}
+
+func (m *memory) Close() error {
+	return nil
+}
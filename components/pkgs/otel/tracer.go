@@ -0,0 +1,127 @@
+package otel
+
+import (
+	"sync"
+	"time"
+
+	"github.com/micro-plat/lib4go/logger"
+)
+
+const (
+	//batchSize 每批上报的最大跨度数
+	batchSize = 512
+
+	//queueSize 等待上报的最大跨度数，超过后丢弃
+	queueSize = 2048
+
+	//flushInterval 上报周期
+	flushInterval = 5 * time.Second
+)
+
+//Tracer 跟踪器，创建跨度并批量上报已结束的跨度
+type Tracer struct {
+	service  string
+	ratio    float64
+	exporter Exporter
+	queue    chan *SpanData
+	closeCh  chan struct{}
+	done     chan struct{}
+	once     sync.Once
+	logger   logger.ILogger
+}
+
+//NewTracer 构建跟踪器，ratio为无上游跟踪信息时的采样比例
+func NewTracer(service string, ratio float64, exporter Exporter, l logger.ILogger) *Tracer {
+	t := &Tracer{
+		service:  service,
+		ratio:    ratio,
+		exporter: exporter,
+		queue:    make(chan *SpanData, queueSize),
+		closeCh:  make(chan struct{}),
+		done:     make(chan struct{}),
+		logger:   l,
+	}
+	go t.loop()
+	return t
+}
+
+//Service 服务名称
+func (t *Tracer) Service() string {
+	return t.service
+}
+
+//Start 创建并开始一个跨度，parent为空时创建新的跟踪，
+//有上级跨度时按上级的采样标志决定是否采样
+func (t *Tracer) Start(name string, kind SpanKind, parent *SpanContext) *Span {
+	s := &Span{tracer: t}
+	s.data.Name = name
+	s.data.Kind = kind
+	s.data.Start = time.Now()
+	s.data.Context.SpanID = newSpanID()
+	if parent.IsValid() {
+		s.data.Context.TraceID = parent.TraceID
+		s.data.Context.Flags = parent.Flags
+		s.data.Context.TraceState = parent.TraceState
+		s.data.Parent = parent.SpanID
+		return s
+	}
+	s.data.Context.TraceID = newTraceID()
+	if sampled(s.data.Context.TraceID, t.ratio) {
+		s.data.Context.Flags = flagSampled
+	}
+	return s
+}
+
+//Close 上报剩余的跨度并关闭上报服务
+func (t *Tracer) Close() error {
+	t.once.Do(func() {
+		close(t.closeCh)
+		<-t.done
+	})
+	return t.exporter.Close()
+}
+
+func (t *Tracer) export(s *SpanData) {
+	select {
+	case t.queue <- s:
+	default:
+		t.logger.Warn("链路跟踪数据过多，丢弃:", s.Name)
+	}
+}
+
+func (t *Tracer) loop() {
+	defer close(t.done)
+	tk := time.NewTicker(flushInterval)
+	defer tk.Stop()
+	batch := make([]*SpanData, 0, batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := t.exporter.Export(t.service, batch); err != nil {
+			t.logger.Errorf("上报链路跟踪数据失败:%v", err)
+		}
+		batch = make([]*SpanData, 0, batchSize)
+	}
+	for {
+		select {
+		case s := <-t.queue:
+			batch = append(batch, s)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-tk.C:
+			flush()
+		case <-t.closeCh:
+			for {
+				select {
+				case s := <-t.queue:
+					batch = append(batch, s)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
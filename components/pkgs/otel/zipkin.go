@@ -0,0 +1,83 @@
+package otel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+//zipkin 通过zipkin v2 json接口上报跟踪数据
+type zipkin struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+//NewZipkin 构建zipkin上报服务，未指定路径时使用/api/v2/spans
+func NewZipkin(url string, headers map[string]string) Exporter {
+	return &zipkin{url: withPath(url, "/api/v2/spans"), headers: headers, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+type zipkinSpan struct {
+	TraceID       string            `json:"traceId"`
+	ID            string            `json:"id"`
+	ParentID      string            `json:"parentId,omitempty"`
+	Name          string            `json:"name"`
+	Kind          string            `json:"kind,omitempty"`
+	Timestamp     int64             `json:"timestamp"`
+	Duration      int64             `json:"duration"`
+	LocalEndpoint *zipkinEndpoint   `json:"localEndpoint"`
+	Tags          map[string]string `json:"tags,omitempty"`
+}
+
+//Export 上报跟踪数据
+func (z *zipkin) Export(service string, spans []*SpanData) error {
+	buff, err := json.Marshal(toZipkin(service, spans))
+	if err != nil {
+		return err
+	}
+	return post(z.client, z.url, "application/json", z.headers, buff)
+}
+
+//Close 关闭上报服务
+func (z *zipkin) Close() error {
+	z.client.CloseIdleConnections()
+	return nil
+}
+
+func toZipkin(service string, spans []*SpanData) []*zipkinSpan {
+	list := make([]*zipkinSpan, 0, len(spans))
+	ep := &zipkinEndpoint{ServiceName: service}
+	for _, s := range spans {
+		z := &zipkinSpan{
+			TraceID:       s.Context.TraceID.String(),
+			ID:            s.Context.SpanID.String(),
+			Name:          s.Name,
+			Timestamp:     s.Start.UnixNano() / int64(time.Microsecond),
+			Duration:      s.End.Sub(s.Start).Microseconds(),
+			LocalEndpoint: ep,
+		}
+		if s.Kind != KindInternal {
+			z.Kind = s.Kind.String()
+		}
+		if s.Parent.IsValid() {
+			z.ParentID = s.Parent.String()
+		}
+		if len(s.Attributes) > 0 || s.StatusCode == StatusError {
+			z.Tags = make(map[string]string, len(s.Attributes)+1)
+			for _, attr := range s.Attributes {
+				z.Tags[attr.Key] = fmt.Sprint(attr.Value)
+			}
+			if s.StatusCode == StatusError {
+				z.Tags["error"] = s.StatusMessage
+			}
+		}
+		list = append(list, z)
+	}
+	return list
+}
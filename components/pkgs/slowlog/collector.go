@@ -0,0 +1,110 @@
+package slowlog
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/micro-plat/hydra/context"
+)
+
+//maxItems 最多汇总的记录数，超过后不再汇总新的请求或组件调用
+const maxItems = 10000
+
+//KindRequest 请求
+const KindRequest = "request"
+
+//Stat 慢请求或慢请求中组件调用的汇总数据
+type Stat struct {
+	Kind  string        `json:"kind"`
+	Name  string        `json:"name"`
+	Count int64         `json:"count"`
+	Total time.Duration `json:"total"`
+	Max   time.Duration `json:"max"`
+	Avg   time.Duration `json:"avg"`
+	Last  time.Time     `json:"last"`
+}
+
+//Report 最慢的请求与组件调用
+type Report struct {
+	Requests   []*Stat `json:"requests"`
+	Operations []*Stat `json:"operations"`
+}
+
+//Collector 汇总慢请求及其中的组件调用耗时
+type Collector struct {
+	lock       sync.Mutex
+	requests   map[string]*Stat
+	operations map[string]*Stat
+}
+
+//Default 默认的汇总器
+var Default = NewCollector()
+
+//NewCollector 构建汇总器
+func NewCollector() *Collector {
+	return &Collector{requests: make(map[string]*Stat), operations: make(map[string]*Stat)}
+}
+
+//Add 添加一个慢请求的耗时记录
+func (c *Collector) Add(serverType string, service string, elapsed time.Duration, timings []*context.Timing) {
+	now := time.Now()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	add(c.requests, KindRequest, serverType+" "+service, elapsed, now)
+	for _, t := range timings {
+		add(c.operations, t.Kind, t.Name, t.Duration, now)
+	}
+}
+
+//Top 获取最慢的n个请求与组件调用，按最大耗时排序
+func (c *Collector) Top(n int) *Report {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return &Report{Requests: top(c.requests, n), Operations: top(c.operations, n)}
+}
+
+//Reset 清除汇总数据
+func (c *Collector) Reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.requests = make(map[string]*Stat)
+	c.operations = make(map[string]*Stat)
+}
+
+func add(items map[string]*Stat, kind string, name string, d time.Duration, now time.Time) {
+	key := kind + ":" + name
+	s, ok := items[key]
+	if !ok {
+		if len(items) >= maxItems {
+			return
+		}
+		s = &Stat{Kind: kind, Name: name}
+		items[key] = s
+	}
+	s.Count++
+	s.Total += d
+	s.Last = now
+	if d > s.Max {
+		s.Max = d
+	}
+}
+
+func top(items map[string]*Stat, n int) []*Stat {
+	list := make([]*Stat, 0, len(items))
+	for _, s := range items {
+		v := *s
+		v.Avg = v.Total / time.Duration(v.Count)
+		list = append(list, &v)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Max == list[j].Max {
+			return list[i].Total > list[j].Total
+		}
+		return list[i].Max > list[j].Max
+	})
+	if n > 0 && len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
@@ -0,0 +1,41 @@
+package slowlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro-plat/hydra/context"
+)
+
+func TestCollector(t *testing.T) {
+	c := NewCollector()
+	c.Add("api", "/order/query", 2*time.Second, []*context.Timing{
+		{Kind: context.KindDB, Name: "db.query", Duration: 1500 * time.Millisecond},
+		{Kind: context.KindCache, Name: "cache.get", Duration: 10 * time.Millisecond},
+	})
+	c.Add("api", "/order/query", 3*time.Second, []*context.Timing{
+		{Kind: context.KindDB, Name: "db.query", Duration: 2500 * time.Millisecond},
+	})
+	c.Add("api", "/user/login", 1*time.Second, nil)
+
+	r := c.Top(1)
+	if len(r.Requests) != 1 || len(r.Operations) != 1 {
+		t.Fatalf("记录数有误:%d,%d", len(r.Requests), len(r.Operations))
+	}
+	req := r.Requests[0]
+	if req.Name != "api /order/query" || req.Count != 2 || req.Max != 3*time.Second || req.Avg != 2500*time.Millisecond {
+		t.Fatalf("请求汇总有误:%+v", req)
+	}
+	op := r.Operations[0]
+	if op.Kind != context.KindDB || op.Count != 2 || op.Max != 2500*time.Millisecond {
+		t.Fatalf("组件调用汇总有误:%+v", op)
+	}
+	if r := c.Top(0); len(r.Requests) != 2 || len(r.Operations) != 2 {
+		t.Fatalf("未限制数量时记录数有误:%d,%d", len(r.Requests), len(r.Operations))
+	}
+
+	c.Reset()
+	if r := c.Top(10); len(r.Requests) != 0 {
+		t.Fatalf("清除后记录数有误:%d", len(r.Requests))
+	}
+}
@@ -1,6 +1,8 @@
 package queues
 
 import (
+	"strings"
+
 	"github.com/micro-plat/hydra/components/pkgs"
 	"github.com/micro-plat/hydra/components/queues/mq"
 	"github.com/micro-plat/hydra/context"
@@ -10,6 +12,7 @@ import (
 //IQueue 消息队列
 type IQueue interface {
 	Send(key string, value interface{}, requestID ...string) error
+	SendWith(key string, value interface{}, opts ...SendOption) error
 }
 
 //IComponentQueue Component Queue
@@ -29,15 +32,54 @@ func newQueue(proto string, confRaw string) (q *queue, err error) {
 	return q, err
 }
 
-//Send 发送消息
+//Send 发送消息，未指定请求编号时使用当前请求的编号
 func (q *queue) Send(key string, value interface{}, requestID ...string) error {
-	hd := make([]string, 0, 2)
 	if len(requestID) > 0 {
-		hd = append(hd, context.XRequestID, requestID[0])
-	} else {
-		hd = append(hd, context.XRequestID, global.RID.GetXRequestID())
+		return q.SendWith(key, value, WithRequestID(requestID[0]))
+	}
+	return q.SendWith(key, value)
+}
+
+//SendWith 按选项发送消息，消息编号、内容类型、创建时间、请求编号及链路跟踪信息作为消息头随消息传递到消费端
+func (q *queue) SendWith(key string, value interface{}, opts ...SendOption) error {
+	e := mq.NewEnvelope("")
+	e.Header[context.XRequestID] = context.GetRequestID()
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.Body = getBody(value, e.ContentType)
+
+	//处理链路跟踪，跟踪头随消息头传递到消费端
+	name := global.MQConf.GetQueueName(key)
+	profiling := context.Profiling(context.KindMQ, name)
+	span := context.StartSpan("MQ " + name)
+	if span == nil {
+		err := q.q.Push(name, e.Encode())
+		profiling(err)
+		return err
+	}
+	defer span.End()
+	span.SetTag("messaging.destination", name)
+	for k, v := range span.GetHeaders() {
+		e.Trace[k] = v
+	}
+	err := q.q.Push(name, e.Encode())
+	span.SetError(err)
+	profiling(err)
+	return err
+}
+
+//getBody 获取消息内容，json类型的消息转换为json串
+func getBody(value interface{}, contentType string) string {
+	if !strings.Contains(contentType, "json") {
+		switch v := value.(type) {
+		case string:
+			return v
+		case []byte:
+			return string(v)
+		}
 	}
-	return q.q.Push(global.MQConf.GetQueueName(key), pkgs.GetStringByHeader(value, hd...))
+	return pkgs.GetString(value)
 }
 
 func (q *queue) Close() error {
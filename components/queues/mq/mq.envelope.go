@@ -0,0 +1,127 @@
+package mq
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/micro-plat/hydra/components/pkgs/otel"
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/lib4go/utility"
+)
+
+//contentType 消息内容类型的头信息名称
+const contentType = "Content-Type"
+
+//DefContentType 默认的消息内容类型
+const DefContentType = "application/json"
+
+//Envelope 消息信封，包含消息编号、内容类型、创建时间、处理次数、链路跟踪信息及其它头信息，
+//支持原生头信息的消息队列将头信息映射为原生头信息，其它消息队列使用包含__header__、__data__的字符串格式
+type Envelope struct {
+	ID          string
+	ContentType string
+	CreatedAt   time.Time
+	Attempts    int
+	Trace       map[string]string
+	Header      map[string]string
+	Body        string
+}
+
+//NewEnvelope 构建消息信封
+func NewEnvelope(body string) *Envelope {
+	return &Envelope{
+		ID:          utility.GetGUID(),
+		ContentType: DefContentType,
+		CreatedAt:   time.Now(),
+		Attempts:    1,
+		Trace:       make(map[string]string),
+		Header:      make(map[string]string),
+		Body:        body,
+	}
+}
+
+//NewEnvelopeByHeader 根据头信息及消息内容构建消息信封
+func NewEnvelopeByHeader(header map[string]string, body string) *Envelope {
+	e := &Envelope{
+		Trace:  make(map[string]string),
+		Header: make(map[string]string),
+		Body:   body,
+	}
+	for k, v := range header {
+		switch k {
+		case context.XMessageID:
+			e.ID = v
+		case contentType:
+			e.ContentType = v
+		case context.XMessageCreated:
+			e.CreatedAt, _ = time.Parse(time.RFC3339Nano, v)
+		case context.XMessageAttempts:
+			e.Attempts, _ = strconv.Atoi(v)
+		case otel.TraceParent, otel.TraceState:
+			e.Trace[k] = v
+		default:
+			e.Header[k] = v
+		}
+	}
+	return e
+}
+
+//ParseEnvelope 解析消息，不包含头信息的消息作为消息内容
+func ParseEnvelope(message string) *Envelope {
+	return NewEnvelopeByHeader(GetHeaders(message), GetBody(message))
+}
+
+//GetHeaders 获取所有头信息
+func (e *Envelope) GetHeaders() map[string]string {
+	header := make(map[string]string, len(e.Header)+len(e.Trace)+4)
+	for k, v := range e.Header {
+		header[k] = v
+	}
+	for k, v := range e.Trace {
+		header[k] = v
+	}
+	if e.ID != "" {
+		header[context.XMessageID] = e.ID
+	}
+	if e.ContentType != "" {
+		header[contentType] = e.ContentType
+	}
+	if !e.CreatedAt.IsZero() {
+		header[context.XMessageCreated] = e.CreatedAt.Format(time.RFC3339Nano)
+	}
+	if e.Attempts > 0 {
+		header[context.XMessageAttempts] = strconv.Itoa(e.Attempts)
+	}
+	return header
+}
+
+//Encode 转换为包含__header__、__data__的字符串格式
+func (e *Envelope) Encode() string {
+	return Marshal(e.GetHeaders(), e.Body)
+}
+
+//Marshal 将头信息及消息内容转换为包含__header__、__data__的字符串格式，无头信息时返回消息内容
+func Marshal(header map[string]string, body string) string {
+	if len(header) == 0 {
+		return body
+	}
+	buff, _ := json.Marshal(map[string]interface{}{
+		dataKey:   []byte(body),
+		headerKey: header,
+	})
+	return string(buff)
+}
+
+//Unmarshal 将包含__header__、__data__的消息拆分为头信息及消息内容，用于映射为消息队列的原生头信息，
+//其它格式的消息不拆分
+func Unmarshal(message string) (map[string]string, string) {
+	input := make(map[string]json.RawMessage)
+	if err := json.Unmarshal([]byte(message), &input); err != nil {
+		return nil, message
+	}
+	if _, ok := input[dataKey]; !ok {
+		return nil, message
+	}
+	return GetHeaders(message), GetBody(message)
+}
@@ -0,0 +1,76 @@
+package mq
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEnvelope_Encode(t *testing.T) {
+	e := NewEnvelope(`{"id":1}`)
+	e.Header["X-Request-Id"] = "abc"
+	e.Trace["traceparent"] = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	e.CreatedAt = time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	got := ParseEnvelope(e.Encode())
+	if got.ID != e.ID || got.ContentType != DefContentType || got.Attempts != 1 || !got.CreatedAt.Equal(e.CreatedAt) {
+		t.Errorf("消息信封有误:%+v", got)
+	}
+	if got.Header["X-Request-Id"] != "abc" || len(got.Header) != 1 {
+		t.Errorf("头信息有误:%v", got.Header)
+	}
+	if got.Trace["traceparent"] != e.Trace["traceparent"] || len(got.Trace) != 1 {
+		t.Errorf("跟踪信息有误:%v", got.Trace)
+	}
+	if got.Body != `{"id":1}` {
+		t.Errorf("消息内容有误:%s", got.Body)
+	}
+}
+
+func TestParseEnvelope(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		id       string
+		attempts int
+		body     string
+	}{
+		{name: "1. 普通json消息", message: `{"id":1}`, body: `{"id":1}`},
+		{name: "2. 非json消息", message: "hello", body: "hello"},
+		{name: "3. 旧格式的消息", message: `{"__data__":"eyJpZCI6MX0=","__header__":{"X-Request-Id":"abc"}}`, body: `{"id":1}`},
+		{name: "4. 包含消息编号及处理次数", message: `{"__data__":"eyJpZCI6MX0=","__header__":{"X-Message-Id":"m1","X-Message-Attempts":"3"}}`, id: "m1", attempts: 3, body: `{"id":1}`},
+	}
+	for _, tt := range tests {
+		e := ParseEnvelope(tt.message)
+		if e.ID != tt.id || e.Attempts != tt.attempts || e.Body != tt.body {
+			t.Errorf("%s:消息信封有误 %+v", tt.name, e)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		header  map[string]string
+		body    string
+	}{
+		{name: "1. 普通json消息", message: `{"id":1}`, body: `{"id":1}`},
+		{name: "2. 非json消息", message: "hello", body: "hello"},
+		{name: "3. 头信息在json消息中", message: `{"__header__":{"X-Message-Attempts":"2"},"id":1}`, body: `{"__header__":{"X-Message-Attempts":"2"},"id":1}`},
+		{name: "4. 包含头信息的消息", message: `{"__data__":"eyJpZCI6MX0=","__header__":{"X-Request-Id":"abc"}}`, header: map[string]string{"X-Request-Id": "abc"}, body: `{"id":1}`},
+	}
+	for _, tt := range tests {
+		header, body := Unmarshal(tt.message)
+		if len(header) != len(tt.header) || body != tt.body {
+			t.Errorf("%s:拆分结果有误 %v %s", tt.name, header, body)
+		}
+		for k, v := range tt.header {
+			if header[k] != v {
+				t.Errorf("%s:头信息%s有误 %s", tt.name, k, header[k])
+			}
+		}
+		if got := Marshal(header, body); GetBody(got) != GetBody(tt.message) {
+			t.Errorf("%s:还原后的消息有误 %s", tt.name, got)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package mq
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+const (
+	headerKey = "__header__"
+	dataKey   = "__data__"
+)
+
+//GetHeaders 获取消息中的头信息
+func GetHeaders(message string) map[string]string {
+	header := make(map[string]string)
+	input := make(map[string]json.RawMessage)
+	if err := json.Unmarshal([]byte(message), &input); err != nil {
+		return header
+	}
+	raw := make(map[string]interface{})
+	json.Unmarshal(input[headerKey], &raw)
+	for k, v := range raw {
+		if s, ok := v.(string); ok {
+			header[k] = s
+			continue
+		}
+		buff, _ := json.Marshal(v)
+		header[k] = string(buff)
+	}
+	return header
+}
+
+//GetBody 获取消息内容，包含__data__时返回解码后的内容
+func GetBody(message string) string {
+	input := make(map[string]json.RawMessage)
+	if err := json.Unmarshal([]byte(message), &input); err != nil {
+		return message
+	}
+	var data string
+	if err := json.Unmarshal(input[dataKey], &data); err != nil {
+		return message
+	}
+	buff, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return message
+	}
+	return string(buff)
+}
+
+//SetHeaders 设置消息的头信息，值为空时删除该头信息，非json对象的消息转换为包含__data__的格式
+func SetHeaders(message string, header map[string]string) string {
+	input := make(map[string]json.RawMessage)
+	if err := json.Unmarshal([]byte(message), &input); err != nil {
+		input = map[string]json.RawMessage{}
+		input[dataKey], _ = json.Marshal([]byte(message))
+	}
+	current := make(map[string]interface{})
+	json.Unmarshal(input[headerKey], &current)
+	for k, v := range header {
+		if v == "" {
+			delete(current, k)
+			continue
+		}
+		current[k] = v
+	}
+	input[headerKey], _ = json.Marshal(current)
+	buff, _ := json.Marshal(input)
+	return string(buff)
+}
@@ -0,0 +1,35 @@
+package mq
+
+import (
+	"testing"
+)
+
+func TestSetHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		header  map[string]string
+		want    map[string]string
+		body    string
+	}{
+		{name: "1. 非json消息", message: "hello", header: map[string]string{"X-Message-Attempts": "2"}, want: map[string]string{"X-Message-Attempts": "2"}, body: "hello"},
+		{name: "2. 包含头信息的消息", message: `{"__data__":"eyJpZCI6MX0=","__header__":{"X-Request-Id":"abc"}}`, header: map[string]string{"X-Message-Attempts": "2"}, want: map[string]string{"X-Request-Id": "abc", "X-Message-Attempts": "2"}, body: `{"id":1}`},
+		{name: "3. 删除头信息", message: `{"__data__":"eyJpZCI6MX0=","__header__":{"X-Request-Id":"abc","X-Message-Attempts":"3"}}`, header: map[string]string{"X-Message-Attempts": ""}, want: map[string]string{"X-Request-Id": "abc"}, body: `{"id":1}`},
+		{name: "4. 普通json消息", message: `{"id":12345678901234567}`, header: map[string]string{"X-Message-Attempts": "2"}, want: map[string]string{"X-Message-Attempts": "2"}, body: `{"__header__":{"X-Message-Attempts":"2"},"id":12345678901234567}`},
+	}
+	for _, tt := range tests {
+		message := SetHeaders(tt.message, tt.header)
+		got := GetHeaders(message)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s:头信息有误 %v", tt.name, got)
+		}
+		for k, v := range tt.want {
+			if got[k] != v {
+				t.Errorf("%s:头信息%s有误 %s", tt.name, k, got[k])
+			}
+		}
+		if body := GetBody(message); body != tt.body {
+			t.Errorf("%s:消息内容有误 %s", tt.name, body)
+		}
+	}
+}
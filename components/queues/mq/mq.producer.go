@@ -15,6 +15,12 @@ type IMQP interface {
 	Close() error
 }
 
+//IMQPBrowser 支持查看队列消息的生产者，用于查看死信队列等
+type IMQPBrowser interface {
+	//Peek 获取队列中从start开始的count条消息，不移除消息
+	Peek(key string, start int64, count int64) ([]string, error)
+}
+
 //imqpResover 定义配置文件转换方法
 type imqpResover interface {
 	Resolve(confRaw string) (IMQP, error)
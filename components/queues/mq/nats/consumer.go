@@ -0,0 +1,140 @@
+package nats
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/micro-plat/hydra/components/pkgs/nats"
+	"github.com/micro-plat/hydra/components/queues/mq"
+	varnats "github.com/micro-plat/hydra/conf/vars/queue/nats"
+	"github.com/micro-plat/lib4go/concurrent/cmap"
+	natsgo "github.com/nats-io/nats.go"
+)
+
+//Consumer nats消息消费者，按队列组订阅，同一队列组内的消息只被一个消费者处理，
+//jetstream模式下使用持久消费者，消息需确认，未确认的消息重新投递
+type Consumer struct {
+	client *nats.Client
+	conf   *varnats.NATS
+	queues cmap.ConcurrentMap
+	once   sync.Once
+}
+
+type subscriber struct {
+	sub  *natsgo.Subscription
+	done chan struct{}
+}
+
+//NewConsumer 创建消息消费者
+func NewConsumer(conf *varnats.NATS) (*Consumer, error) {
+	return &Consumer{conf: conf, queues: cmap.New(4)}, nil
+}
+
+//Connect 连接服务器
+func (consumer *Consumer) Connect() (err error) {
+	consumer.client, err = nats.NewByConfig(consumer.conf)
+	return err
+}
+
+//Consume 注册消费信息，jetstream模式下并发数作为持久消费者未确认消息的最大数量
+func (consumer *Consumer) Consume(queue string, concurrency int, callback func(mq.IMQCMessage)) (err error) {
+	if strings.EqualFold(queue, "") {
+		return errors.New("队列名字不能为空")
+	}
+	if callback == nil {
+		return errors.New("回调函数不能为nil")
+	}
+	if consumer.client == nil {
+		return errors.New("未连接到nats服务器")
+	}
+	_, _, err = consumer.queues.SetIfAbsentCb(queue, func(input ...interface{}) (c interface{}, err error) {
+		queue := input[0].(string)
+		nconcurrency := concurrency
+		if concurrency <= 0 {
+			nconcurrency = 10
+		}
+		done := make(chan struct{})
+		msgChan := make(chan *Message, nconcurrency)
+		for i := 0; i < nconcurrency; i++ {
+			go func() {
+				for {
+					select {
+					case <-done:
+						return
+					case message := <-msgChan:
+						if concurrency == 0 {
+							go callback(message)
+						} else {
+							callback(message)
+						}
+					}
+				}
+			}()
+		}
+		handler := func(m *natsgo.Msg) {
+			message := &Message{msg: m, js: consumer.client.JS != nil, delay: consumer.conf.RedeliveryDelay}
+			select {
+			case msgChan <- message:
+			case <-done:
+			}
+		}
+		sub, err := consumer.subscribe(queue, concurrency, handler)
+		if err != nil {
+			close(done)
+			return nil, err
+		}
+		return &subscriber{sub: sub, done: done}, nil
+	}, queue)
+	return
+}
+
+//subscribe 按队列组订阅，jetstream模式下创建或更新持久消费者并绑定订阅，取消订阅时不删除持久消费者
+func (consumer *Consumer) subscribe(queue string, concurrency int, handler natsgo.MsgHandler) (*natsgo.Subscription, error) {
+	if consumer.client.JS == nil {
+		return consumer.client.QueueSubscribe(queue, consumer.conf.Group, handler)
+	}
+	durable, err := consumer.client.EnsureConsumer(queue, concurrency)
+	if err != nil {
+		return nil, err
+	}
+	return consumer.client.JS.QueueSubscribe(consumer.client.Subject(queue), durable, handler,
+		natsgo.Bind(consumer.conf.Stream, durable), natsgo.ManualAck())
+}
+
+//UnConsume 取消注册消费
+func (consumer *Consumer) UnConsume(queue string) {
+	if c, ok := consumer.queues.Get(queue); ok {
+		s := c.(*subscriber)
+		s.sub.Unsubscribe()
+		close(s.done)
+	}
+	consumer.queues.Remove(queue)
+}
+
+//Close 关闭当前连接
+func (consumer *Consumer) Close() {
+	consumer.once.Do(func() {
+		consumer.queues.RemoveIterCb(func(key string, value interface{}) bool {
+			s := value.(*subscriber)
+			s.sub.Unsubscribe()
+			close(s.done)
+			return true
+		})
+		if consumer.client != nil {
+			consumer.client.Close()
+		}
+	})
+}
+
+type cresolver struct {
+}
+
+func (s *cresolver) Resolve(confRaw string) (mq.IMQC, error) {
+	return NewConsumer(varnats.NewByRaw(confRaw))
+}
+
+func init() {
+	mq.RegisterConsumer(varnats.ProtoNATS, &cresolver{})
+	mq.RegisterConsumer(varnats.ProtoJetStream, &cresolver{})
+}
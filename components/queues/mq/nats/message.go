@@ -0,0 +1,63 @@
+package nats
+
+import (
+	"time"
+
+	"github.com/micro-plat/hydra/components/queues/mq"
+	"github.com/nats-io/nats.go"
+)
+
+//Message nats消息
+type Message struct {
+	msg   *nats.Msg
+	js    bool
+	delay int
+}
+
+//Ack 确认消息，jetstream消息确认后从流中删除
+func (m *Message) Ack() error {
+	if !m.js {
+		return nil
+	}
+	return m.msg.Ack()
+}
+
+//Nack 不确认消息，jetstream消息按配置的延迟时长重新投递
+func (m *Message) Nack() error {
+	if !m.js {
+		return nil
+	}
+	if m.delay > 0 {
+		return m.msg.NakWithDelay(time.Duration(m.delay) * time.Millisecond)
+	}
+	return m.msg.Nak()
+}
+
+//GetMessage 获取消息，包含头信息时转换为包含__header__、__data__的格式
+func (m *Message) GetMessage() string {
+	return mq.Marshal(getHeader(m.msg.Header), string(m.msg.Data))
+}
+
+//newMsg 构建nats消息，头信息转换为nats原生头信息，保留头信息名称的大小写
+func newMsg(subject string, header map[string]string, body string) *nats.Msg {
+	msg := nats.NewMsg(subject)
+	for k, v := range header {
+		msg.Header[k] = []string{v}
+	}
+	msg.Data = []byte(body)
+	return msg
+}
+
+//getHeader 获取nats原生头信息，多个值时取第一个
+func getHeader(header nats.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	h := make(map[string]string, len(header))
+	for k, v := range header {
+		if len(v) > 0 {
+			h[k] = v[0]
+		}
+	}
+	return h
+}
@@ -0,0 +1,248 @@
+package nats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro-plat/hydra/components/queues/mq"
+	varnats "github.com/micro-plat/hydra/conf/vars/queue/nats"
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+//runTestServer 启动进程内开启jetstream的nats服务器
+func runTestServer(t *testing.T) *server.Server {
+	s, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: t.TempDir(), NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Start()
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats服务器启动失败")
+	}
+	t.Cleanup(s.Shutdown)
+	return s
+}
+
+//newTestConf 启动进程内nats服务器并获取jetstream配置
+func newTestConf(t *testing.T, opts ...varnats.Option) *varnats.NATS {
+	return varnats.NewJetStream(runTestServer(t).ClientURL(), opts...)
+}
+
+func newTestProducer(t *testing.T, conf *varnats.NATS) *Producer {
+	p, err := NewProducer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func newTestConsumer(t *testing.T, conf *varnats.NATS, queue string, concurrency int) (*Consumer, chan mq.IMQCMessage) {
+	c, err := NewConsumer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	ch := make(chan mq.IMQCMessage, 8)
+	if err := c.Consume(queue, concurrency, func(m mq.IMQCMessage) { ch <- m }); err != nil {
+		t.Fatal(err)
+	}
+	return c, ch
+}
+
+//receive 在超时时长内接收消息
+func receive(ch chan mq.IMQCMessage, timeout time.Duration) mq.IMQCMessage {
+	select {
+	case m := <-ch:
+		return m
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
+func TestJetStream_Producer(t *testing.T) {
+	conf := newTestConf(t)
+	p := newTestProducer(t, conf)
+	defer p.Close()
+
+	e := mq.NewEnvelope(`{"id":1}`)
+	e.Header["X-Request-Id"] = "abc"
+	for _, v := range []string{e.Encode(), `{"id":2}`} {
+		if err := p.Push("order", v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := p.Push("user", `{"id":3}`); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := p.Count("order"); err != nil || n != 2 {
+		t.Errorf("1. 队列的消息数有误:%d %v", n, err)
+	}
+	v, err := p.Pop("order")
+	got := mq.ParseEnvelope(v)
+	if err != nil || got.ID != e.ID || got.Header["X-Request-Id"] != "abc" || got.Body != `{"id":1}` {
+		t.Errorf("2. 移除最早的消息有误:%+v %v", got, err)
+	}
+	if v, err := p.Pop("order"); err != nil || v != `{"id":2}` {
+		t.Errorf("3. 移除下一条消息有误:%s %v", v, err)
+	}
+	if _, err := p.Pop("order"); err != mq.Nil {
+		t.Errorf("4. 队列为空时应返回mq.Nil:%v", err)
+	}
+	if n, err := p.Count("user"); err != nil || n != 1 {
+		t.Errorf("5. 其它队列的消息不受影响:%d %v", n, err)
+	}
+}
+
+func TestJetStream_Consumer(t *testing.T) {
+	conf := newTestConf(t, varnats.WithAck(1, 0))
+	p := newTestProducer(t, conf)
+	defer p.Close()
+
+	//1. 消费者启动前发送的消息持久化在流中
+	if err := p.Push("order", `{"id":1}`); err != nil {
+		t.Fatal(err)
+	}
+	c, ch := newTestConsumer(t, conf, "order", 1)
+	m := receive(ch, 3*time.Second)
+	if m == nil || m.GetMessage() != `{"id":1}` {
+		t.Fatalf("1. 未收到消费者启动前发送的消息:%v", m)
+	}
+
+	//2. 不确认的消息重新投递
+	if err := m.Nack(); err != nil {
+		t.Fatal(err)
+	}
+	m = receive(ch, 3*time.Second)
+	if m == nil || m.GetMessage() != `{"id":1}` {
+		t.Fatalf("2. 不确认的消息未重新投递:%v", m)
+	}
+
+	//3. 超过确认超时时长未确认的消息重新投递
+	m = receive(ch, 3*time.Second)
+	if m == nil || m.GetMessage() != `{"id":1}` {
+		t.Fatalf("3. 超时未确认的消息未重新投递:%v", m)
+	}
+
+	//4. 确认后的消息不再投递
+	if err := m.Ack(); err != nil {
+		t.Fatal(err)
+	}
+	if m := receive(ch, 2*time.Second); m != nil {
+		t.Errorf("4. 已确认的消息不应重复投递:%s", m.GetMessage())
+	}
+	c.Close()
+
+	//5. 持久消费者重新连接后从未确认的位置继续消费
+	if err := p.Push("order", `{"id":2}`); err != nil {
+		t.Fatal(err)
+	}
+	c, ch = newTestConsumer(t, conf, "order", 1)
+	defer c.Close()
+	m = receive(ch, 3*time.Second)
+	if m == nil || m.GetMessage() != `{"id":2}` {
+		t.Fatalf("5. 重新连接后获取的消息有误:%v", m)
+	}
+	m.Ack()
+}
+
+func TestJetStream_Concurrency(t *testing.T) {
+	conf := newTestConf(t)
+	p := newTestProducer(t, conf)
+	defer p.Close()
+	for i := 0; i < 4; i++ {
+		if err := p.Push("order", `{"id":1}`); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	//未确认的消息数达到并发数后不再投递
+	c, ch := newTestConsumer(t, conf, "order", 2)
+	defer c.Close()
+	var msgs []mq.IMQCMessage
+	for i := 0; i < 2; i++ {
+		if m := receive(ch, 3*time.Second); m != nil {
+			msgs = append(msgs, m)
+		}
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("1. 未收到并发数内的消息:%d", len(msgs))
+	}
+	if m := receive(ch, 500*time.Millisecond); m != nil {
+		t.Errorf("2. 未确认的消息数超过并发数:%s", m.GetMessage())
+	}
+
+	//确认后继续投递
+	msgs[0].Ack()
+	if m := receive(ch, 3*time.Second); m == nil {
+		t.Error("3. 确认后未继续投递消息")
+	}
+}
+
+func TestJetStream_RedeliveryDelay(t *testing.T) {
+	conf := newTestConf(t, varnats.WithRedeliveryDelay(800))
+	p := newTestProducer(t, conf)
+	defer p.Close()
+	if err := p.Push("order", `{"id":1}`); err != nil {
+		t.Fatal(err)
+	}
+	c, ch := newTestConsumer(t, conf, "order", 1)
+	defer c.Close()
+	m := receive(ch, 3*time.Second)
+	if m == nil {
+		t.Fatal("未收到消息")
+	}
+	if err := m.Nack(); err != nil {
+		t.Fatal(err)
+	}
+	if m := receive(ch, 400*time.Millisecond); m != nil {
+		t.Error("1. 延迟时长内不应重新投递")
+	}
+	if m := receive(ch, 3*time.Second); m == nil {
+		t.Error("2. 延迟时长后未重新投递")
+	}
+}
+
+func TestNATS_Consumer(t *testing.T) {
+	conf := varnats.New(runTestServer(t).ClientURL())
+	p := newTestProducer(t, conf)
+	defer p.Close()
+
+	//同一队列组的消息只被一个消费者处理
+	c1, ch1 := newTestConsumer(t, conf, "order", 1)
+	defer c1.Close()
+	c2, ch2 := newTestConsumer(t, conf, "order", 1)
+	defer c2.Close()
+	c1.client.Flush()
+	c2.client.Flush()
+	e := mq.NewEnvelope(`{"id":1}`)
+	e.Header["X-Request-Id"] = "abc"
+	if err := p.Push("order", e.Encode()); err != nil {
+		t.Fatal(err)
+	}
+	var m mq.IMQCMessage
+	select {
+	case m = <-ch1:
+	case m = <-ch2:
+	case <-time.After(3 * time.Second):
+		t.Fatal("1. 未收到消息")
+	}
+	got := mq.ParseEnvelope(m.GetMessage())
+	if got.ID != e.ID || got.Header["X-Request-Id"] != "abc" || got.Body != `{"id":1}` {
+		t.Errorf("2. 消息内容有误:%+v", got)
+	}
+	if m.Ack() != nil || m.Nack() != nil {
+		t.Error("3. nats模式确认消息不返回错误")
+	}
+	select {
+	case m := <-ch1:
+		t.Errorf("4. 消息被重复处理:%s", m.GetMessage())
+	case m := <-ch2:
+		t.Errorf("4. 消息被重复处理:%s", m.GetMessage())
+	case <-time.After(300 * time.Millisecond):
+	}
+	if _, err := p.Pop("order"); err == nil {
+		t.Error("5. nats模式不支持Pop")
+	}
+}
@@ -0,0 +1,75 @@
+package nats
+
+import (
+	"errors"
+
+	"github.com/micro-plat/hydra/components/pkgs/nats"
+	"github.com/micro-plat/hydra/components/queues/mq"
+	varnats "github.com/micro-plat/hydra/conf/vars/queue/nats"
+	natsgo "github.com/nats-io/nats.go"
+)
+
+//Producer nats消息生产者，jetstream模式下消息保存到流中并等待服务器确认
+type Producer struct {
+	client *nats.Client
+}
+
+//NewProducer 创建消息生产者
+func NewProducer(conf *varnats.NATS) (p *Producer, err error) {
+	p = &Producer{}
+	if p.client, err = nats.NewByConfig(conf); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+//Push 发送消息，消息头信息作为nats原生头信息发送
+func (p *Producer) Push(key string, value string) error {
+	header, body := mq.Unmarshal(value)
+	if p.client.JS == nil {
+		return p.client.PublishMsg(newMsg(key, header, body))
+	}
+	_, err := p.client.JS.PublishMsg(newMsg(p.client.Subject(key), header, body))
+	return err
+}
+
+//Pop 移除并返回流中最早的消息，仅jetstream支持
+func (p *Producer) Pop(key string) (string, error) {
+	if p.client.JS == nil {
+		return "", errors.New("nats不支持Pop")
+	}
+	msg, err := p.client.Pop(key)
+	if errors.Is(err, natsgo.ErrMsgNotFound) {
+		return "", mq.Nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return mq.Marshal(getHeader(msg.Header), string(msg.Data)), nil
+}
+
+//Count 获取流中的消息数，仅jetstream支持
+func (p *Producer) Count(key string) (int64, error) {
+	if p.client.JS == nil {
+		return 0, nil
+	}
+	return p.client.Count(key)
+}
+
+//Close 释放资源
+func (p *Producer) Close() error {
+	p.client.Close()
+	return nil
+}
+
+type presolver struct {
+}
+
+func (s *presolver) Resolve(confRaw string) (mq.IMQP, error) {
+	return NewProducer(varnats.NewByRaw(confRaw))
+}
+
+func init() {
+	mq.RegisterProducer(varnats.ProtoNATS, &presolver{})
+	mq.RegisterProducer(varnats.ProtoJetStream, &presolver{})
+}
@@ -4,7 +4,7 @@ import (
 	rds "github.com/go-redis/redis"
 	"github.com/micro-plat/hydra/components/pkgs/redis"
 	"github.com/micro-plat/hydra/components/queues/mq"
-	 "github.com/micro-plat/hydra/conf/vars/queue/queueredis"
+	"github.com/micro-plat/hydra/conf/vars/queue/queueredis"
 	varredis "github.com/micro-plat/hydra/conf/vars/redis"
 )
 
@@ -40,6 +40,11 @@ func (c *Producer) Pop(key string) (string, error) {
 	return r, err
 }
 
+// Peek 获取列表中从start开始的count个元素，不移除元素
+func (c *Producer) Peek(key string, start int64, count int64) ([]string, error) {
+	return c.client.LRange(key, start, start+count-1).Result()
+}
+
 // Count 获取列表中的元素个数
 func (c *Producer) Count(key string) (int64, error) {
 	return c.client.LLen(key).Result()
@@ -0,0 +1,226 @@
+package redisstream
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	rds "github.com/go-redis/redis"
+	"github.com/micro-plat/hydra/components/pkgs/redis"
+	"github.com/micro-plat/hydra/components/queues/mq"
+	varstream "github.com/micro-plat/hydra/conf/vars/queue/redisstream"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/lib4go/concurrent/cmap"
+	"github.com/micro-plat/lib4go/logger"
+	"github.com/micro-plat/lib4go/utility"
+)
+
+//blockTimeout 每次读取消息的最长等待时间，需小于redis的读超时时长
+const blockTimeout = time.Second
+
+//Consumer redis stream消息消费者，使用消费组读取消息，处理完成后确认消息，
+//超时未确认的消息由其它消费者领取后重新处理
+type Consumer struct {
+	client  *redis.Client
+	conf    *varstream.Stream
+	name    string
+	queues  cmap.ConcurrentMap
+	closeCh chan struct{}
+	done    bool
+	once    sync.Once
+	log     logger.ILogger
+}
+
+//NewConsumerByConfig 根据配置创建消息消费者
+func NewConsumerByConfig(conf *varstream.Stream) (consumer *Consumer, err error) {
+	return &Consumer{
+		conf:    conf,
+		name:    fmt.Sprintf("%s-%s", global.LocalIP(), utility.GetGUID()[:8]),
+		queues:  cmap.New(2),
+		closeCh: make(chan struct{}),
+		log:     logger.GetSession("mq.redis-stream", logger.CreateSession()),
+	}, nil
+}
+
+//Connect 连接服务器
+func (consumer *Consumer) Connect() (err error) {
+	consumer.client, err = redis.NewByConfig(consumer.conf.Redis.Redis)
+	return
+}
+
+//Consume 注册消费信息
+func (consumer *Consumer) Consume(queue string, concurrency int, callback func(mq.IMQCMessage)) (err error) {
+	if strings.EqualFold(queue, "") {
+		return errors.New("队列名字不能为空")
+	}
+	if callback == nil {
+		return errors.New("回调函数不能为nil")
+	}
+	_, _, err = consumer.queues.SetIfAbsentCb(queue, func(input ...interface{}) (c interface{}, err error) {
+		queue := input[0].(string)
+		if err := consumer.createGroup(queue); err != nil {
+			return nil, err
+		}
+		unconsumeCh := make(chan struct{}, 1)
+		nconcurrency := concurrency
+		if concurrency <= 0 {
+			nconcurrency = 10
+		}
+		msgChan := make(chan *Message, nconcurrency)
+		for i := 0; i < nconcurrency; i++ {
+			go func() {
+				for message := range msgChan {
+					if concurrency == 0 {
+						go callback(message)
+					} else {
+						callback(message)
+					}
+				}
+			}()
+		}
+
+		go func() {
+			claim := time.NewTicker(consumer.getClaimInterval())
+			defer claim.Stop()
+		START:
+			for {
+				select {
+				case <-consumer.closeCh:
+					break START
+				case <-unconsumeCh:
+					break START
+				case <-claim.C:
+					consumer.claim(queue, int64(nconcurrency), msgChan)
+				default:
+					consumer.read(queue, int64(nconcurrency), msgChan)
+				}
+			}
+			close(msgChan)
+		}()
+		return unconsumeCh, nil
+	}, queue)
+	return
+}
+
+//createGroup 创建消费组，stream不存在时自动创建
+func (consumer *Consumer) createGroup(queue string) error {
+	err := consumer.client.XGroupCreateMkStream(queue, consumer.conf.Group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("创建消费组失败(%s,%s):%w", queue, consumer.conf.Group, err)
+	}
+	return nil
+}
+
+//read 读取未分配给消费者的新消息
+func (consumer *Consumer) read(queue string, count int64, msgChan chan<- *Message) {
+	streams, err := consumer.client.XReadGroup(&rds.XReadGroupArgs{
+		Group:    consumer.conf.Group,
+		Consumer: consumer.name,
+		Streams:  []string{queue, ">"},
+		Count:    count,
+		Block:    blockTimeout,
+	}).Result()
+	if err != nil {
+		if err != rds.Nil && !consumer.done {
+			consumer.log.Errorf("从redis stream中获取消息失败:%v", err)
+			time.Sleep(blockTimeout)
+		}
+		return
+	}
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			msgChan <- newMessage(consumer.client, queue, consumer.conf.Group, msg.ID, msg.Values)
+		}
+	}
+}
+
+//claim 领取其它消费者超时未确认的消息
+func (consumer *Consumer) claim(queue string, count int64, msgChan chan<- *Message) {
+	timeout := time.Duration(consumer.conf.ClaimTimeout) * time.Second
+	pendings, err := consumer.client.XPendingExt(&rds.XPendingExtArgs{
+		Stream: queue,
+		Group:  consumer.conf.Group,
+		Start:  "-",
+		End:    "+",
+		Count:  count,
+	}).Result()
+	if err != nil {
+		consumer.log.Errorf("获取redis stream待确认消息失败:%v", err)
+		return
+	}
+	ids := make([]string, 0, len(pendings))
+	for _, p := range pendings {
+		if p.Idle >= timeout {
+			ids = append(ids, p.Id)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	msgs, err := consumer.client.XClaim(&rds.XClaimArgs{
+		Stream:   queue,
+		Group:    consumer.conf.Group,
+		Consumer: consumer.name,
+		MinIdle:  timeout,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		consumer.log.Errorf("领取redis stream待确认消息失败:%v", err)
+		return
+	}
+	for _, msg := range msgs {
+		message := newMessage(consumer.client, queue, consumer.conf.Group, msg.ID, msg.Values)
+		if len(msg.Values) == 0 {
+			//消息已被裁剪，直接确认
+			message.Ack()
+			continue
+		}
+		msgChan <- message
+	}
+}
+
+//getClaimInterval 获取检查超时未确认消息的间隔
+func (consumer *Consumer) getClaimInterval() time.Duration {
+	d := time.Duration(consumer.conf.ClaimTimeout) * time.Second / 2
+	if d < time.Second {
+		return time.Second
+	}
+	return d
+}
+
+//UnConsume 取消注册消费
+func (consumer *Consumer) UnConsume(queue string) {
+	if c, ok := consumer.queues.Get(queue); ok {
+		close(c.(chan struct{}))
+	}
+	consumer.queues.Remove(queue)
+}
+
+//Close 关闭当前连接
+func (consumer *Consumer) Close() {
+	consumer.once.Do(func() {
+		consumer.done = true
+		close(consumer.closeCh)
+	})
+	consumer.queues.RemoveIterCb(func(key string, value interface{}) bool {
+		close(value.(chan struct{}))
+		return true
+	})
+	if consumer.client == nil {
+		return
+	}
+	consumer.client.Close()
+}
+
+type consumerResolver struct {
+}
+
+func (s *consumerResolver) Resolve(confRaw string) (mq.IMQC, error) {
+	return NewConsumerByConfig(varstream.NewByRaw(confRaw))
+}
+
+func init() {
+	mq.RegisterConsumer(varstream.Proto, &consumerResolver{})
+}
@@ -0,0 +1,167 @@
+package redisstream
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/micro-plat/hydra/components/queues/mq"
+	varstream "github.com/micro-plat/hydra/conf/vars/queue/redisstream"
+	"github.com/micro-plat/lib4go/utility"
+)
+
+//newTestConf 获取测试使用的redis stream配置，未通过HYDRA_TEST_REDIS指定redis地址时跳过测试
+func newTestConf(t *testing.T, opts ...varstream.Option) *varstream.Stream {
+	addr := os.Getenv("HYDRA_TEST_REDIS")
+	if addr == "" {
+		t.Skip("未设置HYDRA_TEST_REDIS(redis地址，如:127.0.0.1:6379)")
+	}
+	return varstream.New(addr, opts...)
+}
+
+func newTestConsumer(t *testing.T, conf *varstream.Stream) *Consumer {
+	c, err := NewConsumerByConfig(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func newTestProducer(t *testing.T, conf *varstream.Stream) *Producer {
+	p, err := NewByConfig(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+//receive 在超时时长内接收消息
+func receive(ch chan mq.IMQCMessage, timeout time.Duration) mq.IMQCMessage {
+	select {
+	case m := <-ch:
+		return m
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
+func TestConsumer_Ack(t *testing.T) {
+	conf := newTestConf(t)
+	queue := "hydra:test:stream:" + utility.GetGUID()
+	p := newTestProducer(t, conf)
+	defer p.Close()
+	defer p.client.Del(queue)
+	c := newTestConsumer(t, conf)
+	defer c.Close()
+
+	ch := make(chan mq.IMQCMessage, 4)
+	if err := c.Consume(queue, 1, func(m mq.IMQCMessage) { ch <- m }); err != nil {
+		t.Fatal(err)
+	}
+	e := mq.NewEnvelope(`{"id":1}`)
+	e.Header["X-Request-Id"] = "abc"
+	if err := p.Push(queue, e.Encode()); err != nil {
+		t.Fatal(err)
+	}
+	m := receive(ch, 3*time.Second)
+	if m == nil {
+		t.Fatal("未收到消息")
+	}
+	got := mq.ParseEnvelope(m.GetMessage())
+	if got.ID != e.ID || got.Header["X-Request-Id"] != "abc" || got.Body != `{"id":1}` {
+		t.Errorf("收到的消息有误:%+v", got)
+	}
+	pending, _ := c.client.XPending(queue, conf.Group).Result()
+	if pending.Count != 1 {
+		t.Errorf("未确认的消息应保留在待确认列表中:%d", pending.Count)
+	}
+	if err := m.Ack(); err != nil {
+		t.Fatal(err)
+	}
+	pending, _ = c.client.XPending(queue, conf.Group).Result()
+	if pending.Count != 0 {
+		t.Errorf("确认后的消息应从待确认列表中移除:%d", pending.Count)
+	}
+	if m := receive(ch, 1500*time.Millisecond); m != nil {
+		t.Errorf("已确认的消息不应重复投递:%s", m.GetMessage())
+	}
+}
+
+func TestConsumer_Claim(t *testing.T) {
+	conf := newTestConf(t, varstream.WithClaimTimeout(1))
+	queue := "hydra:test:stream:" + utility.GetGUID()
+	p := newTestProducer(t, conf)
+	defer p.Close()
+	defer p.client.Del(queue)
+
+	//1. 消费者获取消息后未确认即退出
+	c1 := newTestConsumer(t, conf)
+	ch1 := make(chan mq.IMQCMessage, 4)
+	if err := c1.Consume(queue, 1, func(m mq.IMQCMessage) { ch1 <- m }); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Push(queue, `{"id":1}`); err != nil {
+		t.Fatal(err)
+	}
+	m := receive(ch1, 3*time.Second)
+	if m == nil {
+		t.Fatal("未收到消息")
+	}
+	if err := m.Nack(); err != nil {
+		t.Fatal(err)
+	}
+	c1.Close()
+
+	//2. 超时未确认的消息由其它消费者领取
+	c2 := newTestConsumer(t, conf)
+	defer c2.Close()
+	ch2 := make(chan mq.IMQCMessage, 4)
+	if err := c2.Consume(queue, 1, func(m mq.IMQCMessage) { ch2 <- m }); err != nil {
+		t.Fatal(err)
+	}
+	m = receive(ch2, 5*time.Second)
+	if m == nil {
+		t.Fatal("超时未确认的消息未被其它消费者领取")
+	}
+	if m.GetMessage() != `{"id":1}` {
+		t.Errorf("领取的消息有误:%s", m.GetMessage())
+	}
+	if err := m.Ack(); err != nil {
+		t.Fatal(err)
+	}
+	pending, _ := c2.client.XPending(queue, conf.Group).Result()
+	if pending.Count != 0 {
+		t.Errorf("确认后的消息应从待确认列表中移除:%d", pending.Count)
+	}
+}
+
+func TestProducer_Push(t *testing.T) {
+	conf := newTestConf(t, varstream.WithMaxLen(3, false))
+	queue := "hydra:test:stream:" + utility.GetGUID()
+	p := newTestProducer(t, conf)
+	defer p.Close()
+	defer p.client.Del(queue)
+
+	for _, v := range []string{"1", "2", "3", "4", "5"} {
+		if err := p.Push(queue, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, _ := p.Count(queue); n != 3 {
+		t.Errorf("超过最大长度时应裁剪最早的消息:%d", n)
+	}
+	list, err := p.Peek(queue, 0, 3)
+	if err != nil || len(list) != 3 || list[0] != "3" || list[2] != "5" {
+		t.Errorf("保留的消息有误:%v %v", list, err)
+	}
+	v, err := p.Pop(queue)
+	if err != nil || v != "3" {
+		t.Errorf("移除的消息有误:%s %v", v, err)
+	}
+	if n, _ := p.Count(queue); n != 2 {
+		t.Errorf("移除后的消息数有误:%d", n)
+	}
+}
@@ -0,0 +1,66 @@
+package redisstream
+
+import (
+	"strings"
+
+	"github.com/micro-plat/hydra/components/pkgs/redis"
+	"github.com/micro-plat/hydra/components/queues/mq"
+	"github.com/micro-plat/lib4go/types"
+)
+
+//dataField 消息内容在stream条目中的字段名
+const dataField = "data"
+
+//headerPrefix 消息头在stream条目中的字段名前缀，避免与消息内容字段冲突
+const headerPrefix = "h:"
+
+//Message redis stream消息
+type Message struct {
+	client  *redis.Client
+	stream  string
+	group   string
+	id      string
+	message string
+}
+
+func newMessage(client *redis.Client, stream string, group string, id string, values map[string]interface{}) *Message {
+	header := make(map[string]string, len(values))
+	for k, v := range values {
+		if strings.HasPrefix(k, headerPrefix) {
+			header[strings.TrimPrefix(k, headerPrefix)] = types.GetString(v)
+		}
+	}
+	return &Message{
+		client:  client,
+		stream:  stream,
+		group:   group,
+		id:      id,
+		message: mq.Marshal(header, types.GetString(values[dataField])),
+	}
+}
+
+//getValues 获取消息在stream条目中的字段，消息头信息加上前缀后作为独立的字段保存
+func getValues(message string) map[string]interface{} {
+	header, body := mq.Unmarshal(message)
+	values := make(map[string]interface{}, len(header)+1)
+	for k, v := range header {
+		values[headerPrefix+k] = v
+	}
+	values[dataField] = body
+	return values
+}
+
+//Ack 确认消息，消息从消费组的待确认列表中移除
+func (m *Message) Ack() error {
+	return m.client.XAck(m.stream, m.group, m.id).Err()
+}
+
+//Nack 不确认消息，消息保留在待确认列表中，超时后由消费者重新获取
+func (m *Message) Nack() error {
+	return nil
+}
+
+//GetMessage 获取消息
+func (m *Message) GetMessage() string {
+	return m.message
+}
@@ -0,0 +1,26 @@
+package redisstream
+
+import (
+	"testing"
+
+	"github.com/micro-plat/hydra/components/queues/mq"
+)
+
+func TestGetValues(t *testing.T) {
+	e := mq.NewEnvelope(`{"id":1}`)
+	e.Header["X-Request-Id"] = "abc"
+	e.Header[dataField] = "header"
+	values := getValues(e.Encode())
+	if values[dataField] != `{"id":1}` || values[headerPrefix+"X-Request-Id"] != "abc" || values[headerPrefix+"X-Message-Id"] != e.ID {
+		t.Errorf("stream条目的字段有误:%v", values)
+	}
+	got := mq.ParseEnvelope(newMessage(nil, "order", "", "1-0", values).GetMessage())
+	if got.ID != e.ID || got.Header["X-Request-Id"] != "abc" || got.Header[dataField] != "header" || got.Body != `{"id":1}` {
+		t.Errorf("还原的消息有误:%+v", got)
+	}
+
+	values = getValues(`{"id":2}`)
+	if len(values) != 1 || newMessage(nil, "order", "", "1-0", values).GetMessage() != `{"id":2}` {
+		t.Errorf("普通消息的字段有误:%v", values)
+	}
+}
@@ -0,0 +1,84 @@
+package redisstream
+
+import (
+	rds "github.com/go-redis/redis"
+	"github.com/micro-plat/hydra/components/pkgs/redis"
+	"github.com/micro-plat/hydra/components/queues/mq"
+	varstream "github.com/micro-plat/hydra/conf/vars/queue/redisstream"
+)
+
+//Producer redis stream消息生产者
+type Producer struct {
+	client *redis.Client
+	conf   *varstream.Stream
+}
+
+//NewByConfig 根据配置创建消息生产者
+func NewByConfig(conf *varstream.Stream) (p *Producer, err error) {
+	p = &Producer{conf: conf}
+	p.client, err = redis.NewByConfig(conf.Redis.Redis)
+	return
+}
+
+//Push 向stream尾部添加消息，设置了最大长度时裁剪最早的消息
+func (p *Producer) Push(key string, value string) error {
+	args := &rds.XAddArgs{
+		Stream: key,
+		Values: getValues(value),
+	}
+	if p.conf.Approx {
+		args.MaxLenApprox = p.conf.MaxLen
+	} else {
+		args.MaxLen = p.conf.MaxLen
+	}
+	return p.client.XAdd(args).Err()
+}
+
+//Pop 移除并返回stream中最早的消息
+func (p *Producer) Pop(key string) (string, error) {
+	msgs, err := p.client.XRangeN(key, "-", "+", 1).Result()
+	if err != nil {
+		return "", err
+	}
+	if len(msgs) == 0 {
+		return "", mq.Nil
+	}
+	if err := p.client.XDel(key, msgs[0].ID).Err(); err != nil {
+		return "", err
+	}
+	return newMessage(p.client, key, "", msgs[0].ID, msgs[0].Values).GetMessage(), nil
+}
+
+//Peek 获取stream中从start开始的count条消息，不移除消息
+func (p *Producer) Peek(key string, start int64, count int64) ([]string, error) {
+	msgs, err := p.client.XRangeN(key, "-", "+", start+count).Result()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]string, 0, count)
+	for i := start; i < int64(len(msgs)); i++ {
+		list = append(list, newMessage(p.client, key, "", msgs[i].ID, msgs[i].Values).GetMessage())
+	}
+	return list, nil
+}
+
+//Count 获取stream中的消息数
+func (p *Producer) Count(key string) (int64, error) {
+	return p.client.XLen(key).Result()
+}
+
+//Close 释放资源
+func (p *Producer) Close() error {
+	return p.client.Close()
+}
+
+type producerResolver struct {
+}
+
+func (s *producerResolver) Resolve(confRaw string) (mq.IMQP, error) {
+	return NewByConfig(varstream.NewByRaw(confRaw))
+}
+
+func init() {
+	mq.RegisterProducer(varstream.Proto, &producerResolver{})
+}
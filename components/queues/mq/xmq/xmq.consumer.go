@@ -0,0 +1,348 @@
+package xmq
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro-plat/hydra/components/queues/mq"
+	"github.com/micro-plat/hydra/conf/vars/queue/xmq"
+	"github.com/micro-plat/lib4go/concurrent/cmap"
+	"github.com/micro-plat/lib4go/encoding"
+	"github.com/micro-plat/lib4go/logger"
+)
+
+//checkInterval 心跳及重连检查间隔
+var checkInterval = time.Second * 3
+
+type subscriber struct {
+	concurrency int
+	msgChan     chan *ConsumerMessage
+	unconsumeCh chan struct{}
+}
+
+//Consumer xmq消息消费者，连接断开后自动重连并重新订阅队列。
+//订阅、确认等指令为消费者扩展指令，需xmq服务器支持，见cmdSubscribe
+type Consumer struct {
+	conn           net.Conn
+	connecting     bool
+	isConnected    bool
+	closeCh        chan struct{}
+	done           bool
+	lk             sync.Mutex
+	writeLock      sync.Mutex
+	lastWrite      time.Time
+	firstConnected bool
+	queues         cmap.ConcurrentMap
+	once           sync.Once
+	log            *logger.Logger
+	confOpts       *xmq.XMQ
+}
+
+//NewConsumer 创建新的Consumer
+func NewConsumer(confOpts *xmq.XMQ) (consumer *Consumer, err error) {
+	consumer = &Consumer{
+		log:      logger.GetSession("xmq", logger.CreateSession()),
+		confOpts: confOpts,
+		queues:   cmap.New(2),
+	}
+	consumer.closeCh = make(chan struct{})
+	return consumer, nil
+}
+
+//Connect 连接服务器，首次连接失败时返回错误，连接断开后定时重连，连接空闲时发送心跳
+func (consumer *Consumer) Connect() error {
+	if err := consumer.connectOnce(); err != nil {
+		return err
+	}
+	go func() {
+		for {
+			select {
+			case <-consumer.closeCh:
+				return
+			case <-time.After(checkInterval):
+				if consumer.isDone() {
+					return
+				}
+				if !consumer.connected() {
+					if err := consumer.connectOnce(); err != nil {
+						consumer.log.Error(err)
+					}
+					continue
+				}
+				if time.Since(consumer.getLastWrite()) < checkInterval {
+					continue
+				}
+				if err := consumer.send(newHeartBit()); err != nil {
+					consumer.log.Error(err)
+					consumer.disconnect()
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+//connectOnce 连接到服务器，并重新订阅已注册的队列
+func (consumer *Consumer) connectOnce() (err error) {
+	consumer.lk.Lock()
+	if consumer.connecting || consumer.done {
+		consumer.lk.Unlock()
+		return nil
+	}
+	consumer.connecting = true
+	consumer.lk.Unlock()
+	defer func() {
+		consumer.lk.Lock()
+		consumer.connecting = false
+		consumer.lk.Unlock()
+	}()
+
+	conn, err := net.DialTimeout("tcp", consumer.confOpts.Address, time.Second*2)
+	if err != nil {
+		return fmt.Errorf("mq 无法连接到远程服务器:%v", err)
+	}
+	consumer.lk.Lock()
+	consumer.conn = conn
+	consumer.isConnected = true
+	if !consumer.firstConnected {
+		consumer.firstConnected = true
+	} else {
+		consumer.log.Info("恢复连接:", consumer.confOpts.Address)
+	}
+	consumer.lk.Unlock()
+	consumer.setLastWrite()
+	go consumer.read(conn)
+
+	for k, v := range consumer.queues.Items() {
+		if err = consumer.subscribe(k, v.(*subscriber).concurrency); err != nil {
+			consumer.disconnect()
+			return err
+		}
+	}
+	return nil
+}
+
+//read 读取服务器推送的消息，读取失败时断开连接等待重连
+func (consumer *Consumer) read(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if !consumer.isDone() {
+				consumer.log.Warn("读取数据失败:", err)
+			}
+			consumer.lk.Lock()
+			if consumer.conn == conn {
+				consumer.isConnected = false
+			}
+			consumer.lk.Unlock()
+			conn.Close()
+			return
+		}
+		if err := consumer.handle(line); err != nil {
+			consumer.log.Error(err)
+		}
+	}
+}
+
+//handle 处理服务器推送的消息，心跳等其它指令不处理
+func (consumer *Consumer) handle(line []byte) error {
+	buff, err := encoding.DecodeBytes(line, "gbk")
+	if err != nil {
+		return err
+	}
+	message := &Message{}
+	if err := json.Unmarshal(buff, message); err != nil {
+		return fmt.Errorf("消息格式有误:%s %w", buff, err)
+	}
+	if message.CMD != cmdSend {
+		return nil
+	}
+	if err := message.Check(consumer.getSignKey()); err != nil {
+		return err
+	}
+	s, ok := consumer.queues.Get(message.QueueName)
+	if !ok {
+		return nil
+	}
+	sub := s.(*subscriber)
+	for _, data := range message.Data {
+		select {
+		case sub.msgChan <- &ConsumerMessage{consumer: consumer, message: message, data: data}:
+		case <-sub.unconsumeCh:
+			return nil
+		case <-consumer.closeCh:
+			return nil
+		}
+	}
+	return nil
+}
+
+//Consume 注册消费信息
+func (consumer *Consumer) Consume(queue string, concurrency int, callback func(mq.IMQCMessage)) (err error) {
+	if strings.EqualFold(queue, "") {
+		return errors.New("队列名字不能为空")
+	}
+	if callback == nil {
+		return errors.New("回调函数不能为nil")
+	}
+	_, _, err = consumer.queues.SetIfAbsentCb(queue, func(input ...interface{}) (c interface{}, err error) {
+		queue := input[0].(string)
+		nconcurrency := concurrency
+		if concurrency <= 0 {
+			nconcurrency = 10
+		}
+		sub := &subscriber{
+			concurrency: nconcurrency,
+			msgChan:     make(chan *ConsumerMessage, nconcurrency),
+			unconsumeCh: make(chan struct{}),
+		}
+		for i := 0; i < nconcurrency; i++ {
+			go func() {
+				for {
+					select {
+					case <-sub.unconsumeCh:
+						return
+					case message := <-sub.msgChan:
+						if concurrency == 0 {
+							//默认10个线程获取任务，开启新协程处理任务
+							go callback(message)
+						} else {
+							callback(message)
+						}
+					}
+				}
+			}()
+		}
+		if consumer.connected() {
+			if err := consumer.subscribe(queue, nconcurrency); err != nil {
+				close(sub.unconsumeCh)
+				return nil, err
+			}
+		}
+		return sub, nil
+	}, queue)
+	return
+}
+
+//subscribe 订阅队列，预取数量与并发数一致
+func (consumer *Consumer) subscribe(queue string, concurrency int) error {
+	return consumer.send(newCommand(cmdSubscribe, queue, strconv.Itoa(concurrency)))
+}
+
+//UnConsume 取消注册消费
+func (consumer *Consumer) UnConsume(queue string) {
+	if c, ok := consumer.queues.Get(queue); ok {
+		close(c.(*subscriber).unconsumeCh)
+		if consumer.connected() {
+			if err := consumer.send(newCommand(cmdUnsubscribe, queue)); err != nil {
+				consumer.log.Error(err)
+			}
+		}
+	}
+	consumer.queues.Remove(queue)
+}
+
+//Close 关闭当前连接
+func (consumer *Consumer) Close() {
+	consumer.once.Do(func() {
+		consumer.lk.Lock()
+		consumer.done = true
+		consumer.lk.Unlock()
+		close(consumer.closeCh)
+		consumer.queues.RemoveIterCb(func(key string, value interface{}) bool {
+			close(value.(*subscriber).unconsumeCh)
+			return true
+		})
+		consumer.disconnect()
+	})
+}
+
+//send 签名并发送指令
+func (consumer *Consumer) send(message *Message) error {
+	message.signKey = consumer.getSignKey()
+	msgVal, err := message.Make()
+	if err != nil {
+		return err
+	}
+	return consumer.writeMessage(msgVal)
+}
+
+func (consumer *Consumer) writeMessage(msg string) error {
+	consumer.lk.Lock()
+	conn, isConnected := consumer.conn, consumer.isConnected
+	consumer.lk.Unlock()
+	if !isConnected {
+		return fmt.Errorf("未连接到服务器")
+	}
+	result, err := encoding.Decode(msg, "gbk")
+	if err != nil {
+		return err
+	}
+	consumer.writeLock.Lock()
+	defer consumer.writeLock.Unlock()
+	_, err = conn.Write(result)
+	consumer.lastWrite = time.Now()
+	if err != nil {
+		consumer.log.Warn("发送数据失败:", err)
+	}
+	return err
+}
+
+func (consumer *Consumer) disconnect() {
+	consumer.lk.Lock()
+	defer consumer.lk.Unlock()
+	consumer.isConnected = false
+	if consumer.conn != nil {
+		consumer.conn.Close()
+	}
+}
+
+func (consumer *Consumer) connected() bool {
+	consumer.lk.Lock()
+	defer consumer.lk.Unlock()
+	return consumer.isConnected
+}
+
+func (consumer *Consumer) isDone() bool {
+	consumer.lk.Lock()
+	defer consumer.lk.Unlock()
+	return consumer.done
+}
+
+func (consumer *Consumer) setLastWrite() {
+	consumer.writeLock.Lock()
+	defer consumer.writeLock.Unlock()
+	consumer.lastWrite = time.Now()
+}
+
+func (consumer *Consumer) getLastWrite() time.Time {
+	consumer.writeLock.Lock()
+	defer consumer.writeLock.Unlock()
+	return consumer.lastWrite
+}
+
+func (consumer *Consumer) getSignKey() string {
+	if consumer.confOpts.SignKey != "" {
+		return consumer.confOpts.SignKey
+	}
+	return defaultSignKey
+}
+
+type cresolver struct {
+}
+
+func (s *cresolver) Resolve(confRaw string) (mq.IMQC, error) {
+	return NewConsumer(xmq.NewByRaw(confRaw))
+}
+
+func init() {
+	mq.RegisterConsumer("xmq", &cresolver{})
+}
@@ -0,0 +1,151 @@
+package xmq
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/micro-plat/hydra/components/queues/mq"
+	"github.com/micro-plat/hydra/conf/vars/queue/xmq"
+)
+
+//testServer 收到订阅指令后推送一条需要确认的消息，并记录收到的指令
+type testServer struct {
+	ln    net.Listener
+	cmds  chan *Message
+	conns chan net.Conn
+}
+
+func newTestServer(t *testing.T) *testServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &testServer{ln: ln, cmds: make(chan *Message, 100), conns: make(chan net.Conn, 10)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.conns <- conn
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *testServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		m := &Message{}
+		if err := json.Unmarshal(line, m); err != nil || m.Check("abc") != nil {
+			continue
+		}
+		s.cmds <- m
+		if m.CMD != cmdSubscribe {
+			continue
+		}
+		push := newMessage(m.QueueName, "order-1", 0)
+		push.Mode = 0
+		push.signKey = "abc"
+		v, _ := push.Make()
+		conn.Write([]byte(v))
+	}
+}
+
+func (s *testServer) wait(t *testing.T, cmd int) *Message {
+	for {
+		select {
+		case m := <-s.cmds:
+			if m.CMD == cmd {
+				return m
+			}
+		case <-time.After(time.Second * 2):
+			t.Fatalf("未收到指令:%d", cmd)
+			return nil
+		}
+	}
+}
+
+func init() {
+	checkInterval = time.Millisecond * 50
+}
+
+func TestConsumer_Consume(t *testing.T) {
+	server := newTestServer(t)
+	defer server.ln.Close()
+
+	consumer, err := NewConsumer(xmq.New(server.ln.Addr().String(), xmq.WithSignKey("abc")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := consumer.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer consumer.Close()
+
+	msgs := make(chan mq.IMQCMessage, 10)
+	if err := consumer.Consume("order", 2, func(m mq.IMQCMessage) { msgs <- m }); err != nil {
+		t.Fatal(err)
+	}
+	if sub := server.wait(t, cmdSubscribe); sub.QueueName != "order" || len(sub.Data) != 1 || sub.Data[0] != "2" {
+		t.Errorf("订阅指令有误:%+v", sub)
+	}
+
+	var m mq.IMQCMessage
+	select {
+	case m = <-msgs:
+	case <-time.After(time.Second * 2):
+		t.Fatal("未收到消息")
+	}
+	if m.GetMessage() != "order-1" {
+		t.Errorf("消息内容有误:%s", m.GetMessage())
+	}
+	if err := m.Ack(); err != nil {
+		t.Fatal(err)
+	}
+	if ack := server.wait(t, cmdAck); ack.SEQ != m.(*ConsumerMessage).message.SEQ {
+		t.Errorf("确认的消息序列号有误:%d", ack.SEQ)
+	}
+	server.wait(t, cmdHeartBit)
+
+	//服务器断开连接后自动重连并重新订阅
+	(<-server.conns).Close()
+	if sub := server.wait(t, cmdSubscribe); sub.QueueName != "order" {
+		t.Errorf("重新订阅的队列有误:%s", sub.QueueName)
+	}
+	select {
+	case <-msgs:
+	case <-time.After(time.Second * 2):
+		t.Fatal("重连后未收到消息")
+	}
+
+	consumer.UnConsume("order")
+	server.wait(t, cmdUnsubscribe)
+}
+
+func TestConsumer_Connect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	consumer, err := NewConsumer(xmq.New(addr, xmq.WithSignKey("abc")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer consumer.Close()
+	if err := consumer.Connect(); err == nil {
+		t.Error("无法连接到服务器时应返回错误")
+	}
+}
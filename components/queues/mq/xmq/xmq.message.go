@@ -16,6 +16,22 @@ import (
 
 var xmqSEQId int64 = 10000
 
+//xmq服务器已支持的指令
+const (
+	cmdSend     = 0  //发送消息
+	cmdHeartBit = 99 //心跳
+)
+
+//消费者扩展指令，现有xmq服务器仅支持发送与心跳，使用xmq消费者需服务器实现以下指令:
+//订阅后按预取数量推送cmd为0的消息(签名规则与发送一致)，mod为0的消息等待确认，
+//未确认或不确认的消息重新投递，连接断开后未确认的消息重新投递给其它订阅者
+const (
+	cmdSubscribe   = 1 //订阅队列，name为队列名称，data[0]为预取数量
+	cmdUnsubscribe = 2 //取消订阅，name为队列名称
+	cmdAck         = 3 //确认消息，seq为推送消息的序列号
+	cmdNack        = 4 //不确认消息，seq为推送消息的序列号，服务器重新投递
+)
+
 //Message 消息体
 type Message struct {
 	CMD       int      `json:"cmd"`  //0发送
@@ -33,10 +49,16 @@ const defaultSignKey = "sdfsdfsdfdsf"
 
 //newHeartBit 构建消息信息
 func newHeartBit() *Message {
+	return newCommand(cmdHeartBit, "")
+}
 
+//newCommand 构建不需要回复的指令
+func newCommand(cmd int, queueName string, data ...string) *Message {
 	r := &Message{
-		CMD:       99,
+		CMD:       cmd,
 		Mode:      1,
+		QueueName: queueName,
+		Data:      data,
 		Timestmap: time.Now().Unix(),
 		signKey:   defaultSignKey,
 	}
@@ -49,7 +71,7 @@ func newHeartBit() *Message {
 func newMessage(queueName string, msg string, timeout int) *Message {
 
 	r := &Message{
-		CMD:       0,
+		CMD:       cmdSend,
 		Mode:      1,
 		QueueName: queueName,
 		Data:      []string{msg},
@@ -64,6 +86,32 @@ func newMessage(queueName string, msg string, timeout int) *Message {
 
 //Make 构建消息
 func (x *Message) Make() (string, error) {
+	sign, err := x.makeSign()
+	if err != nil {
+		return "", err
+	}
+	x.Sign = sign
+	r, err := jsons.Marshal(x)
+	if err != nil {
+		return "", err
+	}
+	return string(r) + "\n", nil
+}
+
+//Check 检查服务器推送消息的签名
+func (x *Message) Check(signKey string) error {
+	x.signKey = signKey
+	sign, err := x.makeSign()
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(sign, x.Sign) {
+		return fmt.Errorf("消息签名错误:%d", x.SEQ)
+	}
+	return nil
+}
+
+func (x *Message) makeSign() (string, error) {
 	buff := &bytes.Buffer{}
 	buff.WriteString(strconv.Itoa(x.CMD))
 	buff.WriteString(fmt.Sprint(x.SEQ))
@@ -73,10 +121,36 @@ func (x *Message) Make() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	x.Sign = strings.ToUpper(md5.EncryptBytes(gbkValue))
-	r, err := jsons.Marshal(x)
-	if err != nil {
-		return "", err
+	return strings.ToUpper(md5.EncryptBytes(gbkValue)), nil
+}
+
+//ConsumerMessage 服务器推送给消费者的消息
+type ConsumerMessage struct {
+	consumer *Consumer
+	message  *Message
+	data     string
+}
+
+//Ack 确认消息，服务器推送的消息需要回复时发送确认指令
+func (m *ConsumerMessage) Ack() error {
+	return m.reply(cmdAck)
+}
+
+//Nack 不确认消息，服务器重新投递
+func (m *ConsumerMessage) Nack() error {
+	return m.reply(cmdNack)
+}
+
+func (m *ConsumerMessage) reply(cmd int) error {
+	if m.message.Mode != 0 {
+		return nil
 	}
-	return string(r) + "\n", nil
+	r := newCommand(cmd, m.message.QueueName)
+	r.SEQ = m.message.SEQ
+	return m.consumer.send(r)
+}
+
+//GetMessage 获取消息
+func (m *ConsumerMessage) GetMessage() string {
+	return m.data
 }
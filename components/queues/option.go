@@ -0,0 +1,37 @@
+package queues
+
+import (
+	"github.com/micro-plat/hydra/components/queues/mq"
+	"github.com/micro-plat/hydra/context"
+)
+
+//SendOption 消息发送选项
+type SendOption func(*mq.Envelope)
+
+//WithRequestID 设置请求编号，未设置时使用当前请求的编号
+func WithRequestID(requestID string) SendOption {
+	return func(e *mq.Envelope) {
+		e.Header[context.XRequestID] = requestID
+	}
+}
+
+//WithMessageID 设置消息编号，未设置时自动生成
+func WithMessageID(id string) SendOption {
+	return func(e *mq.Envelope) {
+		e.ID = id
+	}
+}
+
+//WithContentType 设置消息内容类型，非json类型的消息内容须为字符串或[]byte
+func WithContentType(contentType string) SendOption {
+	return func(e *mq.Envelope) {
+		e.ContentType = contentType
+	}
+}
+
+//WithHeader 设置消息头信息
+func WithHeader(name string, value string) SendOption {
+	return func(e *mq.Envelope) {
+		e.Header[name] = value
+	}
+}
@@ -10,7 +10,6 @@ import (
 	rc "github.com/micro-plat/hydra/context"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/lib4go/concurrent/cmap"
-	"github.com/micro-plat/lib4go/types"
 )
 
 var requests = cmap.New(4)
@@ -46,13 +45,14 @@ func NewRequest(version int32, conf *rpcconf.RPCConf) *Request {
 //Request request 请求
 func (r *Request) Request(service string, input interface{}, opts ...rpc.RequestOption) (res *rpc.Response, err error) {
 
-	//处理链路跟踪
-	nopts := make([]rpc.RequestOption, 0, 2)
-	nopts = append(nopts, opts...)
-	nopts = append(opts, rpc.WithXRequestID(global.RID.GetXRequestID()))
+	//继承当前请求的编号与链路跟踪信息，不随请求结束而撤销
+	ctx := context.Background()
+	if c, ok := rc.GetContext(); ok {
+		ctx = rc.Detach(c.Context())
+	}
 
 	//发送请求
-	return r.RequestByCtx(context.Background(), service, input, opts...)
+	return r.RequestByCtx(ctx, service, input, opts...)
 }
 
 //Swap 将当前请求参数作为RPC参数并发送RPC请求
@@ -96,9 +96,27 @@ func (r *Request) RequestByCtx(ctx context.Context, service string, input interf
 
 	client := c.(*rpc.Client)
 	nopts := make([]rpc.RequestOption, 0, len(opts)+1)
+	nopts = append(nopts, rpc.WithXRequestID(rc.GetRequestID(ctx)))
 	nopts = append(nopts, opts...)
-	if reqid := types.GetString(ctx.Value(rc.XRequestID)); reqid != "" {
-		nopts = append(nopts, rpc.WithXRequestID(reqid))
+
+	//记录请求耗时
+	profiling := rc.Profiling(rc.KindRPC, rservice, ctx)
+	defer func() { profiling(err) }()
+
+	//处理链路跟踪
+	if span := rc.StartSpan("RPC "+rservice, ctx); span != nil {
+		defer func() {
+			if res != nil {
+				span.SetTag("rpc.status_code", res.Status)
+			}
+			span.SetError(err)
+			span.End()
+		}()
+		span.SetTag("rpc.service", rservice)
+		span.SetTag("rpc.platform", platName)
+		for k, v := range span.GetHeaders() {
+			nopts = append(nopts, rpc.WithHeader(k, v))
+		}
 	}
 	fm := pkgs.GetString(input)
 	return client.RequestByString(ctx, rservice, fm, nopts...)
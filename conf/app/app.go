@@ -6,17 +6,26 @@ import (
 	"github.com/micro-plat/hydra/conf"
 	"github.com/micro-plat/hydra/conf/server"
 	"github.com/micro-plat/hydra/conf/server/acl/blacklist"
+	"github.com/micro-plat/hydra/conf/server/acl/fault"
 	"github.com/micro-plat/hydra/conf/server/acl/limiter"
+	"github.com/micro-plat/hydra/conf/server/acl/mirror"
 	"github.com/micro-plat/hydra/conf/server/acl/proxy"
 	"github.com/micro-plat/hydra/conf/server/acl/whitelist"
 	"github.com/micro-plat/hydra/conf/server/apm"
+	"github.com/micro-plat/hydra/conf/server/audit"
 	"github.com/micro-plat/hydra/conf/server/auth/apikey"
 	"github.com/micro-plat/hydra/conf/server/auth/basic"
 	"github.com/micro-plat/hydra/conf/server/auth/jwt"
 	"github.com/micro-plat/hydra/conf/server/auth/ras"
+	xcache "github.com/micro-plat/hydra/conf/server/cache"
+	"github.com/micro-plat/hydra/conf/server/delay"
 	"github.com/micro-plat/hydra/conf/server/header"
+	"github.com/micro-plat/hydra/conf/server/history"
+	"github.com/micro-plat/hydra/conf/server/idempotency"
 	"github.com/micro-plat/hydra/conf/server/metric"
 	"github.com/micro-plat/hydra/conf/server/mqc"
+	"github.com/micro-plat/hydra/conf/server/profile"
+	"github.com/micro-plat/hydra/conf/server/prometheus"
 	"github.com/micro-plat/hydra/conf/server/queue"
 	"github.com/micro-plat/hydra/conf/server/render"
 	"github.com/micro-plat/hydra/conf/server/router"
@@ -36,6 +45,8 @@ type IAPPConf interface {
 	GetMQCQueueConf() (*queue.Queues, error)
 
 	GetCRONTaskConf() (*task.Tasks, error)
+	GetCRONHistoryConf() (*history.History, error)
+	GetCRONDelayConf() (*delay.Delay, error)
 
 	GetRouterConf() (*router.Routers, error)
 	GetJWTConf() (*jwt.JWTAuth, error)
@@ -51,6 +62,13 @@ type IAPPConf interface {
 	GetLimiterConf() (*limiter.Limiter, error)
 	GetProxyConf() (*proxy.Proxy, error)
 	GetAPMConf() (*apm.APM, error)
+	GetIdempotencyConf() (*idempotency.Idempotency, error)
+	GetCacheConf() (*xcache.Cache, error)
+	GetMirrorConf() (*mirror.Mirror, error)
+	GetFaultConf() (*fault.Fault, error)
+	GetPrometheusConf() (*prometheus.Prometheus, error)
+	GetAuditConf() (*audit.Audit, error)
+	GetProfileConf() (*profile.Profile, error)
 	//获取远程日志配置
 	GetRLogConf() (*rlog.Layout, error)
 	Close() error
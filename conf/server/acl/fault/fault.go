@@ -0,0 +1,166 @@
+/*
+故障注入配置，按规则对指定比例的请求注入延时、错误状态码、中断连接或panic，用于演练下游服务故障。
+未配置时不启用，配置变更后自动生效。
+*/
+
+package fault
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/registry"
+)
+
+const (
+	//ParNodeName fault配置父节点名
+	ParNodeName = "acl"
+
+	//SubNodeName fault配置子节点名
+	SubNodeName = "fault"
+)
+
+//Fault 故障注入配置
+type Fault struct {
+
+	//Rules 故障规则，按顺序匹配，使用第一个满足条件的规则
+	Rules []*Rule `json:"rules,omitempty" valid:"required" toml:"rules,omitempty"`
+
+	Disable bool `json:"disable,omitempty" toml:"disable,omitempty"`
+}
+
+//Rule 故障规则
+type Rule struct {
+
+	//Name 规则名称
+	Name string `json:"name,omitempty" toml:"name,omitempty"`
+
+	//Paths 请求路径(mqc为队列名称,cron为服务名称)，未设置时匹配所有请求
+	Paths []string `json:"paths,omitempty" toml:"paths,omitempty"`
+
+	//Headers 请求头，值为*时只检查请求头是否存在
+	Headers map[string]string `json:"headers,omitempty" toml:"headers,omitempty"`
+
+	//Percent 注入故障的请求比例(0-100)
+	Percent int `json:"percent" valid:"range(0|100)" toml:"percent"`
+
+	//Delay 注入的延时时长,如:500ms,3s
+	Delay string `json:"delay,omitempty" toml:"delay,omitempty"`
+
+	//Status 返回的错误状态码
+	Status int `json:"status,omitempty" valid:"range(0|999)" toml:"status,omitempty"`
+
+	//Content 返回的错误内容
+	Content string `json:"content,omitempty" toml:"content,omitempty"`
+
+	//Abort 中断连接(http请求直接关闭连接，其它请求不返回内容)
+	Abort bool `json:"abort,omitempty" toml:"abort,omitempty"`
+
+	//Panic 触发panic
+	Panic bool `json:"panic,omitempty" toml:"panic,omitempty"`
+
+	delay time.Duration
+	p     *conf.PathMatch
+}
+
+//New 构建故障注入配置，规则配置有误时由GetConf加载时返回错误
+func New(opts ...Option) *Fault {
+	f := &Fault{Rules: make([]*Rule, 0, 1)}
+	for _, opt := range opts {
+		opt(f)
+	}
+	for i, r := range f.Rules {
+		if r.Name == "" {
+			r.Name = fmt.Sprint(i)
+		}
+		r.init()
+	}
+	return f
+}
+
+//NewRule 构建故障规则
+func NewRule(percent int, opts ...RuleOption) *Rule {
+	r := &Rule{Percent: percent}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+//Match 检查当前请求是否需要注入故障，已按比例进行抽样
+func (f *Fault) Match(path string, header func(string) string) (*Rule, bool) {
+	if f.Disable {
+		return nil, false
+	}
+	for _, r := range f.Rules {
+		if r.match(path, header) {
+			return r, rand.Intn(100) < r.Percent
+		}
+	}
+	return nil, false
+}
+
+//GetDelay 获取注入的延时时长
+func (r *Rule) GetDelay() time.Duration {
+	return r.delay
+}
+
+func (r *Rule) init() error {
+	if r.Delay != "" {
+		d, err := time.ParseDuration(r.Delay)
+		if err != nil {
+			return fmt.Errorf("规则%s的延时时长%s有误:%v", r.Name, r.Delay, err)
+		}
+		r.delay = d
+	}
+	if r.delay == 0 && r.Status == 0 && !r.Abort && !r.Panic {
+		return fmt.Errorf("规则%s未指定故障类型(delay,status,abort,panic)", r.Name)
+	}
+	r.p = conf.NewPathMatch(r.Paths...)
+	return nil
+}
+
+func (r *Rule) match(path string, header func(string) string) bool {
+	if len(r.Paths) > 0 {
+		if ok, _ := r.p.Match(path); !ok {
+			return false
+		}
+	}
+	for k, v := range r.Headers {
+		value := header(k)
+		if value == "" || (v != "*" && v != value) {
+			return false
+		}
+	}
+	return true
+}
+
+//GetConf 获取故障注入配置
+func GetConf(cnf conf.IServerConf) (*Fault, error) {
+	f := &Fault{}
+	_, err := cnf.GetSubObject(registry.Join(ParNodeName, SubNodeName), f)
+	if err == conf.ErrNoSetting || len(f.Rules) == 0 {
+		return &Fault{Disable: true}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("acl.fault配置格式有误:%v", err)
+	}
+	if b, err := govalidator.ValidateStruct(f); !b {
+		return nil, fmt.Errorf("acl.fault配置数据有误:%v", err)
+	}
+	for i, r := range f.Rules {
+		if r.Name == "" {
+			r.Name = fmt.Sprint(i)
+		}
+		if b, err := govalidator.ValidateStruct(r); !b {
+			return nil, fmt.Errorf("acl.fault规则%s配置数据有误:%v", r.Name, err)
+		}
+		if err := r.init(); err != nil {
+			return nil, fmt.Errorf("acl.fault配置数据有误:%v", err)
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,55 @@
+package fault
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestFault_Match(t *testing.T) {
+	header := func(k string) string {
+		return map[string]string{"X-Chaos": "1"}[k]
+	}
+	tests := []struct {
+		name  string
+		fault *Fault
+		path  string
+		want  bool
+		rule  string
+	}{
+		{name: "1. 未设置规则", fault: New(), path: "/order/create", want: false},
+		{name: "2. 匹配所有请求", fault: New(WithRule(NewRule(100, WithName("a"), WithStatus(500)))), path: "/order/create", want: true, rule: "a"},
+		{name: "3. 匹配路径", fault: New(WithRule(NewRule(100, WithName("a"), WithPaths("/order/*"), WithDelay("1ms")))), path: "/order/create", want: true, rule: "a"},
+		{name: "4. 不匹配的路径", fault: New(WithRule(NewRule(100, WithName("a"), WithPaths("/user/*"), WithAbort()))), path: "/order/create", want: false},
+		{name: "5. 匹配请求头", fault: New(WithRule(NewRule(100, WithName("a"), WithHeader("X-Chaos", "*"), WithPanic()))), path: "/order/create", want: true, rule: "a"},
+		{name: "6. 不匹配的请求头", fault: New(WithRule(NewRule(100, WithName("a"), WithHeader("X-Chaos", "2"), WithPanic()))), path: "/order/create", want: false},
+		{name: "7. 比例为0", fault: New(WithRule(NewRule(0, WithName("a"), WithStatus(500)))), path: "/order/create", want: false, rule: "a"},
+		{name: "8. 禁用配置", fault: New(WithRule(NewRule(100, WithStatus(500))), WithDisable()), path: "/order/create", want: false},
+	}
+	for _, tt := range tests {
+		rule, got := tt.fault.Match(tt.path, header)
+		assert.Equal(t, tt.want, got, tt.name)
+		if tt.rule != "" {
+			assert.Equal(t, tt.rule, rule.Name, tt.name)
+		}
+	}
+}
+
+func TestRule_init(t *testing.T) {
+	assert.NotEqual(t, nil, NewRule(100).init(), "未指定故障类型")
+	assert.NotEqual(t, nil, NewRule(100, WithDelay("abc")).init(), "延时时长有误")
+	r := NewRule(100, WithDelay("20ms"))
+	assert.Equal(t, nil, r.init(), "正确的延时时长")
+	assert.Equal(t, int64(20000000), int64(r.GetDelay()), "延时时长")
+}
+
+func TestNew(t *testing.T) {
+	f := New(WithRule(NewRule(100)))
+	assert.Equal(t, "0", f.Rules[0].Name, "1. 默认规则名称")
+	assert.Equal(t, "规则0未指定故障类型(delay,status,abort,panic)", f.Rules[0].init().Error(), "1. 未指定故障类型")
+	f = New(WithRule(NewRule(100, WithName("a"), WithDelay("abc"))))
+	assert.NotEqual(t, nil, f.Rules[0].init(), "2. 延时时长有误")
+	f = New(WithRule(NewRule(100, WithDelay("20ms"))))
+	assert.Equal(t, "0", f.Rules[0].Name, "3. 默认规则名称")
+	assert.Equal(t, int64(20000000), int64(f.Rules[0].GetDelay()), "3. 延时时长")
+}
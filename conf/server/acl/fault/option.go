@@ -0,0 +1,83 @@
+package fault
+
+//Option 配置选项
+type Option func(*Fault)
+
+//WithRule 添加故障规则
+func WithRule(rules ...*Rule) Option {
+	return func(f *Fault) {
+		f.Rules = append(f.Rules, rules...)
+	}
+}
+
+//WithDisable 禁用配置
+func WithDisable() Option {
+	return func(f *Fault) {
+		f.Disable = true
+	}
+}
+
+//WithEnable 启用配置
+func WithEnable() Option {
+	return func(f *Fault) {
+		f.Disable = false
+	}
+}
+
+//RuleOption 故障规则选项
+type RuleOption func(*Rule)
+
+//WithName 设置规则名称
+func WithName(name string) RuleOption {
+	return func(r *Rule) {
+		r.Name = name
+	}
+}
+
+//WithPaths 设置需要注入故障的请求路径
+func WithPaths(paths ...string) RuleOption {
+	return func(r *Rule) {
+		r.Paths = append(r.Paths, paths...)
+	}
+}
+
+//WithHeader 设置需要匹配的请求头，值为*时只检查是否存在
+func WithHeader(name string, value string) RuleOption {
+	return func(r *Rule) {
+		if r.Headers == nil {
+			r.Headers = make(map[string]string)
+		}
+		r.Headers[name] = value
+	}
+}
+
+//WithDelay 注入延时,如:500ms,3s
+func WithDelay(delay string) RuleOption {
+	return func(r *Rule) {
+		r.Delay = delay
+	}
+}
+
+//WithStatus 返回指定的错误状态码与内容
+func WithStatus(status int, content ...string) RuleOption {
+	return func(r *Rule) {
+		r.Status = status
+		if len(content) > 0 {
+			r.Content = content[0]
+		}
+	}
+}
+
+//WithAbort 中断连接
+func WithAbort() RuleOption {
+	return func(r *Rule) {
+		r.Abort = true
+	}
+}
+
+//WithPanic 触发panic
+func WithPanic() RuleOption {
+	return func(r *Rule) {
+		r.Panic = true
+	}
+}
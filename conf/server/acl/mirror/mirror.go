@@ -0,0 +1,168 @@
+/*
+流量复制配置，将匹配路径的请求按采样比例异步复制到指定的上游集群(影子请求)，
+影子请求的响应结果将被丢弃，不影响主请求的处理与响应时长。启用对比时记录主请求与影子请求的
+状态码、处理时长与响应内容差异。
+*/
+
+package mirror
+
+import (
+	"fmt"
+	"math/rand"
+	"net/url"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/registry"
+	"github.com/micro-plat/lib4go/concurrent/cmap"
+	"github.com/micro-plat/lib4go/types"
+)
+
+const (
+	//ParNodeName mirror配置父节点名
+	ParNodeName = "acl"
+
+	//SubNodeName mirror配置子节点名
+	SubNodeName = "mirror"
+
+	//HeaderName 影子请求的标识头，包含此请求头的请求不再复制
+	HeaderName = "X-Hydra-Mirror"
+
+	//DefConcurrency 默认影子请求最大并发数
+	DefConcurrency = 100
+)
+
+//clusters 上游集群缓存，按集群路径缓存，配置版本变化后重新获取
+var clusters = cmap.New(2)
+
+type upCluster struct {
+	version int32
+	cluster conf.ICluster
+}
+
+//Mirror 流量复制配置
+type Mirror struct {
+
+	//UpCluster 接收影子请求的上游集群名称
+	UpCluster string `json:"upcluster" valid:"ascii,required" toml:"upcluster"`
+
+	//Paths 需要复制的请求路径，未设置时复制所有请求
+	Paths []string `json:"paths,omitempty" toml:"paths,omitempty"`
+
+	//Percent 采样比例(1-100)
+	Percent int `json:"percent,omitempty" valid:"range(0|100)" toml:"percent,omitempty"`
+
+	//Timeout 影子请求超时时长(秒)
+	Timeout int `json:"timeout,omitempty" toml:"timeout,omitempty"`
+
+	//Compare 是否对比并记录主请求与影子请求的状态码、处理时长与响应内容差异
+	Compare bool `json:"compare,omitempty" toml:"compare,omitempty"`
+
+	//Concurrency 影子请求最大并发数，超过时丢弃当前影子请求
+	Concurrency int `json:"concurrency,omitempty" valid:"range(0|10000)" toml:"concurrency,omitempty"`
+
+	Disable bool `json:"disable,omitempty" toml:"disable,omitempty"`
+	p       *conf.PathMatch
+	c       conf.IServerConf
+	version int32
+	sem     chan struct{}
+}
+
+//New 构建流量复制配置
+func New(upcluster string, opts ...Option) *Mirror {
+	m := &Mirror{
+		UpCluster:   upcluster,
+		Percent:     100,
+		Timeout:     5,
+		Concurrency: DefConcurrency,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	m.p = conf.NewPathMatch(m.Paths...)
+	m.sem = make(chan struct{}, m.Concurrency)
+	return m
+}
+
+//IsMirror 检查当前请求是否需要复制，已按采样比例进行抽样
+func (m *Mirror) IsMirror(path string) bool {
+	if m.Disable || m.p == nil {
+		return false
+	}
+	if len(m.Paths) > 0 {
+		if ok, _ := m.p.Match(path); !ok {
+			return false
+		}
+	}
+	return rand.Intn(100) < m.Percent
+}
+
+//Acquire 获取影子请求的执行许可，已达到最大并发数时返回false
+func (m *Mirror) Acquire() bool {
+	select {
+	case m.sem <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+//Release 释放影子请求的执行许可
+func (m *Mirror) Release() {
+	<-m.sem
+}
+
+//Next 获取上游集群的下一个服务器地址
+func (m *Mirror) Next() (*url.URL, error) {
+	c, err := m.getCluster()
+	if err != nil {
+		return nil, fmt.Errorf("获取集群%s失败:%w", m.UpCluster, err)
+	}
+	node, ok := c.Next()
+	if !ok {
+		return nil, fmt.Errorf("集群%s无可用服务器", m.UpCluster)
+	}
+	path := fmt.Sprintf("http://%s:%s", node.GetHost(), node.GetPort())
+	u, err := url.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("集群的服务器地址不合法:%s %s", path, err)
+	}
+	return u, nil
+}
+
+//GetConf 获取流量复制配置
+func GetConf(cnf conf.IServerConf) (*Mirror, error) {
+	m := &Mirror{}
+	_, err := cnf.GetSubObject(registry.Join(ParNodeName, SubNodeName), m)
+	if err == conf.ErrNoSetting {
+		return &Mirror{Disable: true}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("acl.mirror配置格式有误:%v", err)
+	}
+	if b, err := govalidator.ValidateStruct(m); !b {
+		return nil, fmt.Errorf("acl.mirror配置数据有误:%v", err)
+	}
+	m.Percent = types.DecodeInt(m.Percent, 0, 100, m.Percent)
+	m.Timeout = types.DecodeInt(m.Timeout, 0, 5, m.Timeout)
+	m.Concurrency = types.DecodeInt(m.Concurrency, 0, DefConcurrency, m.Concurrency)
+	m.p = conf.NewPathMatch(m.Paths...)
+	m.sem = make(chan struct{}, m.Concurrency)
+	m.c = cnf
+	m.version = cnf.GetVersion()
+	return m, nil
+}
+
+//getCluster 获取上游集群，服务器配置版本变化后重新获取
+func (m *Mirror) getCluster() (conf.ICluster, error) {
+	key := m.c.GetServerPubPath(m.UpCluster)
+	if v, ok := clusters.Get(key); ok && v.(*upCluster).version == m.version {
+		return v.(*upCluster).cluster, nil
+	}
+	c, err := m.c.GetCluster(m.UpCluster)
+	if err != nil {
+		return nil, err
+	}
+	clusters.Set(key, &upCluster{version: m.version, cluster: c})
+	return c, nil
+}
@@ -0,0 +1,35 @@
+package mirror
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestMirror_IsMirror(t *testing.T) {
+	tests := []struct {
+		name   string
+		mirror *Mirror
+		path   string
+		want   bool
+	}{
+		{name: "1. 未设置路径", mirror: New("gray"), path: "/order/create", want: true},
+		{name: "2. 匹配路径", mirror: New("gray", WithPaths("/order/*")), path: "/order/create", want: true},
+		{name: "3. 不匹配的路径", mirror: New("gray", WithPaths("/order/*")), path: "/user/create", want: false},
+		{name: "4. 采样比例为0", mirror: New("gray", WithPercent(0)), path: "/order/create", want: false},
+		{name: "5. 禁用配置", mirror: New("gray", WithDisable()), path: "/order/create", want: false},
+	}
+	for _, tt := range tests {
+		got := tt.mirror.IsMirror(tt.path)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
+
+func TestMirror_Acquire(t *testing.T) {
+	m := New("gray", WithConcurrency(2))
+	assert.Equal(t, true, m.Acquire(), "1. 获取许可")
+	assert.Equal(t, true, m.Acquire(), "2. 获取许可")
+	assert.Equal(t, false, m.Acquire(), "3. 超过最大并发数")
+	m.Release()
+	assert.Equal(t, true, m.Acquire(), "4. 释放后获取许可")
+}
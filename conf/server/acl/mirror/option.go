@@ -0,0 +1,53 @@
+package mirror
+
+//Option 配置选项
+type Option func(*Mirror)
+
+//WithPaths 设置需要复制的请求路径
+func WithPaths(paths ...string) Option {
+	return func(m *Mirror) {
+		m.Paths = append(m.Paths, paths...)
+	}
+}
+
+//WithPercent 设置采样比例(1-100)
+func WithPercent(percent int) Option {
+	return func(m *Mirror) {
+		m.Percent = percent
+	}
+}
+
+//WithTimeout 设置影子请求超时时长(秒)
+func WithTimeout(second int) Option {
+	return func(m *Mirror) {
+		m.Timeout = second
+	}
+}
+
+//WithCompare 对比并记录主请求与影子请求的处理结果
+func WithCompare() Option {
+	return func(m *Mirror) {
+		m.Compare = true
+	}
+}
+
+//WithConcurrency 设置影子请求最大并发数
+func WithConcurrency(n int) Option {
+	return func(m *Mirror) {
+		m.Concurrency = n
+	}
+}
+
+//WithDisable 禁用配置
+func WithDisable() Option {
+	return func(m *Mirror) {
+		m.Disable = true
+	}
+}
+
+//WithEnable 启用配置
+func WithEnable() Option {
+	return func(m *Mirror) {
+		m.Disable = false
+	}
+}
@@ -0,0 +1,107 @@
+package proxy
+
+//Option 配置选项
+type Option func(*Proxy)
+
+//WithScript 设置返回upcluster的tengo脚本，脚本返回结果优先于灰度规则
+func WithScript(script string) Option {
+	return func(p *Proxy) {
+		p.Script = script
+	}
+}
+
+//WithRule 添加灰度规则
+func WithRule(rules ...*Rule) Option {
+	return func(p *Proxy) {
+		p.Rules = append(p.Rules, rules...)
+	}
+}
+
+//WithDisable 禁用配置
+func WithDisable() Option {
+	return func(p *Proxy) {
+		p.Disable = true
+	}
+}
+
+//WithEnable 启用配置
+func WithEnable() Option {
+	return func(p *Proxy) {
+		p.Disable = false
+	}
+}
+
+//RuleOption 灰度规则选项
+type RuleOption func(*Rule)
+
+//WithName 设置规则名称
+func WithName(name string) RuleOption {
+	return func(r *Rule) {
+		r.Name = name
+	}
+}
+
+//WithPaths 设置需要匹配的请求路径
+func WithPaths(paths ...string) RuleOption {
+	return func(r *Rule) {
+		r.Paths = append(r.Paths, paths...)
+	}
+}
+
+//WithHeader 设置需要匹配的请求头，值为*时只检查是否存在
+func WithHeader(name string, value string) RuleOption {
+	return func(r *Rule) {
+		if r.Headers == nil {
+			r.Headers = make(map[string]string)
+		}
+		r.Headers[name] = value
+	}
+}
+
+//WithCookie 设置需要匹配的cookie，值为*时只检查是否存在
+func WithCookie(name string, value string) RuleOption {
+	return func(r *Rule) {
+		if r.Cookies == nil {
+			r.Cookies = make(map[string]string)
+		}
+		r.Cookies[name] = value
+	}
+}
+
+//WithIPs 设置需要匹配的客户端IP范围
+func WithIPs(ips ...string) RuleOption {
+	return func(r *Rule) {
+		r.IPs = append(r.IPs, ips...)
+	}
+}
+
+//WithClaim 设置需要匹配的jwt用户信息字段，值为*时只检查是否存在
+func WithClaim(name string, value string) RuleOption {
+	return func(r *Rule) {
+		if r.Claims == nil {
+			r.Claims = make(map[string]string)
+		}
+		r.Claims[name] = value
+	}
+}
+
+//WithUpCluster 满足条件的请求全部转到指定的上游集群
+func WithUpCluster(name string) RuleOption {
+	return func(r *Rule) {
+		r.UpCluster = name
+	}
+}
+
+//WithSplit 满足条件的请求按比例转到指定的上游集群
+func WithSplit(upcluster string, weight int) RuleOption {
+	return func(r *Rule) {
+		r.Splits = append(r.Splits, &Split{UpCluster: upcluster, Weight: weight})
+	}
+}
+
+//WithSticky 设置粘性分流依据(user,ip,cookie:[name],header:[name])
+func WithSticky(sticky string) RuleOption {
+	return func(r *Rule) {
+		r.Sticky = sticky
+	}
+}
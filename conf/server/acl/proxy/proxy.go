@@ -1,8 +1,11 @@
 package proxy
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 
+	"github.com/asaskevich/govalidator"
 	"github.com/micro-plat/hydra/conf"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/hydra/registry"
@@ -18,30 +21,71 @@ const (
 
 	//脚本中的上游集群参数名称
 	upclusterName = "upcluster"
+
+	//SourceScript 由脚本确定的上游集群
+	SourceScript = "script"
+
+	//SourceRule 由灰度规则确定的上游集群
+	SourceRule = "rule"
 )
 
-//Proxy 代理设置
+//Proxy 代理设置，配置内容为tengo脚本时按脚本返回的upcluster转发请求，
+//配置内容为json时按声明的灰度规则转发请求，同时指定脚本时优先使用脚本的结果
 type Proxy struct {
 
+	//Script 返回upcluster的tengo脚本
+	Script string `json:"script,omitempty" toml:"script,omitempty"`
+
+	//Rules 灰度规则，按顺序匹配，使用第一个满足条件的规则
+	Rules []*Rule `json:"rules,omitempty" toml:"rules,omitempty"`
+
 	//Disable 禁用
-	Disable bool `json:""-`
+	Disable bool `json:"disable,omitempty" toml:"disable,omitempty"`
 	c       conf.IServerConf
 	tengo   *tgo.VM
 }
 
-//Check 检查当前是否需要转到上游服务器处理
-func (g *Proxy) Check() (*UpCluster, bool, error) {
+//Decision 当前请求的分流结果
+type Decision struct {
 
-	//执行脚本，检查当前请求是否需要转到上游服务器
-	result, err := g.tengo.Run()
-	if err != nil {
-		return nil, false, err
+	//Source 分流依据(script,rule)
+	Source string
+
+	//Rule 满足条件的规则名称
+	Rule string
+
+	//UpCluster 上游集群名称，为空时由当前集群处理
+	UpCluster string
+
+	//Bucket 分流桶号，脚本分流时为-1
+	Bucket int
+}
+
+//String 分流结果描述
+func (d *Decision) String() string {
+	return fmt.Sprintf("source:%s rule:%s upcluster:%s bucket:%d", d.Source, d.Rule, d.UpCluster, d.Bucket)
+}
+
+//New 构建声明式灰度配置
+func New(opts ...Option) *Proxy {
+	p := &Proxy{Rules: make([]*Rule, 0, 1)}
+	for _, opt := range opts {
+		opt(p)
 	}
+	return p
+}
+
+//Check 检查当前是否需要转到上游服务器处理，未满足任何规则时Decision为nil
+func (g *Proxy) Check(req IRequest) (*UpCluster, *Decision, error) {
 
-	//获取脚本执行结果
-	upstream := result.GetString(upclusterName)
+	//获取分流结果，检查当前请求是否需要转到上游服务器
+	decision, err := g.decide(req)
+	if err != nil || decision == nil {
+		return nil, decision, err
+	}
+	upstream := decision.UpCluster
 	if upstream == "" || upstream == g.c.GetClusterName() {
-		return nil, false, nil
+		return nil, decision, nil
 	}
 
 	//保存到缓存，或从缓存获取上游集群信息
@@ -53,10 +97,30 @@ func (g *Proxy) Check() (*UpCluster, bool, error) {
 		return &UpCluster{c: up, name: upstream}, nil
 	})
 	if err != nil {
-		return nil, false, err
+		return nil, decision, err
 	}
-	return cluster.(*UpCluster), true, nil
+	return cluster.(*UpCluster), decision, nil
+
+}
 
+//decide 优先使用脚本结果，脚本未返回上游集群时按灰度规则分流
+func (g *Proxy) decide(req IRequest) (*Decision, error) {
+	if g.tengo != nil {
+		result, err := g.tengo.Run()
+		if err != nil {
+			return nil, err
+		}
+		if upstream := result.GetString(upclusterName); upstream != "" {
+			return &Decision{Source: SourceScript, UpCluster: upstream, Bucket: -1}, nil
+		}
+	}
+	for _, r := range g.Rules {
+		if r.Match(req) {
+			upstream, bucket := r.Select(req)
+			return &Decision{Source: SourceRule, Rule: r.Name, UpCluster: upstream, Bucket: bucket}, nil
+		}
+	}
+	return nil, nil
 }
 
 //GetConf 获取Proxy
@@ -69,10 +133,41 @@ func GetConf(cnf conf.IServerConf) (*Proxy, error) {
 		return nil, fmt.Errorf("acl.proxy配置有误:%v", err)
 	}
 
-	proxy := &Proxy{c: cnf}
-	proxy.tengo, err = tgo.New(string(script.GetRaw()), tgo.WithModule(global.GetTGOModules()...))
-	if err != nil {
-		return nil, fmt.Errorf("acl.proxy脚本错误:%v", err)
+	//非json格式的配置作为脚本处理
+	proxy := &Proxy{}
+	raw := strings.TrimSpace(string(script.GetRaw()))
+	if strings.HasPrefix(raw, "{") {
+		if err := json.Unmarshal([]byte(raw), proxy); err != nil {
+			return nil, fmt.Errorf("acl.proxy配置格式有误:%v", err)
+		}
+	} else {
+		proxy.Script = raw
+	}
+	if proxy.Disable {
+		return &Proxy{Disable: true}, nil
+	}
+	proxy.c = cnf
+	for i, r := range proxy.Rules {
+		if r.Name == "" {
+			r.Name = fmt.Sprint(i)
+		}
+		if b, err := govalidator.ValidateStruct(r); !b {
+			return nil, fmt.Errorf("acl.proxy规则%s配置数据有误:%v", r.Name, err)
+		}
+		for _, s := range r.Splits {
+			if b, err := govalidator.ValidateStruct(s); !b {
+				return nil, fmt.Errorf("acl.proxy规则%s分流配置数据有误:%v", r.Name, err)
+			}
+		}
+		if err := r.init(); err != nil {
+			return nil, fmt.Errorf("acl.proxy配置数据有误:%v", err)
+		}
+	}
+	if proxy.Script != "" {
+		proxy.tengo, err = tgo.New(proxy.Script, tgo.WithModule(global.GetTGOModules()...))
+		if err != nil {
+			return nil, fmt.Errorf("acl.proxy脚本错误:%v", err)
+		}
 	}
 	return proxy, nil
 }
@@ -0,0 +1,207 @@
+package proxy
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net"
+	"strings"
+
+	"github.com/micro-plat/hydra/conf"
+)
+
+const (
+	//StickyUser 按用户信息进行粘性分流
+	StickyUser = "user"
+
+	//StickyIP 按客户端IP进行粘性分流
+	StickyIP = "ip"
+
+	//StickyCookiePrefix 按cookie进行粘性分流的前缀，如:cookie:uid
+	StickyCookiePrefix = "cookie:"
+
+	//StickyHeaderPrefix 按请求头进行粘性分流的前缀，如:header:X-Uid
+	StickyHeaderPrefix = "header:"
+)
+
+//IRequest 灰度规则匹配所需的请求信息
+type IRequest interface {
+	GetPath() string
+	GetHeader(string) string
+	GetCookie(string) string
+	GetClientIP() string
+	GetUser() string
+	GetClaim(string) string
+}
+
+//Split 按百分比分流的上游集群
+type Split struct {
+
+	//UpCluster 上游集群名称
+	UpCluster string `json:"upcluster" valid:"ascii,required" toml:"upcluster"`
+
+	//Weight 分流比例(0-100)
+	Weight int `json:"weight" valid:"range(0|100)" toml:"weight"`
+}
+
+//Rule 灰度规则，所有已配置的条件均满足时规则生效
+type Rule struct {
+
+	//Name 规则名称
+	Name string `json:"name,omitempty" toml:"name,omitempty"`
+
+	//Paths 请求路径，支持*,**模糊匹配
+	Paths []string `json:"paths,omitempty" toml:"paths,omitempty"`
+
+	//Headers 请求头，值为*时只检查请求头是否存在
+	Headers map[string]string `json:"headers,omitempty" toml:"headers,omitempty"`
+
+	//Cookies cookie，值为*时只检查cookie是否存在
+	Cookies map[string]string `json:"cookies,omitempty" toml:"cookies,omitempty"`
+
+	//IPs 客户端IP范围，支持CIDR(10.0.0.0/8)与*模糊匹配(192.168.*.*)
+	IPs []string `json:"ips,omitempty" toml:"ips,omitempty"`
+
+	//Claims jwt中的用户信息字段，值为*时只检查字段是否存在
+	Claims map[string]string `json:"claims,omitempty" toml:"claims,omitempty"`
+
+	//UpCluster 满足条件的请求全部转到的上游集群
+	UpCluster string `json:"upcluster,omitempty" valid:"ascii" toml:"upcluster,omitempty"`
+
+	//Splits 满足条件的请求按比例转到的上游集群，比例之和不足100时剩余的请求由当前集群处理
+	Splits []*Split `json:"splits,omitempty" toml:"splits,omitempty"`
+
+	//Sticky 粘性分流依据(user,ip,cookie:[name],header:[name])，未设置时随机分流
+	Sticky string `json:"sticky,omitempty" valid:"ascii" toml:"sticky,omitempty"`
+
+	pathMatch *conf.PathMatch
+	ipMatch   *conf.PathMatch
+	ipNets    []*net.IPNet
+}
+
+//NewRule 构建灰度规则
+func NewRule(opts ...RuleOption) *Rule {
+	r := &Rule{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+//init 检查规则并构建匹配器
+func (r *Rule) init() error {
+	if r.UpCluster == "" && len(r.Splits) == 0 {
+		return fmt.Errorf("规则%s未指定上游集群", r.Name)
+	}
+	total := 0
+	for _, s := range r.Splits {
+		total += s.Weight
+	}
+	if total > 100 {
+		return fmt.Errorf("规则%s分流比例之和不能超过100:%d", r.Name, total)
+	}
+	if len(r.Paths) > 0 {
+		r.pathMatch = conf.NewPathMatch(r.Paths...)
+	}
+	ips := make([]string, 0, len(r.IPs))
+	r.ipNets = make([]*net.IPNet, 0, 1)
+	for _, ip := range r.IPs {
+		if !strings.Contains(ip, "/") {
+			ips = append(ips, ip)
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(ip)
+		if err != nil {
+			return fmt.Errorf("规则%s的IP范围有误:%s %v", r.Name, ip, err)
+		}
+		r.ipNets = append(r.ipNets, ipNet)
+	}
+	if len(ips) > 0 {
+		r.ipMatch = conf.NewPathMatch(ips...)
+	}
+	return nil
+}
+
+//Match 检查请求是否满足规则的所有条件
+func (r *Rule) Match(req IRequest) bool {
+	if r.pathMatch != nil {
+		if ok, _ := r.pathMatch.Match(req.GetPath()); !ok {
+			return false
+		}
+	}
+	if len(r.IPs) > 0 && !r.matchIP(req.GetClientIP()) {
+		return false
+	}
+	return matchValues(r.Headers, req.GetHeader) &&
+		matchValues(r.Cookies, req.GetCookie) &&
+		matchValues(r.Claims, req.GetClaim)
+}
+
+//Select 选择上游集群，返回集群名称与分流桶号(0-99)，集群名称为空时由当前集群处理
+func (r *Rule) Select(req IRequest) (string, int) {
+	bucket := r.getBucket(req)
+	if len(r.Splits) == 0 {
+		return r.UpCluster, bucket
+	}
+	total := 0
+	for _, s := range r.Splits {
+		total += s.Weight
+		if bucket < total {
+			return s.UpCluster, bucket
+		}
+	}
+	return "", bucket
+}
+
+//getBucket 获取分流桶号，相同粘性值的请求总是分配到相同的桶
+func (r *Rule) getBucket(req IRequest) int {
+	key := r.getStickyValue(req)
+	if key == "" {
+		return rand.Intn(100)
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % 100)
+}
+
+func (r *Rule) getStickyValue(req IRequest) string {
+	switch {
+	case r.Sticky == StickyUser:
+		return req.GetUser()
+	case r.Sticky == StickyIP:
+		return req.GetClientIP()
+	case strings.HasPrefix(r.Sticky, StickyCookiePrefix):
+		return req.GetCookie(strings.TrimPrefix(r.Sticky, StickyCookiePrefix))
+	case strings.HasPrefix(r.Sticky, StickyHeaderPrefix):
+		return req.GetHeader(strings.TrimPrefix(r.Sticky, StickyHeaderPrefix))
+	}
+	return ""
+}
+
+func (r *Rule) matchIP(ip string) bool {
+	if r.ipMatch != nil {
+		if ok, _ := r.ipMatch.Match(ip, "."); ok {
+			return true
+		}
+	}
+	cip := net.ParseIP(ip)
+	if cip == nil {
+		return false
+	}
+	for _, n := range r.ipNets {
+		if n.Contains(cip) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchValues(want map[string]string, get func(string) string) bool {
+	for k, v := range want {
+		value := get(k)
+		if value == "" || (v != "*" && v != value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package proxy
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+type testRequest struct {
+	path    string
+	ip      string
+	user    string
+	headers map[string]string
+	cookies map[string]string
+	claims  map[string]string
+}
+
+func (r *testRequest) GetPath() string              { return r.path }
+func (r *testRequest) GetHeader(name string) string { return r.headers[name] }
+func (r *testRequest) GetCookie(name string) string { return r.cookies[name] }
+func (r *testRequest) GetClientIP() string          { return r.ip }
+func (r *testRequest) GetUser() string              { return r.user }
+func (r *testRequest) GetClaim(name string) string  { return r.claims[name] }
+
+func TestRule_Match(t *testing.T) {
+	req := &testRequest{
+		path:    "/order/create",
+		ip:      "192.168.1.10",
+		headers: map[string]string{"X-Beta": "1"},
+		cookies: map[string]string{"uid": "1001"},
+		claims:  map[string]string{"role": "tester"},
+	}
+	tests := []struct {
+		name string
+		rule *Rule
+		want bool
+	}{
+		{name: "1. 未设置条件", rule: NewRule(WithUpCluster("gray")), want: true},
+		{name: "2. 路径匹配", rule: NewRule(WithUpCluster("gray"), WithPaths("/order/*")), want: true},
+		{name: "3. 路径不匹配", rule: NewRule(WithUpCluster("gray"), WithPaths("/user/*")), want: false},
+		{name: "4. 请求头匹配", rule: NewRule(WithUpCluster("gray"), WithHeader("X-Beta", "1")), want: true},
+		{name: "5. 请求头值不匹配", rule: NewRule(WithUpCluster("gray"), WithHeader("X-Beta", "2")), want: false},
+		{name: "6. cookie存在", rule: NewRule(WithUpCluster("gray"), WithCookie("uid", "*")), want: true},
+		{name: "7. cookie不存在", rule: NewRule(WithUpCluster("gray"), WithCookie("sid", "*")), want: false},
+		{name: "8. IP模糊匹配", rule: NewRule(WithUpCluster("gray"), WithIPs("192.168.*.*")), want: true},
+		{name: "9. IP段匹配", rule: NewRule(WithUpCluster("gray"), WithIPs("192.168.0.0/16")), want: true},
+		{name: "10. IP段不匹配", rule: NewRule(WithUpCluster("gray"), WithIPs("10.0.0.0/8")), want: false},
+		{name: "11. jwt字段匹配", rule: NewRule(WithUpCluster("gray"), WithClaim("role", "tester")), want: true},
+		{name: "12. 多个条件部分不匹配", rule: NewRule(WithUpCluster("gray"), WithPaths("/order/*"), WithClaim("role", "admin")), want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, nil, tt.rule.init(), tt.name)
+		assert.Equal(t, tt.want, tt.rule.Match(req), tt.name)
+	}
+}
+
+func TestRule_Select(t *testing.T) {
+	tests := []struct {
+		name string
+		rule *Rule
+		req  *testRequest
+		want string
+	}{
+		{name: "1. 指定上游集群", rule: NewRule(WithUpCluster("gray")), req: &testRequest{}, want: "gray"},
+		{name: "2. 全部分流到上游集群", rule: NewRule(WithSplit("gray", 100)), req: &testRequest{}, want: "gray"},
+		{name: "3. 不分流到上游集群", rule: NewRule(WithSplit("gray", 0)), req: &testRequest{}, want: ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, nil, tt.rule.init(), tt.name)
+		got, _ := tt.rule.Select(tt.req)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+
+	//相同用户总是分配到相同的上游集群
+	rule := NewRule(WithSplit("a", 30), WithSplit("b", 30), WithSticky(StickyUser))
+	assert.Equal(t, nil, rule.init(), "粘性分流规则")
+	for _, user := range []string{"u1", "u2", "u3", "u4"} {
+		first, bucket := rule.Select(&testRequest{user: user})
+		for i := 0; i < 10; i++ {
+			got, b := rule.Select(&testRequest{user: user})
+			assert.Equal(t, first, got, "粘性分流集群")
+			assert.Equal(t, bucket, b, "粘性分流桶号")
+		}
+	}
+
+	//比例之和超过100
+	rule = NewRule(WithSplit("a", 60), WithSplit("b", 50))
+	assert.NotEqual(t, nil, rule.init(), "比例之和超过100")
+}
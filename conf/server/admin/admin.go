@@ -0,0 +1,74 @@
+/*
+admin管理服务器配置，使用独立端口输出当前节点的服务器配置、已注册服务、定时任务、消息队列、组件等运行时信息及pprof性能分析数据。
+管理服务器需配置auth/basic或auth/jwt认证，未配置认证时不允许启动。
+*/
+
+package admin
+
+import (
+	"fmt"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/global"
+)
+
+const (
+	//StartStatus 开启服务
+	StartStatus = "start"
+	//StartStop 停止服务
+	StartStop = "stop"
+)
+
+//DefAddress 默认监听地址
+const DefAddress = ":19999"
+
+//MainConfName 主配置中的关键配置名
+var MainConfName = []string{"address", "status", "pprof"}
+
+//SubConfName 子配置中的关键配置名
+var SubConfName = []string{"auth"}
+
+//Server 管理服务器配置信息
+type Server struct {
+	Address string `json:"address,omitempty" valid:"dialstring" toml:"address,omitempty"`
+	Status  string `json:"status,omitempty" valid:"in(start|stop)" toml:"status,omitempty"`
+
+	//Pprof 是否开启/debug/pprof性能分析
+	Pprof bool `json:"pprof,omitempty" toml:"pprof,omitempty"`
+}
+
+//New 构建admin server配置
+func New(address string, opts ...Option) *Server {
+	s := &Server{
+		Address: address,
+		Status:  StartStatus,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+//GetConf 获取主配置信息
+func GetConf(cnf conf.IServerConf) (s *Server, err error) {
+	s = &Server{}
+	if cnf.GetServerType() != global.Admin {
+		return nil, fmt.Errorf("admin主配置类型错误:%s != admin", cnf.GetServerType())
+	}
+
+	_, err = cnf.GetMainObject(s)
+	if err == conf.ErrNoSetting {
+		return nil, fmt.Errorf("/%s :%w", cnf.GetServerPath(), err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.Address == "" {
+		s.Address = DefAddress
+	}
+	if b, err := govalidator.ValidateStruct(s); !b {
+		return nil, fmt.Errorf("admin主配置数据有误:%v", err)
+	}
+	return s, nil
+}
@@ -0,0 +1,24 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		opts    []Option
+		want    *Server
+	}{
+		{name: "1. 默认配置", address: DefAddress, want: &Server{Address: DefAddress, Status: StartStatus}},
+		{name: "2. 开启pprof", address: ":9999", opts: []Option{WithPprof()}, want: &Server{Address: ":9999", Status: StartStatus, Pprof: true}},
+		{name: "3. 禁用服务器", address: ":9999", opts: []Option{WithDisable()}, want: &Server{Address: ":9999", Status: StartStop}},
+		{name: "4. 禁用后启用服务器", address: ":9999", opts: []Option{WithDisable(), WithEnable()}, want: &Server{Address: ":9999", Status: StartStatus}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, New(tt.address, tt.opts...), tt.name)
+	}
+}
@@ -0,0 +1,25 @@
+package admin
+
+//Option 配置选项
+type Option func(*Server)
+
+//WithPprof 开启pprof性能分析
+func WithPprof() Option {
+	return func(a *Server) {
+		a.Pprof = true
+	}
+}
+
+//WithDisable 禁用管理服务器
+func WithDisable() Option {
+	return func(a *Server) {
+		a.Status = StartStop
+	}
+}
+
+//WithEnable 启用管理服务器
+func WithEnable() Option {
+	return func(a *Server) {
+		a.Status = StartStatus
+	}
+}
@@ -38,7 +38,7 @@ const (
 var MainConfName = []string{"address", "status", "rTimeout", "wTimeout", "rhTimeout", "dn"}
 
 //SubConfName 子配置中的关键配置名
-var SubConfName = []string{"router", "metric"}
+var SubConfName = []string{"router", "metric", "prometheus"}
 var validTypes = map[string]bool{"api": true, "web": true, "ws": true}
 
 //Server api server配置信息
@@ -10,15 +10,35 @@ import (
 //TypeNodeName APM配置节点名
 const TypeNodeName = "apm"
 
+const (
+	//SkyWalking 通过skywalking上报
+	SkyWalking = "skywalking"
+
+	//OTLPGRPC 通过OTLP/gRPC上报，地址格式为host:port
+	OTLPGRPC = "otlp-grpc"
+
+	//OTLPHTTP 通过OTLP/HTTP上报，地址格式为http://host:port
+	OTLPHTTP = "otlp-http"
+
+	//Zipkin 通过zipkin json接口上报，地址格式为http://host:port
+	Zipkin = "zipkin"
+
+	//Stdout 输出到控制台
+	Stdout = "stdout"
+)
+
 type IAPM interface {
 	GetConf() (*APM, bool)
 }
 
 //APM APM
 type APM struct {
-	Address string `json:"address,omitempty" valid:"required" toml:"address,omitempty"`
-	Version int32  `json:"-"`
-	Disable bool   `json:"disable,omitempty" toml:"disable,omitempty"`
+	Address  string            `json:"address,omitempty" toml:"address,omitempty"`
+	Provider string            `json:"provider,omitempty" valid:"in(skywalking|otlp-grpc|otlp-http|zipkin|stdout)" toml:"provider,omitempty"`
+	Ratio    float64           `json:"ratio,omitempty" valid:"range(0|1)" toml:"ratio,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty" toml:"headers,omitempty"`
+	Version  int32             `json:"-"`
+	Disable  bool              `json:"disable,omitempty" toml:"disable,omitempty"`
 }
 
 //New 构建api server配置信息
@@ -32,6 +52,19 @@ func New(address string, opts ...Option) *APM {
 	return m
 }
 
+//IsOTel 是否使用OpenTelemetry跟踪
+func (a *APM) IsOTel() bool {
+	return a.Provider != "" && a.Provider != SkyWalking
+}
+
+//GetRatio 获取采样比例，未设置时全部采样
+func (a *APM) GetRatio() float64 {
+	if a.Ratio <= 0 {
+		return 1
+	}
+	return a.Ratio
+}
+
 //GetConf 设置APM
 func GetConf(cnf conf.IServerConf) (apm *APM, err error) {
 	apm = &APM{}
@@ -50,5 +83,8 @@ func GetConf(cnf conf.IServerConf) (apm *APM, err error) {
 	if b, err := govalidator.ValidateStruct(apm); !b {
 		return nil, fmt.Errorf("apm配置数据有误:%v", err)
 	}
+	if apm.Address == "" && apm.Provider != Stdout {
+		return nil, fmt.Errorf("apm配置数据有误:未指定上报地址")
+	}
 	return
 }
@@ -16,3 +16,27 @@ func WithEnable() Option {
 		a.Disable = false
 	}
 }
+
+//WithProvider 设置上报方式(skywalking,otlp-grpc,otlp-http,zipkin,stdout)
+func WithProvider(provider string) Option {
+	return func(a *APM) {
+		a.Provider = provider
+	}
+}
+
+//WithRatio 设置未收到上游跟踪信息时的采样比例(0-1]
+func WithRatio(ratio float64) Option {
+	return func(a *APM) {
+		a.Ratio = ratio
+	}
+}
+
+//WithHeader 设置上报请求头，如认证信息
+func WithHeader(name string, value string) Option {
+	return func(a *APM) {
+		if a.Headers == nil {
+			a.Headers = make(map[string]string)
+		}
+		a.Headers[name] = value
+	}
+}
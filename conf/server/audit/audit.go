@@ -0,0 +1,136 @@
+/*
+审计配置，对指定路径的请求在处理完成后记录客户端IP、用户、请求编号、服务、指定的请求参数(已脱敏)、
+响应状态与处理时长，审计事件异步发送到消息队列或写入按日期滚动的本地文件，缓冲区满时丢弃，不阻塞请求处理。
+记录的请求参数按审计配置脱敏，DefRedact中的参数始终脱敏，不依赖日志配置。
+*/
+
+package audit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/lib4go/types"
+)
+
+//TypeNodeName 审计配置节点名
+const TypeNodeName = "audit"
+
+//DefQueueKey 默认的审计消息队列名称
+const DefQueueKey = "hydra:audit"
+
+//DefFile 默认的审计文件路径
+const DefFile = "../audit/%date.audit"
+
+//DefBuffer 默认的审计缓冲区大小
+const DefBuffer = 1024
+
+//DefMask 敏感参数的替换值
+const DefMask = "******"
+
+//DefRedact 始终脱敏的请求参数
+var DefRedact = []string{"password", "passwd", "pwd", "secret", "token", "access_token", "card_no", "cvv", "id_card"}
+
+//Audit 审计配置
+type Audit struct {
+
+	//Paths 需要审计的路径
+	Paths []string `json:"paths,omitempty" valid:"required" toml:"paths,omitempty"`
+
+	//Params 需要记录的请求参数，*表示所有参数
+	Params []string `json:"params,omitempty" toml:"params,omitempty"`
+
+	//Redact 需要脱敏的请求参数，与DefRedact合并使用
+	Redact []string `json:"redact,omitempty" toml:"redact,omitempty"`
+
+	//Queue 消息队列配置名称(var/queue/[name])，未配置时写入本地文件
+	Queue string `json:"queue,omitempty" valid:"ascii" toml:"queue,omitempty"`
+
+	//Key 审计消息的队列名称
+	Key string `json:"key,omitempty" toml:"key,omitempty"`
+
+	//File 本地文件路径，%date替换为当前日期
+	File string `json:"file,omitempty" toml:"file,omitempty"`
+
+	//Buffer 等待输出的最大审计事件数
+	Buffer int `json:"buffer,omitempty" toml:"buffer,omitempty"`
+
+	Disable         bool `json:"disable,omitempty" toml:"disable,omitempty"`
+	*conf.PathMatch `json:"-"`
+}
+
+//New 构建审计配置
+func New(paths []string, opts ...Option) *Audit {
+	a := &Audit{
+		Paths:  paths,
+		Key:    DefQueueKey,
+		File:   DefFile,
+		Buffer: DefBuffer,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.PathMatch = conf.NewPathMatch(a.Paths...)
+	return a
+}
+
+//IsEnable 检查当前请求是否需要审计
+func (a *Audit) IsEnable(path string) bool {
+	if a.Disable || a.PathMatch == nil {
+		return false
+	}
+	ok, _ := a.PathMatch.Match(path)
+	return ok
+}
+
+//GetParams 从请求参数中获取需要记录的参数，敏感参数替换为DefMask
+func (a *Audit) GetParams(input map[string]interface{}) map[string]interface{} {
+	if len(a.Params) == 0 || len(input) == 0 {
+		return nil
+	}
+	all := types.StringContains(a.Params, "*")
+	out := make(map[string]interface{}, len(a.Params))
+	for k, v := range input {
+		if !all && !types.StringContains(a.Params, k) {
+			continue
+		}
+		if a.isRedact(k) {
+			v = DefMask
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func (a *Audit) isRedact(k string) bool {
+	for _, list := range [][]string{DefRedact, a.Redact} {
+		for _, v := range list {
+			if strings.EqualFold(v, k) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//GetConf 获取审计配置
+func GetConf(cnf conf.IServerConf) (*Audit, error) {
+	a := New(nil)
+	_, err := cnf.GetSubObject(TypeNodeName, a)
+	if err == conf.ErrNoSetting || len(a.Paths) == 0 {
+		return &Audit{Disable: true}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("audit配置格式有误:%v", err)
+	}
+	if b, err := govalidator.ValidateStruct(a); !b {
+		return nil, fmt.Errorf("audit配置数据有误:%v", err)
+	}
+	a.Key = types.GetString(a.Key, DefQueueKey)
+	a.File = types.GetString(a.File, DefFile)
+	a.Buffer = types.DecodeInt(a.Buffer, 0, DefBuffer, a.Buffer)
+	a.PathMatch = conf.NewPathMatch(a.Paths...)
+	return a, nil
+}
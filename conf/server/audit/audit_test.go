@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestAudit_IsEnable(t *testing.T) {
+	tests := []struct {
+		name  string
+		audit *Audit
+		path  string
+		want  bool
+	}{
+		{name: "1. 未配置路径", audit: New(nil), path: "/order/create", want: false},
+		{name: "2. 完全匹配路径", audit: New([]string{"/order/create"}), path: "/order/create", want: true},
+		{name: "3. 模糊匹配路径", audit: New([]string{"/order/**"}), path: "/order/pay/notify", want: true},
+		{name: "4. 不匹配的路径", audit: New([]string{"/order/create"}), path: "/order/query", want: false},
+		{name: "5. 禁用配置", audit: New([]string{"/order/create"}, WithDisable()), path: "/order/create", want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.audit.IsEnable(tt.path), tt.name)
+	}
+}
+
+func TestAudit_GetParams(t *testing.T) {
+	input := map[string]interface{}{"order_no": "123", "amount": 100, "password": "xxx"}
+	tests := []struct {
+		name  string
+		audit *Audit
+		want  map[string]interface{}
+	}{
+		{name: "1. 未配置参数", audit: New(nil), want: nil},
+		{name: "2. 记录指定参数", audit: New(nil, WithParams("order_no", "amount", "not_exists")), want: map[string]interface{}{"order_no": "123", "amount": 100}},
+		{name: "3. 记录所有参数,默认脱敏密码", audit: New(nil, WithParams("*")), want: map[string]interface{}{"order_no": "123", "amount": 100, "password": DefMask}},
+		{name: "4. 记录指定的敏感参数", audit: New(nil, WithParams("order_no", "password")), want: map[string]interface{}{"order_no": "123", "password": DefMask}},
+		{name: "5. 配置脱敏参数", audit: New(nil, WithParams("*"), WithRedact("Amount")), want: map[string]interface{}{"order_no": "123", "amount": DefMask, "password": DefMask}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.audit.GetParams(input), tt.name)
+	}
+}
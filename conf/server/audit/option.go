@@ -0,0 +1,54 @@
+package audit
+
+//Option 配置选项
+type Option func(*Audit)
+
+//WithParams 设置需要记录的请求参数，*表示所有参数
+func WithParams(params ...string) Option {
+	return func(a *Audit) {
+		a.Params = params
+	}
+}
+
+//WithRedact 设置需要脱敏的请求参数，与DefRedact合并使用
+func WithRedact(params ...string) Option {
+	return func(a *Audit) {
+		a.Redact = params
+	}
+}
+
+//WithQueue 设置审计事件发送的消息队列配置名称与队列名称
+func WithQueue(queue string, key string) Option {
+	return func(a *Audit) {
+		a.Queue = queue
+		a.Key = key
+	}
+}
+
+//WithFile 设置审计事件写入的本地文件路径，%date替换为当前日期
+func WithFile(path string) Option {
+	return func(a *Audit) {
+		a.File = path
+	}
+}
+
+//WithBuffer 设置等待输出的最大审计事件数
+func WithBuffer(n int) Option {
+	return func(a *Audit) {
+		a.Buffer = n
+	}
+}
+
+//WithDisable 禁用审计
+func WithDisable() Option {
+	return func(a *Audit) {
+		a.Disable = true
+	}
+}
+
+//WithEnable 启用审计
+func WithEnable() Option {
+	return func(a *Audit) {
+		a.Disable = false
+	}
+}
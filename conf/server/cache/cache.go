@@ -0,0 +1,84 @@
+/*
+响应缓存配置，按请求路径指定缓存规则，缓存键由请求路径、指定的参数、请求头、用户信息组成。
+缓存结果存储于var/cache中配置的缓存服务，支持ETag/If-None-Match条件请求与Cache-Control处理。
+*/
+
+package cache
+
+import (
+	"fmt"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/lib4go/concurrent/cmap"
+)
+
+//TypeNodeName 响应缓存配置节点名
+const TypeNodeName = "cache"
+
+//Cache 响应缓存配置
+type Cache struct {
+
+	//Cache 存储响应结果的缓存配置名称(var/cache/[name])
+	Cache   string          `json:"cache,omitempty" valid:"ascii" toml:"cache,omitempty"`
+	Rules   []*Rule         `json:"rules,omitempty" valid:"required" toml:"rules,omitempty"`
+	Disable bool            `json:"disable,omitempty" toml:"disable,omitempty"`
+	p       *conf.PathMatch `json:"-"`
+	rules   cmap.ConcurrentMap
+}
+
+//New 构建响应缓存配置
+func New(opts ...Option) *Cache {
+	c := &Cache{
+		Cache: "cache",
+		Rules: []*Rule{},
+	}
+	for _, f := range opts {
+		f(c)
+	}
+	c.init()
+	return c
+}
+
+func (c *Cache) init() {
+	c.rules = cmap.New(4)
+	paths := make([]string, 0, len(c.Rules))
+	for _, v := range c.Rules {
+		c.rules.Set(v.Path, v)
+		paths = append(paths, v.Path)
+	}
+	c.p = conf.NewPathMatch(paths...)
+}
+
+//GetRule 获取与请求路径匹配的缓存规则
+func (c *Cache) GetRule(path string) (bool, *Rule) {
+	if c.Disable || c.p == nil {
+		return false, nil
+	}
+	ok, path := c.p.Match(path)
+	if !ok {
+		return false, nil
+	}
+	rule, ok := c.rules.Get(path)
+	if !ok {
+		return false, nil
+	}
+	return true, rule.(*Rule)
+}
+
+//GetConf 获取响应缓存配置
+func GetConf(cnf conf.IServerConf) (*Cache, error) {
+	cache := &Cache{Cache: "cache"}
+	_, err := cnf.GetSubObject(TypeNodeName, cache)
+	if err == conf.ErrNoSetting || len(cache.Rules) == 0 {
+		return &Cache{Disable: true}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cache配置格式有误:%v", err)
+	}
+	if b, err := govalidator.ValidateStruct(cache); !b {
+		return nil, fmt.Errorf("cache配置数据有误:%v %+v", err, cache)
+	}
+	cache.init()
+	return cache, nil
+}
@@ -0,0 +1,63 @@
+package cache
+
+//Option 配置选项
+type Option func(*Cache)
+
+//WithCache 设置存储响应结果的缓存配置名称
+func WithCache(name string) Option {
+	return func(a *Cache) {
+		a.Cache = name
+	}
+}
+
+//WithRuleList 添加缓存规则
+func WithRuleList(list ...*Rule) Option {
+	return func(a *Cache) {
+		a.Rules = append(a.Rules, list...)
+	}
+}
+
+//WithDisable 禁用配置
+func WithDisable() Option {
+	return func(a *Cache) {
+		a.Disable = true
+	}
+}
+
+//WithEnable 启用配置
+func WithEnable() Option {
+	return func(a *Cache) {
+		a.Disable = false
+	}
+}
+
+//RuleOption 缓存规则配置选项
+type RuleOption func(*Rule)
+
+//WithParams 设置参与缓存键计算的请求参数
+func WithParams(p ...string) RuleOption {
+	return func(a *Rule) {
+		a.Params = p
+	}
+}
+
+//WithHeaders 设置参与缓存键计算的请求头
+func WithHeaders(h ...string) RuleOption {
+	return func(a *Rule) {
+		a.Headers = h
+	}
+}
+
+//WithUser 按用户分别缓存
+func WithUser() RuleOption {
+	return func(a *Rule) {
+		a.User = true
+	}
+}
+
+//WithTags 设置缓存标签
+func WithTags(t ...string) RuleOption {
+	return func(a *Rule) {
+		a.Tags = t
+	}
+}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//Rule 按请求路径设定的缓存规则
+type Rule struct {
+
+	//Path 请求路径,支持模糊匹配
+	Path string `json:"path" valid:"ascii,required" toml:"path,omitempty"`
+
+	//Expire 缓存时长(秒)
+	Expire int `json:"expire" valid:"required" toml:"expire,omitempty"`
+
+	//Params 参与缓存键计算的请求参数
+	Params []string `json:"params,omitempty" toml:"params,omitempty"`
+
+	//Headers 参与缓存键计算的请求头
+	Headers []string `json:"headers,omitempty" toml:"headers,omitempty"`
+
+	//User 是否按用户分别缓存
+	User bool `json:"user,omitempty" toml:"user,omitempty"`
+
+	//Tags 缓存标签,用于按标签清除缓存
+	Tags []string `json:"tags,omitempty" toml:"tags,omitempty"`
+}
+
+//NewRule 构建缓存规则
+func NewRule(path string, expire int, opts ...RuleOption) *Rule {
+	r := &Rule{
+		Path:   path,
+		Expire: expire,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+//GetKeyRaw 根据请求路径、参数、请求头、用户构建缓存键原串
+func (r *Rule) GetKeyRaw(path string, param func(string) string, header func(string) string, user string) string {
+	var sb strings.Builder
+	sb.WriteString(path)
+	for _, k := range sortStrings(r.Params) {
+		sb.WriteString(fmt.Sprintf("|p:%s=%s", k, param(k)))
+	}
+	for _, k := range sortStrings(r.Headers) {
+		sb.WriteString(fmt.Sprintf("|h:%s=%s", strings.ToLower(k), header(k)))
+	}
+	if r.User {
+		sb.WriteString(fmt.Sprintf("|u:%s", user))
+	}
+	return sb.String()
+}
+
+//GetCacheControl 获取响应的Cache-Control头
+func (r *Rule) GetCacheControl() string {
+	if r.User {
+		return fmt.Sprintf("private, max-age=%d", r.Expire)
+	}
+	return fmt.Sprintf("public, max-age=%d", r.Expire)
+}
+
+func sortStrings(v []string) []string {
+	n := make([]string, len(v))
+	copy(n, v)
+	sort.Strings(n)
+	return n
+}
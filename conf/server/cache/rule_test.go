@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestRule_GetKeyRaw(t *testing.T) {
+	values := map[string]string{"id": "1", "type": "a", "Accept-Language": "zh-CN"}
+	get := func(k string) string { return values[k] }
+	tests := []struct {
+		name string
+		rule *Rule
+		want string
+	}{
+		{name: "1. 仅路径", rule: NewRule("/order/query", 60), want: "/order/query"},
+		{name: "2. 参数按名称排序", rule: NewRule("/order/query", 60, WithParams("type", "id")), want: "/order/query|p:id=1|p:type=a"},
+		{name: "3. 参数与请求头", rule: NewRule("/order/query", 60, WithParams("id"), WithHeaders("Accept-Language")), want: "/order/query|p:id=1|h:accept-language=zh-CN"},
+		{name: "4. 按用户缓存", rule: NewRule("/order/query", 60, WithUser()), want: "/order/query|u:colin"},
+	}
+	for _, tt := range tests {
+		got := tt.rule.GetKeyRaw("/order/query", get, get, "colin")
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
+
+func TestCache_GetRule(t *testing.T) {
+	c := New(WithRuleList(NewRule("/order/*", 60), NewRule("/product/query", 30)))
+	ok, rule := c.GetRule("/order/query")
+	assert.Equal(t, true, ok, "1. 模糊匹配")
+	assert.Equal(t, 60, rule.Expire, "1. 模糊匹配")
+	ok, rule = c.GetRule("/product/query")
+	assert.Equal(t, true, ok, "2. 完全匹配")
+	assert.Equal(t, 30, rule.Expire, "2. 完全匹配")
+	ok, _ = c.GetRule("/user/query")
+	assert.Equal(t, false, ok, "3. 未匹配")
+}
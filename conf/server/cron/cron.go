@@ -16,16 +16,19 @@ const (
 )
 
 //MainConfName 主配置中的关键配置名
-var MainConfName = []string{"status", "sharding"}
+var MainConfName = []string{"status", "sharding", "lease"}
 
 //SubConfName 子配置中的关键配置名
-var SubConfName = []string{"task"}
+var SubConfName = []string{"task", "prometheus", "history", "delay"}
 
 //Server 服务嚣配置信息
 type Server struct {
 	Status   string `json:"status,omitempty" valid:"in(start|stop)" toml:"status,omitempty"`
 	Sharding int    `json:"sharding,omitempty" toml:"sharding,omitempty"`
 	Trace    bool   `json:"trace,omitempty" toml:"trace,omitempty"`
+
+	//Lease 任务租约模式，每个任务租约给集群中的一个存活节点执行，节点变化时重新分配
+	Lease bool `json:"lease,omitempty" toml:"lease,omitempty"`
 }
 
 //New 构建cron server配置，默认为对等模式
@@ -14,6 +14,7 @@ func WithTrace() Option {
 func WithMasterSlave() Option {
 	return func(a *Server) {
 		a.Sharding = 1
+		a.Lease = false
 	}
 }
 
@@ -21,6 +22,7 @@ func WithMasterSlave() Option {
 func WithSharding(i int) Option {
 	return func(a *Server) {
 		a.Sharding = i
+		a.Lease = false
 	}
 }
 
@@ -28,6 +30,15 @@ func WithSharding(i int) Option {
 func WithP2P() Option {
 	return func(a *Server) {
 		a.Sharding = 0
+		a.Lease = false
+	}
+}
+
+//WithLease 设置为任务租约模式，每个任务租约给集群中的一个存活节点执行
+func WithLease() Option {
+	return func(a *Server) {
+		a.Sharding = 0
+		a.Lease = true
 	}
 }
 
@@ -0,0 +1,70 @@
+/*
+cron服务器执行延迟任务的配置，cron服务器按/var/delay下的配置获取到期的延迟任务并执行，
+未配置时不执行延迟任务。
+*/
+
+package delay
+
+import (
+	"fmt"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+	vardelay "github.com/micro-plat/hydra/conf/vars/delay"
+)
+
+//TypeNodeName 延迟任务配置节点名
+const TypeNodeName = "delay"
+
+//DefBatch 每次获取的默认任务数
+const DefBatch = 100
+
+//Delay 延迟任务执行配置
+type Delay struct {
+	//Names /var/delay下的配置名称
+	Names []string `json:"names,omitempty" toml:"names,omitempty"`
+
+	//Batch 每次获取的最大任务数
+	Batch int `json:"batch,omitempty" valid:"range(0|10000)" toml:"batch,omitempty"`
+
+	Disable bool `json:"disable,omitempty" toml:"disable,omitempty"`
+}
+
+//New 构建延迟任务执行配置，未指定名称时使用默认配置名称
+func New(names ...string) *Delay {
+	d := &Delay{Names: names}
+	d.setDefault()
+	return d
+}
+
+//WithBatch 设置每次获取的最大任务数
+func (d *Delay) WithBatch(batch int) *Delay {
+	d.Batch = batch
+	return d
+}
+
+func (d *Delay) setDefault() {
+	if len(d.Names) == 0 {
+		d.Names = []string{vardelay.DefName}
+	}
+	if d.Batch <= 0 {
+		d.Batch = DefBatch
+	}
+}
+
+//GetConf 获取延迟任务执行配置
+func GetConf(cnf conf.IServerConf) (*Delay, error) {
+	d := &Delay{}
+	_, err := cnf.GetSubObject(TypeNodeName, d)
+	if err == conf.ErrNoSetting {
+		return &Delay{Disable: true}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("delay配置格式有误:%v", err)
+	}
+	if b, err := govalidator.ValidateStruct(d); !b {
+		return nil, fmt.Errorf("delay配置数据有误:%v", err)
+	}
+	d.setDefault()
+	return d, nil
+}
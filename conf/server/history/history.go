@@ -0,0 +1,106 @@
+/*
+cron任务执行历史配置，记录每次任务执行的开始、结束时间、执行节点、状态、错误信息及处理时长，
+用于服务器启动或主节点切换后按任务的misfire策略补偿错过的执行。
+存储方式：
+registry:保存到注册中心/平台/系统/cron/集群/history/任务名称节点
+file:保存到本地目录，每个任务一个文件，执行记录仅当前节点可见，主节点切换后新的主节点无法补偿错过的执行
+db:保存到数据库表，需预先创建表：
+	create table hydra_cron_history(
+		task varchar(64),service varchar(256),scheduled bigint,start_time bigint,end_time bigint,
+		node varchar(64),status int,error varchar(512),duration bigint,misfire int)
+*/
+
+package history
+
+import (
+	"fmt"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+)
+
+//TypeNodeName 执行历史配置节点名
+const TypeNodeName = "history"
+
+const (
+	//StoreRegistry 保存到注册中心
+	StoreRegistry = "registry"
+	//StoreFile 保存到本地文件
+	StoreFile = "file"
+	//StoreDB 保存到数据库
+	StoreDB = "db"
+)
+
+//DefKeep 每个任务默认保留的记录数
+const DefKeep = 20
+
+//DefPath 默认的文件保存目录
+const DefPath = "../history"
+
+//DefTable 默认的数据库表名
+const DefTable = "hydra_cron_history"
+
+//History 执行历史配置
+type History struct {
+	//Store 存储方式registry,file,db
+	Store string `json:"store,omitempty" valid:"in(registry|file|db)" toml:"store,omitempty"`
+
+	//Path 文件保存目录
+	Path string `json:"path,omitempty" toml:"path,omitempty"`
+
+	//DB 数据库配置名称
+	DB string `json:"db,omitempty" toml:"db,omitempty"`
+
+	//Table 数据库表名
+	Table string `json:"table,omitempty" valid:"ascii" toml:"table,omitempty"`
+
+	//Keep 每个任务保留的记录数，仅registry,file有效
+	Keep int `json:"keep,omitempty" toml:"keep,omitempty"`
+
+	Disable bool `json:"disable,omitempty" toml:"disable,omitempty"`
+}
+
+//New 构建执行历史配置
+func New(store string, opts ...Option) *History {
+	h := &History{Store: store}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.setDefault()
+	return h
+}
+
+func (h *History) setDefault() {
+	if h.Store == "" {
+		h.Store = StoreRegistry
+	}
+	if h.Keep <= 0 {
+		h.Keep = DefKeep
+	}
+	if h.Path == "" {
+		h.Path = DefPath
+	}
+	if h.Table == "" {
+		h.Table = DefTable
+	}
+}
+
+//GetConf 获取执行历史配置
+func GetConf(cnf conf.IServerConf) (*History, error) {
+	h := &History{}
+	_, err := cnf.GetSubObject(TypeNodeName, h)
+	if err == conf.ErrNoSetting {
+		return &History{Disable: true}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("history配置格式有误:%v", err)
+	}
+	if b, err := govalidator.ValidateStruct(h); !b {
+		return nil, fmt.Errorf("history配置数据有误:%v", err)
+	}
+	h.setDefault()
+	if h.Store == StoreDB && h.DB == "" {
+		return nil, fmt.Errorf("history配置数据有误:未指定数据库配置名称")
+	}
+	return h, nil
+}
@@ -0,0 +1,25 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name  string
+		store string
+		opts  []Option
+		want  *History
+	}{
+		{name: "1. 默认配置", store: "", want: &History{Store: StoreRegistry, Path: DefPath, Table: DefTable, Keep: DefKeep}},
+		{name: "2. 文件存储", store: StoreFile, opts: []Option{WithPath("./history"), WithKeep(5)}, want: &History{Store: StoreFile, Path: "./history", Table: DefTable, Keep: 5}},
+		{name: "3. 数据库存储", store: StoreDB, opts: []Option{WithDB("db", "cron_history")}, want: &History{Store: StoreDB, Path: DefPath, DB: "db", Table: "cron_history", Keep: DefKeep}},
+		{name: "4. 禁用执行历史", store: StoreRegistry, opts: []Option{WithDisable()}, want: &History{Store: StoreRegistry, Path: DefPath, Table: DefTable, Keep: DefKeep, Disable: true}},
+		{name: "5. 禁用后启用执行历史", store: StoreRegistry, opts: []Option{WithDisable(), WithEnable()}, want: &History{Store: StoreRegistry, Path: DefPath, Table: DefTable, Keep: DefKeep}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, New(tt.store, tt.opts...), tt.name)
+	}
+}
@@ -0,0 +1,42 @@
+package history
+
+//Option 配置选项
+type Option func(*History)
+
+//WithPath 设置文件保存目录
+func WithPath(path string) Option {
+	return func(h *History) {
+		h.Path = path
+	}
+}
+
+//WithDB 设置数据库配置名称及表名
+func WithDB(db string, table ...string) Option {
+	return func(h *History) {
+		h.DB = db
+		if len(table) > 0 {
+			h.Table = table[0]
+		}
+	}
+}
+
+//WithKeep 设置每个任务保留的记录数
+func WithKeep(keep int) Option {
+	return func(h *History) {
+		h.Keep = keep
+	}
+}
+
+//WithDisable 禁用配置
+func WithDisable() Option {
+	return func(h *History) {
+		h.Disable = true
+	}
+}
+
+//WithEnable 启用配置
+func WithEnable() Option {
+	return func(h *History) {
+		h.Disable = false
+	}
+}
@@ -0,0 +1,124 @@
+/*
+幂等配置，对指定路径的非安全请求(POST,PUT,PATCH,DELETE)按请求头或参数中的幂等键进行去重处理。
+首次请求的最终响应结果将保存到缓存中，后续相同幂等键的请求直接返回已保存的结果；
+首次请求尚未处理完成时，重复的请求将返回409。
+幂等键按调用方隔离，已认证的请求使用认证用户，未认证的请求使用客户端IP，
+不同调用方使用相同的幂等键不会获取到彼此的处理结果。
+处理结果默认保存到var/cache/[cache]，设置redis后保存到var/redis/[redis]。
+*/
+
+package idempotency
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/lib4go/security/md5"
+	"github.com/micro-plat/lib4go/types"
+)
+
+//TypeNodeName 幂等配置节点名
+const TypeNodeName = "idempotency"
+
+//DefHeader 默认的幂等键请求头名称
+const DefHeader = "Idempotency-Key"
+
+//DefMethods 默认需要进行幂等处理的请求方法
+var DefMethods = []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+//Idempotency 幂等配置
+type Idempotency struct {
+
+	//Header 幂等键对应的请求头名称
+	Header string `json:"header,omitempty" valid:"ascii" toml:"header,omitempty"`
+
+	//Param 幂等键对应的请求参数名称,请求头未传入时使用
+	Param string `json:"param,omitempty" valid:"ascii" toml:"param,omitempty"`
+
+	//Cache 存储幂等结果的缓存配置名称(var/cache/[name])
+	Cache string `json:"cache,omitempty" valid:"ascii" toml:"cache,omitempty"`
+
+	//Redis 存储幂等结果的redis配置名称(var/redis/[name]),设置后优先于Cache
+	Redis string `json:"redis,omitempty" valid:"ascii" toml:"redis,omitempty"`
+
+	//Paths 需要进行幂等处理的路径
+	Paths []string `json:"paths,omitempty" valid:"required" toml:"paths,omitempty"`
+
+	//Methods 需要进行幂等处理的请求方法
+	Methods []string `json:"methods,omitempty" toml:"methods,omitempty"`
+
+	//Expire 响应结果的保存时长(秒)
+	Expire int `json:"expire,omitempty" toml:"expire,omitempty"`
+
+	//LockTimeout 请求处理中的锁定时长(秒)
+	LockTimeout int `json:"lockTimeout,omitempty" toml:"lockTimeout,omitempty"`
+
+	Disable         bool `json:"disable,omitempty" toml:"disable,omitempty"`
+	*conf.PathMatch `json:"-"`
+}
+
+//New 构建幂等配置
+func New(paths []string, opts ...Option) *Idempotency {
+	i := &Idempotency{
+		Header:      DefHeader,
+		Cache:       "cache",
+		Paths:       paths,
+		Expire:      86400,
+		LockTimeout: 60,
+	}
+	for _, opt := range opts {
+		opt(i)
+	}
+	i.PathMatch = conf.NewPathMatch(i.Paths...)
+	return i
+}
+
+//IsEnable 检查当前请求是否需要进行幂等处理
+func (i *Idempotency) IsEnable(path string, method string) bool {
+	if i.Disable || i.PathMatch == nil {
+		return false
+	}
+	if !types.StringContains(i.getMethods(), strings.ToUpper(method)) {
+		return false
+	}
+	ok, _ := i.PathMatch.Match(path)
+	return ok
+}
+
+//GetKey 获取存储幂等结果的缓存键，caller为调用方标识
+func (i *Idempotency) GetKey(serverType string, path string, caller string, key string) string {
+	return fmt.Sprintf("hydra:idempotency:%s:%s:%s:%s", serverType, path, md5.Encrypt(caller), key)
+}
+
+func (i *Idempotency) getMethods() []string {
+	if len(i.Methods) == 0 {
+		return DefMethods
+	}
+	return i.Methods
+}
+
+//GetConf 获取幂等配置
+func GetConf(cnf conf.IServerConf) (*Idempotency, error) {
+	idem := New(nil)
+	_, err := cnf.GetSubObject(TypeNodeName, idem)
+	if err == conf.ErrNoSetting || len(idem.Paths) == 0 {
+		return &Idempotency{Disable: true}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("idempotency配置格式有误:%v", err)
+	}
+	if b, err := govalidator.ValidateStruct(idem); !b {
+		return nil, fmt.Errorf("idempotency配置数据有误:%v", err)
+	}
+	for k, v := range idem.Methods {
+		idem.Methods[k] = strings.ToUpper(v)
+	}
+	idem.Header = types.GetString(idem.Header, DefHeader)
+	idem.Expire = types.DecodeInt(idem.Expire, 0, 86400, idem.Expire)
+	idem.LockTimeout = types.DecodeInt(idem.LockTimeout, 0, 60, idem.LockTimeout)
+	idem.PathMatch = conf.NewPathMatch(idem.Paths...)
+	return idem, nil
+}
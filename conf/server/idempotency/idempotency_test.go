@@ -0,0 +1,29 @@
+package idempotency
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestIdempotency_IsEnable(t *testing.T) {
+	tests := []struct {
+		name   string
+		idem   *Idempotency
+		path   string
+		method string
+		want   bool
+	}{
+		{name: "1. 未配置路径", idem: New(nil), path: "/order/create", method: "POST", want: false},
+		{name: "2. 完全匹配路径的POST请求", idem: New([]string{"/order/create"}), path: "/order/create", method: "POST", want: true},
+		{name: "3. 模糊匹配路径的小写put请求", idem: New([]string{"/order/*"}), path: "/order/create", method: "put", want: true},
+		{name: "4. 匹配路径的GET请求", idem: New([]string{"/order/create"}), path: "/order/create", method: "GET", want: false},
+		{name: "5. 不匹配的路径", idem: New([]string{"/order/create"}), path: "/order/query", method: "POST", want: false},
+		{name: "6. 自定义请求方法", idem: New([]string{"/order/**"}, WithMethods("GET")), path: "/order/a/b", method: "GET", want: true},
+		{name: "7. 禁用配置", idem: New([]string{"/order/create"}, WithDisable()), path: "/order/create", method: "POST", want: false},
+	}
+	for _, tt := range tests {
+		got := tt.idem.IsEnable(tt.path, tt.method)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
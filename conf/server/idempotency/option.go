@@ -0,0 +1,67 @@
+package idempotency
+
+//Option 配置选项
+type Option func(*Idempotency)
+
+//WithHeader 设置幂等键对应的请求头名称
+func WithHeader(name string) Option {
+	return func(a *Idempotency) {
+		a.Header = name
+	}
+}
+
+//WithParam 设置幂等键对应的请求参数名称
+func WithParam(name string) Option {
+	return func(a *Idempotency) {
+		a.Param = name
+	}
+}
+
+//WithCache 设置存储幂等结果的缓存配置名称
+func WithCache(name string) Option {
+	return func(a *Idempotency) {
+		a.Cache = name
+	}
+}
+
+//WithRedis 设置存储幂等结果的redis配置名称
+func WithRedis(name string) Option {
+	return func(a *Idempotency) {
+		a.Redis = name
+	}
+}
+
+//WithMethods 设置需要进行幂等处理的请求方法
+func WithMethods(methods ...string) Option {
+	return func(a *Idempotency) {
+		a.Methods = methods
+	}
+}
+
+//WithExpire 设置响应结果的保存时长(秒)
+func WithExpire(second int) Option {
+	return func(a *Idempotency) {
+		a.Expire = second
+	}
+}
+
+//WithLockTimeout 设置请求处理中的锁定时长(秒)
+func WithLockTimeout(second int) Option {
+	return func(a *Idempotency) {
+		a.LockTimeout = second
+	}
+}
+
+//WithDisable 禁用配置
+func WithDisable() Option {
+	return func(a *Idempotency) {
+		a.Disable = true
+	}
+}
+
+//WithEnable 启用配置
+func WithEnable() Option {
+	return func(a *Idempotency) {
+		a.Disable = false
+	}
+}
@@ -10,18 +10,76 @@ import (
 //TypeNodeName metric配置节点名
 const TypeNodeName = "metric"
 
+//DefCron 默认的上报周期
+const DefCron = "@every 10s"
+
+const (
+	//InfluxDB influxdb(v1)上报服务
+	InfluxDB = "influxdb"
+
+	//InfluxDB2 influxdb(v2)上报服务
+	InfluxDB2 = "influxdb2"
+
+	//Graphite graphite上报服务
+	Graphite = "graphite"
+
+	//OpenTSDB opentsdb上报服务
+	OpenTSDB = "opentsdb"
+
+	//StatsD statsd(udp)上报服务
+	StatsD = "statsd"
+
+	//Log 输出到日志
+	Log = "log"
+
+	//JSON 以json格式输出到文件
+	JSON = "json"
+)
+
 type IMetric interface {
 	GetConf() (*Metric, bool)
 }
 
 //Metric Metric
 type Metric struct {
-	Host     string `json:"host,omitempty" valid:"requrl,required" toml:"host,omitempty"`
-	DataBase string `json:"dataBase,omitempty" valid:"ascii,required" toml:"dataBase,omitempty"`
-	Cron     string `json:"cron,omitempty" valid:"ascii,required" toml:"cron,omitempty"`
+	Host      string      `json:"host,omitempty" valid:"requrl" toml:"host,omitempty"`
+	DataBase  string      `json:"dataBase,omitempty" valid:"ascii" toml:"dataBase,omitempty"`
+	Cron      string      `json:"cron,omitempty" valid:"ascii" toml:"cron,omitempty"`
+	UserName  string      `json:"userName,omitempty" valid:"ascii" toml:"userName,omitempty"`
+	Password  string      `json:"password,omitempty" valid:"ascii" toml:"password,omitempty"`
+	Reporters []*Reporter `json:"reporters,omitempty" toml:"reporters,omitempty"`
+	Disable   bool        `json:"disable,omitempty" toml:"disable,omitempty"`
+}
+
+//Reporter 上报服务配置
+type Reporter struct {
+
+	//Type 上报服务类型(influxdb,influxdb2,graphite,opentsdb,statsd,log,json)
+	Type string `json:"type" valid:"in(influxdb|influxdb2|graphite|opentsdb|statsd|log|json),required" toml:"type"`
+
+	//Address 服务地址，influxdb为url，graphite,opentsdb,statsd为host:port
+	Address string `json:"address,omitempty" valid:"ascii" toml:"address,omitempty"`
+
+	//DataBase influxdb数据库名称或influxdb2的bucket
+	DataBase string `json:"dataBase,omitempty" valid:"ascii" toml:"dataBase,omitempty"`
+
+	//Org influxdb2组织名称
+	Org string `json:"org,omitempty" valid:"ascii" toml:"org,omitempty"`
+
+	//Token influxdb2访问令牌
+	Token string `json:"token,omitempty" valid:"ascii" toml:"token,omitempty"`
+
+	//UserName influxdb用户名
 	UserName string `json:"userName,omitempty" valid:"ascii" toml:"userName,omitempty"`
+
+	//Password influxdb密码
 	Password string `json:"password,omitempty" valid:"ascii" toml:"password,omitempty"`
-	Disable  bool   `json:"disable,omitempty" toml:"disable,omitempty"`
+
+	//Prefix graphite,opentsdb,statsd指标名称前缀
+	Prefix string `json:"prefix,omitempty" valid:"ascii" toml:"prefix,omitempty"`
+
+	//Path json文件路径
+	Path string `json:"path,omitempty" toml:"path,omitempty"`
 }
 
 //New 构建api server配置信息
@@ -37,6 +95,39 @@ func New(host string, db string, cron string, opts ...Option) *Metric {
 	return m
 }
 
+//NewReporter 构建上报服务配置
+func NewReporter(tp string, address string, opts ...ReporterOption) *Reporter {
+	r := &Reporter{Type: tp, Address: address}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+//GetReporters 获取所有上报服务，host不为空时作为influxdb上报服务
+func (m *Metric) GetReporters() []*Reporter {
+	if m.Host == "" {
+		return m.Reporters
+	}
+	reporters := make([]*Reporter, 0, len(m.Reporters)+1)
+	reporters = append(reporters, &Reporter{
+		Type:     InfluxDB,
+		Address:  m.Host,
+		DataBase: m.DataBase,
+		UserName: m.UserName,
+		Password: m.Password,
+	})
+	return append(reporters, m.Reporters...)
+}
+
+//GetCron 获取上报周期
+func (m *Metric) GetCron() string {
+	if m.Cron == "" {
+		return DefCron
+	}
+	return m.Cron
+}
+
 //GetConf 设置metric
 func GetConf(cnf conf.IServerConf) (metric *Metric, err error) {
 	metric = &Metric{}
@@ -52,5 +143,20 @@ func GetConf(cnf conf.IServerConf) (metric *Metric, err error) {
 	if b, err := govalidator.ValidateStruct(metric); !b {
 		return nil, fmt.Errorf("metric配置数据有误:%v", err)
 	}
+	reporters := metric.GetReporters()
+	if len(reporters) == 0 {
+		return nil, fmt.Errorf("metric配置数据有误:未指定上报服务")
+	}
+	for _, r := range reporters {
+		if b, err := govalidator.ValidateStruct(r); !b {
+			return nil, fmt.Errorf("metric配置数据有误:%s %v", r.Type, err)
+		}
+		if r.Address == "" && r.Type != Log && r.Type != JSON {
+			return nil, fmt.Errorf("metric配置数据有误:%s未指定服务地址", r.Type)
+		}
+		if r.Type == JSON && r.Path == "" {
+			return nil, fmt.Errorf("metric配置数据有误:json未指定文件路径")
+		}
+	}
 	return
 }
@@ -0,0 +1,27 @@
+package metric
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestMetric_GetReporters(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric *Metric
+		want   []string
+	}{
+		{name: "1. 只配置influxdb地址", metric: New("http://192.168.0.1:8086", "db", ""), want: []string{InfluxDB}},
+		{name: "2. 只配置上报服务", metric: New("", "", "", WithReporter(NewReporter(StatsD, "127.0.0.1:8125"), NewReporter(Log, ""))), want: []string{StatsD, Log}},
+		{name: "3. 同时配置influxdb地址与上报服务", metric: New("http://192.168.0.1:8086", "db", "", WithReporter(NewReporter(Graphite, "127.0.0.1:2003"))), want: []string{InfluxDB, Graphite}},
+	}
+	for _, tt := range tests {
+		got := make([]string, 0, 2)
+		for _, r := range tt.metric.GetReporters() {
+			got = append(got, r.Type)
+		}
+		assert.Equal(t, tt.want, got, tt.name)
+		assert.Equal(t, DefCron, tt.metric.GetCron(), tt.name)
+	}
+}
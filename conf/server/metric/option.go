@@ -24,3 +24,50 @@ func WithEnable() Option {
 		a.Disable = false
 	}
 }
+
+//WithReporter 添加上报服务
+func WithReporter(reporters ...*Reporter) Option {
+	return func(a *Metric) {
+		a.Reporters = append(a.Reporters, reporters...)
+	}
+}
+
+//ReporterOption 上报服务配置选项
+type ReporterOption func(*Reporter)
+
+//WithDataBase 设置influxdb数据库名称或influxdb2的bucket
+func WithDataBase(db string) ReporterOption {
+	return func(r *Reporter) {
+		r.DataBase = db
+	}
+}
+
+//WithToken 设置influxdb2的组织名称与访问令牌
+func WithToken(org string, token string) ReporterOption {
+	return func(r *Reporter) {
+		r.Org = org
+		r.Token = token
+	}
+}
+
+//WithAuth 设置influxdb用户名密码
+func WithAuth(userName string, password string) ReporterOption {
+	return func(r *Reporter) {
+		r.UserName = userName
+		r.Password = password
+	}
+}
+
+//WithPrefix 设置指标名称前缀
+func WithPrefix(prefix string) ReporterOption {
+	return func(r *Reporter) {
+		r.Prefix = prefix
+	}
+}
+
+//WithPath 设置json文件路径
+func WithPath(path string) ReporterOption {
+	return func(r *Reporter) {
+		r.Path = path
+	}
+}
@@ -19,7 +19,7 @@ const (
 var MainConfName = []string{"status", "sharding"}
 
 //SubConfName 子配置中的关键配置名
-var SubConfName = []string{"queue"}
+var SubConfName = []string{"queue", "prometheus"}
 
 //Server mqc服务配置
 type Server struct {
@@ -0,0 +1,53 @@
+package profile
+
+//Option 配置选项
+type Option func(*Profile)
+
+//WithThreshold 设置慢请求阈值(毫秒)
+func WithThreshold(ms int) Option {
+	return func(p *Profile) {
+		p.Threshold = ms
+	}
+}
+
+//WithRule 设置路径的慢请求阈值
+func WithRule(rules ...*Rule) Option {
+	return func(p *Profile) {
+		p.Rules = append(p.Rules, rules...)
+	}
+}
+
+//WithTop 设置输出的最慢记录数
+func WithTop(n int) Option {
+	return func(p *Profile) {
+		p.Top = n
+	}
+}
+
+//WithPath 设置汇总数据输出路径
+func WithPath(path string) Option {
+	return func(p *Profile) {
+		p.Path = path
+	}
+}
+
+//WithAddress 设置独立的监听地址,如::9101
+func WithAddress(address string) Option {
+	return func(p *Profile) {
+		p.Address = address
+	}
+}
+
+//WithDisable 禁用配置
+func WithDisable() Option {
+	return func(p *Profile) {
+		p.Disable = true
+	}
+}
+
+//WithEnable 启用配置
+func WithEnable() Option {
+	return func(p *Profile) {
+		p.Disable = false
+	}
+}
@@ -0,0 +1,108 @@
+/*
+慢请求分析配置，记录每个请求各中间件阶段及数据库、缓存、RPC、HTTP等组件调用的耗时，
+处理时长超过阈值时输出耗时明细日志，并汇总最慢的请求与组件调用，通过/debug/slow输出。
+汇总数据仅在指定的独立端口上输出，未指定独立端口时不输出，避免绕过认证与黑白名单在服务端口上公开。
+*/
+
+package profile
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/lib4go/types"
+)
+
+//TypeNodeName 慢请求分析配置节点名
+const TypeNodeName = "profile"
+
+//DefPath 默认的汇总数据输出路径
+const DefPath = "/debug/slow"
+
+//DefThreshold 默认的慢请求阈值(毫秒)
+const DefThreshold = 1000
+
+//DefTop 默认输出的最慢记录数
+const DefTop = 20
+
+//Profile 慢请求分析配置
+type Profile struct {
+
+	//Threshold 慢请求阈值(毫秒)
+	Threshold int `json:"threshold,omitempty" toml:"threshold,omitempty"`
+
+	//Rules 按路径设置的慢请求阈值
+	Rules []*Rule `json:"rules,omitempty" toml:"rules,omitempty"`
+
+	//Top 输出的最慢记录数
+	Top int `json:"top,omitempty" toml:"top,omitempty"`
+
+	//Path 汇总数据输出路径
+	Path string `json:"path,omitempty" valid:"ascii" toml:"path,omitempty"`
+
+	//Address 独立的监听地址,如::9101，未指定时不输出汇总数据
+	Address string `json:"address,omitempty" valid:"ascii" toml:"address,omitempty"`
+
+	Disable bool `json:"disable,omitempty" toml:"disable,omitempty"`
+}
+
+//Rule 按路径设置的慢请求阈值
+type Rule struct {
+	Paths     []string `json:"paths" valid:"required" toml:"paths"`
+	Threshold int      `json:"threshold" valid:"required" toml:"threshold"`
+	match     *conf.PathMatch
+}
+
+//New 构建慢请求分析配置
+func New(opts ...Option) *Profile {
+	p := &Profile{Threshold: DefThreshold, Top: DefTop, Path: DefPath}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+//NewRule 构建路径的慢请求阈值(毫秒)
+func NewRule(threshold int, paths ...string) *Rule {
+	return &Rule{Paths: paths, Threshold: threshold, match: conf.NewPathMatch(paths...)}
+}
+
+//GetThreshold 获取路径的慢请求阈值
+func (p *Profile) GetThreshold(path string) time.Duration {
+	for _, r := range p.Rules {
+		if r.match == nil {
+			continue
+		}
+		if ok, _ := r.match.Match(path); ok {
+			return time.Duration(r.Threshold) * time.Millisecond
+		}
+	}
+	return time.Duration(p.Threshold) * time.Millisecond
+}
+
+//GetConf 获取慢请求分析配置
+func GetConf(cnf conf.IServerConf) (*Profile, error) {
+	p := &Profile{}
+	_, err := cnf.GetSubObject(TypeNodeName, p)
+	if err == conf.ErrNoSetting {
+		return &Profile{Disable: true}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("profile配置格式有误:%v", err)
+	}
+	if b, err := govalidator.ValidateStruct(p); !b {
+		return nil, fmt.Errorf("profile配置数据有误:%v", err)
+	}
+	for _, r := range p.Rules {
+		if b, err := govalidator.ValidateStruct(r); !b {
+			return nil, fmt.Errorf("profile配置数据有误:%v", err)
+		}
+		r.match = conf.NewPathMatch(r.Paths...)
+	}
+	p.Threshold = types.DecodeInt(p.Threshold, 0, DefThreshold, p.Threshold)
+	p.Top = types.DecodeInt(p.Top, 0, DefTop, p.Top)
+	p.Path = types.GetString(p.Path, DefPath)
+	return p, nil
+}
@@ -0,0 +1,24 @@
+package profile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestProfile_GetThreshold(t *testing.T) {
+	p := New(WithThreshold(500), WithRule(NewRule(3000, "/order/export", "/report/**")))
+	tests := []struct {
+		name string
+		path string
+		want time.Duration
+	}{
+		{name: "1. 未匹配路径,使用默认阈值", path: "/order/query", want: 500 * time.Millisecond},
+		{name: "2. 完全匹配路径", path: "/order/export", want: 3 * time.Second},
+		{name: "3. 模糊匹配路径", path: "/report/day/sum", want: 3 * time.Second},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, p.GetThreshold(tt.path), tt.name)
+	}
+}
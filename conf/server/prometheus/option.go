@@ -0,0 +1,32 @@
+package prometheus
+
+//Option 配置选项
+type Option func(*Prometheus)
+
+//WithPath 设置统计数据输出路径
+func WithPath(path string) Option {
+	return func(p *Prometheus) {
+		p.Path = path
+	}
+}
+
+//WithAddress 设置独立的监听地址,如::9100
+func WithAddress(address string) Option {
+	return func(p *Prometheus) {
+		p.Address = address
+	}
+}
+
+//WithDisable 禁用配置
+func WithDisable() Option {
+	return func(p *Prometheus) {
+		p.Disable = true
+	}
+}
+
+//WithEnable 启用配置
+func WithEnable() Option {
+	return func(p *Prometheus) {
+		p.Disable = false
+	}
+}
@@ -0,0 +1,63 @@
+/*
+prometheus配置，按服务器类型、服务、请求方法、状态码统计请求数与处理时长，并通过/metrics输出prometheus或OpenMetrics
+文本格式的统计数据及go运行时指标。api,web服务器未指定独立端口时使用当前服务端口，rpc,mqc,cron服务器需指定独立端口。
+*/
+
+package prometheus
+
+import (
+	"fmt"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/lib4go/types"
+)
+
+//TypeNodeName prometheus配置节点名
+const TypeNodeName = "prometheus"
+
+//DefPath 默认的统计数据输出路径
+const DefPath = "/metrics"
+
+//Prometheus prometheus配置
+type Prometheus struct {
+
+	//Path 统计数据输出路径
+	Path string `json:"path,omitempty" valid:"ascii" toml:"path,omitempty"`
+
+	//Address 独立的监听地址,如::9100
+	Address string `json:"address,omitempty" valid:"ascii" toml:"address,omitempty"`
+
+	Disable bool `json:"disable,omitempty" toml:"disable,omitempty"`
+}
+
+//New 构建prometheus配置
+func New(opts ...Option) *Prometheus {
+	p := &Prometheus{Path: DefPath}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+//IsMetricPath 是否是统计数据输出路径
+func (p *Prometheus) IsMetricPath(path string) bool {
+	return !p.Disable && p.Address == "" && path == p.Path
+}
+
+//GetConf 获取prometheus配置
+func GetConf(cnf conf.IServerConf) (*Prometheus, error) {
+	p := &Prometheus{}
+	_, err := cnf.GetSubObject(TypeNodeName, p)
+	if err == conf.ErrNoSetting {
+		return &Prometheus{Disable: true}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("prometheus配置格式有误:%v", err)
+	}
+	if b, err := govalidator.ValidateStruct(p); !b {
+		return nil, fmt.Errorf("prometheus配置数据有误:%v", err)
+	}
+	p.Path = types.GetString(p.Path, DefPath)
+	return p, nil
+}
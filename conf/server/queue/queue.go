@@ -1,11 +1,42 @@
 package queue
 
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	//BackoffFixed 固定重试间隔
+	BackoffFixed = "fixed"
+	//BackoffExponential 重试间隔按次数加倍
+	BackoffExponential = "exponential"
+)
+
+//MaxBackoff 最大重试间隔
+const MaxBackoff = 10 * time.Minute
+
 //Queue 配置参数
 type Queue struct {
 	Queue       string `json:"queue,omitempty" valid:"ascii,required" toml:"queue,omitempty"`
 	Service     string `json:"service,omitempty" valid:"ascii,required" toml:"service,omitempty"`
 	Concurrency int    `json:"concurrency,omitempty" toml:"concurrency,omitempty"`
-	Disable     bool   `json:"disable,omitempty" toml:"disable,omitempty"`
+
+	//Attempts 最大处理次数，包含首次处理，处理失败且未超过最大次数时重新放入队列
+	Attempts int `json:"attempts,omitempty" toml:"attempts,omitempty"`
+
+	//Backoff 首次重试间隔(毫秒)，重试消息立即放入队列，消费时等待到期后处理，等待期间占用消费协程
+	Backoff int `json:"backoff,omitempty" toml:"backoff,omitempty"`
+
+	//BackoffMode 重试间隔方式fixed,exponential
+	BackoffMode string `json:"backoffMode,omitempty" valid:"in(fixed|exponential)" toml:"backoffMode,omitempty"`
+
+	//Jitter 重试间隔是否随机抖动，抖动范围为间隔的一半
+	Jitter bool `json:"jitter,omitempty" toml:"jitter,omitempty"`
+
+	//DeadLetter 死信队列名称，超过最大处理次数的消息放入死信队列，未设置时丢弃
+	DeadLetter string `json:"deadLetter,omitempty" valid:"ascii" toml:"deadLetter,omitempty"`
+
+	Disable bool `json:"disable,omitempty" toml:"disable,omitempty"`
 }
 
 //NewQueue 构建queue任务信息
@@ -20,6 +51,23 @@ func NewQueue(queue string, service string, opts ...Option) *Queue {
 	return q
 }
 
+//GetBackoff 获取第n次处理失败后的重试间隔
+func (q *Queue) GetBackoff(n int) time.Duration {
+	d := time.Duration(q.Backoff) * time.Millisecond
+	if q.BackoffMode == BackoffExponential {
+		for i := 1; i < n && d < MaxBackoff; i++ {
+			d *= 2
+		}
+	}
+	if d > MaxBackoff {
+		d = MaxBackoff
+	}
+	if q.Jitter && d > 1 {
+		d = d/2 + time.Duration(rand.Int63n(int64(d/2)))
+	}
+	return d
+}
+
 //Option Option
 type Option func(q *Queue)
 
@@ -30,6 +78,35 @@ func WithConcurrency(concurrency int) Option {
 	}
 }
 
+//WithRetry 设置最大处理次数及首次重试间隔(毫秒)
+func WithRetry(attempts int, backoff int) Option {
+	return func(q *Queue) {
+		q.Attempts = attempts
+		q.Backoff = backoff
+	}
+}
+
+//WithExponentialBackoff 重试间隔按次数加倍
+func WithExponentialBackoff() Option {
+	return func(q *Queue) {
+		q.BackoffMode = BackoffExponential
+	}
+}
+
+//WithJitter 重试间隔随机抖动
+func WithJitter() Option {
+	return func(q *Queue) {
+		q.Jitter = true
+	}
+}
+
+//WithDeadLetter 设置死信队列
+func WithDeadLetter(queue string) Option {
+	return func(q *Queue) {
+		q.DeadLetter = queue
+	}
+}
+
 //WithDisable 禁用
 func WithDisable() Option {
 	return func(q *Queue) {
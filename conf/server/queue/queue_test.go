@@ -0,0 +1,31 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestQueue_GetBackoff(t *testing.T) {
+	tests := []struct {
+		name string
+		q    *Queue
+		n    int
+		want time.Duration
+	}{
+		{name: "1. 未设置重试间隔", q: NewQueue("order", "/order"), n: 1, want: 0},
+		{name: "2. 固定间隔", q: NewQueue("order", "/order", WithRetry(3, 100)), n: 3, want: 100 * time.Millisecond},
+		{name: "3. 加倍间隔", q: NewQueue("order", "/order", WithRetry(3, 100), WithExponentialBackoff()), n: 3, want: 400 * time.Millisecond},
+		{name: "4. 超过最大间隔", q: NewQueue("order", "/order", WithRetry(30, 1000), WithExponentialBackoff()), n: 20, want: MaxBackoff},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.q.GetBackoff(tt.n), tt.name)
+	}
+
+	q := NewQueue("order", "/order", WithRetry(3, 100), WithJitter())
+	for i := 0; i < 10; i++ {
+		d := q.GetBackoff(1)
+		assert.Equal(t, true, d >= 50*time.Millisecond && d < 100*time.Millisecond, "5. 随机抖动")
+	}
+}
@@ -43,6 +43,8 @@ func (q *Queues) Append(queues ...*Queue) (*Queues, []*Queue) {
 				queue.Disable = v.Disable
 				queue.Concurrency = v.Concurrency
 			}
+			queue.Attempts, queue.Backoff, queue.BackoffMode = v.Attempts, v.Backoff, v.BackoffMode
+			queue.Jitter, queue.DeadLetter = v.Jitter, v.DeadLetter
 			continue
 		}
 		notifyQueues = append(notifyQueues, v)
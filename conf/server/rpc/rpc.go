@@ -28,7 +28,7 @@ const DefaultRPCAddress = ":8090"
 var MainConfName = []string{"address", "status", "rTimeout", "wTimeout", "rhTimeout", "dn"}
 
 //SubConfName 子配置中的关键配置名
-var SubConfName = []string{"router", "metric"}
+var SubConfName = []string{"router", "metric", "prometheus"}
 
 //Server rpc server配置信息
 type Server struct {
@@ -2,12 +2,16 @@ package server
 
 import (
 	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/conf/server/delay"
+	"github.com/micro-plat/hydra/conf/server/history"
 	"github.com/micro-plat/hydra/conf/server/task"
 )
 
 type CronSub struct {
-	cnf  conf.IServerConf
-	task *Loader
+	cnf     conf.IServerConf
+	task    *Loader
+	history *Loader
+	delay   *Loader
 }
 
 func NewCronSub(cnf conf.IServerConf) *CronSub {
@@ -17,6 +21,14 @@ func NewCronSub(cnf conf.IServerConf) *CronSub {
 			func(cnf conf.IServerConf) (interface{}, error) {
 				return task.GetConf(cnf)
 			}),
+		history: GetLoader(cnf,
+			func(cnf conf.IServerConf) (interface{}, error) {
+				return history.GetConf(cnf)
+			}),
+		delay: GetLoader(cnf,
+			func(cnf conf.IServerConf) (interface{}, error) {
+				return delay.GetConf(cnf)
+			}),
 	}
 }
 
@@ -28,3 +40,21 @@ func (s *CronSub) GetCRONTaskConf() (*task.Tasks, error) {
 	}
 	return taskObj.(*task.Tasks), nil
 }
+
+//GetCRONHistoryConf 获取cron执行历史配置
+func (s *CronSub) GetCRONHistoryConf() (*history.History, error) {
+	historyObj, err := s.history.GetConf()
+	if err != nil {
+		return nil, err
+	}
+	return historyObj.(*history.History), nil
+}
+
+//GetCRONDelayConf 获取cron延迟任务执行配置
+func (s *CronSub) GetCRONDelayConf() (*delay.Delay, error) {
+	delayObj, err := s.delay.GetConf()
+	if err != nil {
+		return nil, err
+	}
+	return delayObj.(*delay.Delay), nil
+}
@@ -3,16 +3,23 @@ package server
 import (
 	"github.com/micro-plat/hydra/conf"
 	"github.com/micro-plat/hydra/conf/server/acl/blacklist"
+	"github.com/micro-plat/hydra/conf/server/acl/fault"
 	"github.com/micro-plat/hydra/conf/server/acl/limiter"
+	"github.com/micro-plat/hydra/conf/server/acl/mirror"
 	"github.com/micro-plat/hydra/conf/server/acl/proxy"
 	"github.com/micro-plat/hydra/conf/server/acl/whitelist"
 	"github.com/micro-plat/hydra/conf/server/apm"
+	"github.com/micro-plat/hydra/conf/server/audit"
 	"github.com/micro-plat/hydra/conf/server/auth/apikey"
 	"github.com/micro-plat/hydra/conf/server/auth/basic"
 	"github.com/micro-plat/hydra/conf/server/auth/jwt"
 	"github.com/micro-plat/hydra/conf/server/auth/ras"
+	"github.com/micro-plat/hydra/conf/server/cache"
 	"github.com/micro-plat/hydra/conf/server/header"
+	"github.com/micro-plat/hydra/conf/server/idempotency"
 	"github.com/micro-plat/hydra/conf/server/metric"
+	"github.com/micro-plat/hydra/conf/server/profile"
+	"github.com/micro-plat/hydra/conf/server/prometheus"
 	"github.com/micro-plat/hydra/conf/server/render"
 	"github.com/micro-plat/hydra/conf/server/router"
 	"github.com/micro-plat/hydra/conf/server/static"
@@ -34,6 +41,13 @@ type HttpSub struct {
 	limit     *Loader
 	proxy     *Loader
 	apm       *Loader
+	idem      *Loader
+	cache     *Loader
+	mirror    *Loader
+	fault     *Loader
+	prom      *Loader
+	audit     *Loader
+	profile   *Loader
 }
 
 func NewHttpSub(cnf conf.IServerConf) *HttpSub {
@@ -52,6 +66,13 @@ func NewHttpSub(cnf conf.IServerConf) *HttpSub {
 	s.limit = GetLoader(cnf, s.getLimiterFunc())
 	s.proxy = GetLoader(cnf, s.getProxyFunc())
 	s.apm = GetLoader(cnf, s.getAPMFunc())
+	s.idem = GetLoader(cnf, s.getIdempotencyFunc())
+	s.cache = GetLoader(cnf, s.getCacheFunc())
+	s.mirror = GetLoader(cnf, s.getMirrorFunc())
+	s.fault = GetLoader(cnf, s.getFaultFunc())
+	s.prom = GetLoader(cnf, s.getPrometheusFunc())
+	s.audit = GetLoader(cnf, s.getAuditFunc())
+	s.profile = GetLoader(cnf, s.getProfileFunc())
 	return s
 }
 
@@ -153,6 +174,20 @@ func (s HttpSub) getAPMFunc() func(cnf conf.IServerConf) (interface{}, error) {
 	}
 }
 
+//getIdempotencyFunc 获取幂等配置信息
+func (s HttpSub) getIdempotencyFunc() func(cnf conf.IServerConf) (interface{}, error) {
+	return func(cnf conf.IServerConf) (interface{}, error) {
+		return idempotency.GetConf(cnf)
+	}
+}
+
+//getCacheFunc 获取响应缓存配置信息
+func (s HttpSub) getCacheFunc() func(cnf conf.IServerConf) (interface{}, error) {
+	return func(cnf conf.IServerConf) (interface{}, error) {
+		return cache.GetConf(cnf)
+	}
+}
+
 //GetHeaderConf 获取响应头配置
 func (s *HttpSub) GetHeaderConf() (header.Headers, error) {
 	headerObj, err := s.header.GetConf()
@@ -284,3 +319,101 @@ func (s *HttpSub) GetAPMConf() (*apm.APM, error) {
 	}
 	return apmc.(*apm.APM), nil
 }
+
+//GetIdempotencyConf 获取幂等配置
+func (s *HttpSub) GetIdempotencyConf() (*idempotency.Idempotency, error) {
+	idemObj, err := s.idem.GetConf()
+	if err != nil {
+		return nil, err
+	}
+	return idemObj.(*idempotency.Idempotency), nil
+}
+
+//GetCacheConf 获取响应缓存配置
+func (s *HttpSub) GetCacheConf() (*cache.Cache, error) {
+	cacheObj, err := s.cache.GetConf()
+	if err != nil {
+		return nil, err
+	}
+	return cacheObj.(*cache.Cache), nil
+}
+
+//getMirrorFunc 获取流量复制配置信息
+func (s HttpSub) getMirrorFunc() func(cnf conf.IServerConf) (interface{}, error) {
+	return func(cnf conf.IServerConf) (interface{}, error) {
+		return mirror.GetConf(cnf)
+	}
+}
+
+//GetMirrorConf 获取流量复制配置
+func (s *HttpSub) GetMirrorConf() (*mirror.Mirror, error) {
+	mirrorObj, err := s.mirror.GetConf()
+	if err != nil {
+		return nil, err
+	}
+	return mirrorObj.(*mirror.Mirror), nil
+}
+
+//getFaultFunc 获取故障注入配置信息
+func (s HttpSub) getFaultFunc() func(cnf conf.IServerConf) (interface{}, error) {
+	return func(cnf conf.IServerConf) (interface{}, error) {
+		return fault.GetConf(cnf)
+	}
+}
+
+//GetFaultConf 获取故障注入配置
+func (s *HttpSub) GetFaultConf() (*fault.Fault, error) {
+	faultObj, err := s.fault.GetConf()
+	if err != nil {
+		return nil, err
+	}
+	return faultObj.(*fault.Fault), nil
+}
+
+//getPrometheusFunc 获取prometheus配置信息
+func (s HttpSub) getPrometheusFunc() func(cnf conf.IServerConf) (interface{}, error) {
+	return func(cnf conf.IServerConf) (interface{}, error) {
+		return prometheus.GetConf(cnf)
+	}
+}
+
+//GetPrometheusConf 获取prometheus配置
+func (s *HttpSub) GetPrometheusConf() (*prometheus.Prometheus, error) {
+	promObj, err := s.prom.GetConf()
+	if err != nil {
+		return nil, err
+	}
+	return promObj.(*prometheus.Prometheus), nil
+}
+
+//getAuditFunc 获取审计配置信息
+func (s HttpSub) getAuditFunc() func(cnf conf.IServerConf) (interface{}, error) {
+	return func(cnf conf.IServerConf) (interface{}, error) {
+		return audit.GetConf(cnf)
+	}
+}
+
+//GetAuditConf 获取审计配置
+func (s *HttpSub) GetAuditConf() (*audit.Audit, error) {
+	auditObj, err := s.audit.GetConf()
+	if err != nil {
+		return nil, err
+	}
+	return auditObj.(*audit.Audit), nil
+}
+
+//getProfileFunc 获取慢请求分析配置信息
+func (s HttpSub) getProfileFunc() func(cnf conf.IServerConf) (interface{}, error) {
+	return func(cnf conf.IServerConf) (interface{}, error) {
+		return profile.GetConf(cnf)
+	}
+}
+
+//GetProfileConf 获取慢请求分析配置
+func (s *HttpSub) GetProfileConf() (*profile.Profile, error) {
+	profileObj, err := s.profile.GetConf()
+	if err != nil {
+		return nil, err
+	}
+	return profileObj.(*profile.Profile), nil
+}
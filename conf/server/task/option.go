@@ -16,3 +16,91 @@ func WithEnable() Option {
 		a.Disable = false
 	}
 }
+
+//WithMisfireSkip 跳过错过的执行
+func WithMisfireSkip() Option {
+	return func(a *Task) {
+		a.Misfire = MisfireSkip
+	}
+}
+
+//WithMisfireOnce 错过的执行补偿执行一次
+func WithMisfireOnce() Option {
+	return func(a *Task) {
+		a.Misfire = MisfireOnce
+	}
+}
+
+//WithMisfireAll 补偿执行所有错过的执行，最多执行max次
+func WithMisfireAll(max int) Option {
+	return func(a *Task) {
+		a.Misfire = MisfireAll
+		a.MaxMisfire = max
+	}
+}
+
+//WithZone 设置计算执行时间使用的时区，如Asia/Shanghai
+func WithZone(zone string) Option {
+	return func(a *Task) {
+		a.Zone = zone
+	}
+}
+
+//WithCalendar 设置任务日历，日历中排除的日期不执行任务
+func WithCalendar(name string) Option {
+	return func(a *Task) {
+		a.Calendar = name
+	}
+}
+
+//WithParams 设置执行任务时传入的参数
+func WithParams(params map[string]interface{}) Option {
+	return func(a *Task) {
+		a.Params = params
+	}
+}
+
+//WithHeader 设置执行任务时传入的头信息
+func WithHeader(name string, value string) Option {
+	return func(a *Task) {
+		if a.Headers == nil {
+			a.Headers = make(map[string]string)
+		}
+		a.Headers[name] = value
+	}
+}
+
+//WithTimeout 设置执行超时时长(秒)
+func WithTimeout(timeout int) Option {
+	return func(a *Task) {
+		a.Timeout = timeout
+	}
+}
+
+//WithOverlapAllow 按计划时间调度，上次执行未结束时仍执行本次任务
+func WithOverlapAllow() Option {
+	return func(a *Task) {
+		a.Overlap = OverlapAllow
+	}
+}
+
+//WithOverlapSkip 上次执行未结束时跳过本次执行
+func WithOverlapSkip() Option {
+	return func(a *Task) {
+		a.Overlap = OverlapSkip
+	}
+}
+
+//WithOverlapQueue 上次执行未结束时排队等待，结束后再执行一次
+func WithOverlapQueue() Option {
+	return func(a *Task) {
+		a.Overlap = OverlapQueue
+	}
+}
+
+//WithCluster 通过分布式锁在集群内执行overlap策略
+func WithCluster() Option {
+	return func(a *Task) {
+		a.Cluster = true
+	}
+}
@@ -0,0 +1,78 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/micro-plat/hydra/conf/vars/calendar"
+	"github.com/robfig/cron"
+)
+
+//secondParser 包含秒的6段cron表达式解析器
+var secondParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+//maxExcludedDays 跳过日历排除日期时最多查找的天数
+const maxExcludedDays = 3660
+
+//Schedule 任务执行计划，按任务的时区计算执行时间并跳过日历中排除的日期
+type Schedule struct {
+	schedule cron.Schedule
+	location *time.Location
+	calendar *calendar.Calendar
+}
+
+//GetSchedule 获取任务的执行计划，cal为任务日历，未指定日历时为nil
+//支持5段标准表达式、6段包含秒的表达式及@every 1h30m、@daily等描述符
+func (t *Task) GetSchedule(cal *calendar.Calendar) (*Schedule, error) {
+	if t.IsImmediately() {
+		return nil, fmt.Errorf("%s为立即执行的任务，没有执行计划", t.Service)
+	}
+	loc, err := t.GetLocation()
+	if err != nil {
+		return nil, err
+	}
+	parse := cron.ParseStandard
+	if !strings.HasPrefix(t.Cron, "@") && len(strings.Fields(t.Cron)) == 6 {
+		parse = secondParser.Parse
+	}
+	schedule, err := parse(t.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("%s的cron表达式(%s)配置有误 %w", t.Service, t.Cron, err)
+	}
+	return &Schedule{schedule: schedule, location: loc, calendar: cal}, nil
+}
+
+//Next 获取t之后的下次执行时间，无可执行时间时返回零值
+func (s *Schedule) Next(t time.Time) time.Time {
+	next := s.schedule.Next(t.In(s.location))
+	for i := 0; i < maxExcludedDays && !next.IsZero() && s.calendar.IsExcluded(next); i++ {
+		y, m, d := next.Date()
+		next = s.schedule.Next(time.Date(y, m, d+1, 0, 0, 0, 0, s.location).Add(-time.Second))
+	}
+	if s.calendar.IsExcluded(next) {
+		return time.Time{}
+	}
+	return next
+}
+
+//WithCalendar 复制执行计划并使用新的任务日历
+func (s *Schedule) WithCalendar(cal *calendar.Calendar) *Schedule {
+	n := *s
+	n.calendar = cal
+	return &n
+}
+
+//IsExcluded 指定时间是否被任务日历排除
+func (s *Schedule) IsExcluded(t time.Time) bool {
+	return s.calendar.IsExcluded(t.In(s.location))
+}
+
+//NextN 获取t之后的n次执行时间
+func (s *Schedule) NextN(t time.Time, n int) []time.Time {
+	times := make([]time.Time, 0, n)
+	for next := s.Next(t); !next.IsZero() && len(times) < n; next = s.Next(next) {
+		times = append(times, next)
+	}
+	return times
+}
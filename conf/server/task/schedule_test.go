@@ -0,0 +1,50 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro-plat/hydra/conf/vars/calendar"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestTask_GetSchedule(t *testing.T) {
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+	utc := time.Date(2020, 9, 30, 15, 59, 58, 0, time.UTC)
+	tests := []struct {
+		name    string
+		task    *Task
+		cal     *calendar.Calendar
+		from    time.Time
+		wantErr bool
+		want    []time.Time
+	}{
+		{name: "1. 立即执行的任务", task: NewTask(CronExecuteNow, "/order/query"), wantErr: true},
+		{name: "2. 错误的cron表达式", task: NewTask("* * *", "/order/query"), wantErr: true},
+		{name: "3. 错误的时区", task: NewTask("* * * * *", "/order/query", WithZone("Asia/None")), wantErr: true},
+		{name: "4. 5段标准表达式", task: NewTask("0 0 * * *", "/order/query", WithZone("Asia/Shanghai")), from: utc,
+			want: []time.Time{time.Date(2020, 10, 1, 0, 0, 0, 0, shanghai), time.Date(2020, 10, 2, 0, 0, 0, 0, shanghai)}},
+		{name: "5. 6段包含秒的表达式", task: NewTask("*/5 * * * * *", "/order/query", WithZone("Asia/Shanghai")), from: utc,
+			want: []time.Time{time.Date(2020, 10, 1, 0, 0, 0, 0, shanghai), time.Date(2020, 10, 1, 0, 0, 5, 0, shanghai)}},
+		{name: "6. @every表达式", task: NewTask("@every 10s", "/order/query", WithZone("UTC")), from: utc,
+			want: []time.Time{time.Date(2020, 9, 30, 16, 0, 8, 0, time.UTC), time.Date(2020, 9, 30, 16, 0, 18, 0, time.UTC)}},
+		{name: "7. 跳过日历排除的日期", task: NewTask("0 0 * * *", "/order/query", WithZone("Asia/Shanghai"), WithCalendar("holiday")),
+			cal: calendar.New("2020-10-01", "2020-10-02"), from: utc,
+			want: []time.Time{time.Date(2020, 10, 3, 0, 0, 0, 0, shanghai), time.Date(2020, 10, 4, 0, 0, 0, 0, shanghai)}},
+		{name: "8. 跳过日历排除日期中的秒级任务", task: NewTask("0 0 * * * *", "/order/query", WithZone("Asia/Shanghai"), WithCalendar("holiday")),
+			cal: calendar.New("2020-10-01"), from: utc,
+			want: []time.Time{time.Date(2020, 10, 2, 0, 0, 0, 0, shanghai), time.Date(2020, 10, 2, 1, 0, 0, 0, shanghai)}},
+	}
+	for _, tt := range tests {
+		s, err := tt.task.GetSchedule(tt.cal)
+		assert.Equalf(t, tt.wantErr, err != nil, tt.name, err)
+		if err != nil {
+			continue
+		}
+		got := s.NextN(tt.from, len(tt.want))
+		assert.Equalf(t, len(tt.want), len(got), tt.name)
+		for i := range tt.want {
+			assert.Equalf(t, true, tt.want[i].Equal(got[i]), tt.name, got[i])
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package task
 
 import (
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/micro-plat/lib4go/security/md5"
@@ -13,11 +15,60 @@ const CronExecuteImmediately = "@immediately"
 //CronExecuteNow 立即执行
 const CronExecuteNow = "@now"
 
+const (
+	//MisfireSkip 跳过错过的执行
+	MisfireSkip = "skip"
+	//MisfireOnce 错过的执行补偿执行一次
+	MisfireOnce = "once"
+	//MisfireAll 补偿执行所有错过的执行，最多MaxMisfire次
+	MisfireAll = "all"
+)
+
+const (
+	//OverlapAllow 上次执行未结束时仍按计划执行
+	OverlapAllow = "allow"
+	//OverlapSkip 上次执行未结束时跳过本次执行
+	OverlapSkip = "skip"
+	//OverlapQueue 上次执行未结束时排队等待，结束后再执行一次
+	OverlapQueue = "queue"
+)
+
+//DefMaxMisfire 默认最大补偿执行次数
+const DefMaxMisfire = 10
+
 //Task cron任务的task明细
 type Task struct {
 	Cron    string `json:"cron,omitempty" valid:"ascii,required" toml:"cron,omitempty"`
 	Service string `json:"service,omitempty" valid:"ascii,required" toml:"service,omitempty"`
 	Disable bool   `json:"disable,omitempty" toml:"disable,omitempty"`
+
+	//Misfire 服务器启动或主节点切换后对错过执行的处理策略,skip:跳过,once:补偿一次,all:全部补偿，人工暂停后恢复时不补偿
+	Misfire string `json:"misfire,omitempty" valid:"in(skip|once|all)" toml:"misfire,omitempty"`
+
+	//MaxMisfire 全部补偿时的最大执行次数
+	MaxMisfire int `json:"maxMisfire,omitempty" toml:"maxMisfire,omitempty"`
+
+	//Zone 计算执行时间使用的时区，如Asia/Shanghai，默认为服务器本地时区
+	Zone string `json:"zone,omitempty" toml:"zone,omitempty"`
+
+	//Calendar 任务日历名称，对应/var/calendar/名称，日历中排除的日期不执行
+	Calendar string `json:"calendar,omitempty" toml:"calendar,omitempty"`
+
+	//Params 执行任务时传入的参数
+	Params map[string]interface{} `json:"params,omitempty" toml:"params,omitempty"`
+
+	//Headers 执行任务时传入的头信息
+	Headers map[string]string `json:"headers,omitempty" toml:"headers,omitempty"`
+
+	//Timeout 执行超时时长(秒)，超时后撤销处理程序的context
+	Timeout int `json:"timeout,omitempty" toml:"timeout,omitempty"`
+
+	//Overlap 上次执行未结束时的处理策略,allow:继续执行,skip:跳过,queue:排队执行一次。
+	//未设置时执行结束后再计算下次执行时间，任务不会重叠执行；设置后按计划时间调度，重叠的执行按策略处理
+	Overlap string `json:"overlap,omitempty" valid:"in(allow|skip|queue)" toml:"overlap,omitempty"`
+
+	//Cluster 是否通过分布式锁在集群内执行overlap策略，默认仅限当前节点
+	Cluster bool `json:"cluster,omitempty" toml:"cluster,omitempty"`
 }
 
 //NewTask 创建任务信息
@@ -33,9 +84,21 @@ func NewTask(cron string, service string, opts ...Option) *Task {
 	return t
 }
 
-//GetUNQ 获取任务的唯一标识
+//GetUNQ 获取任务的唯一标识，相同服务和表达式的任务以参数区分
 func (t *Task) GetUNQ() string {
-	return md5.Encrypt(fmt.Sprintf("%s(%s)", t.Service, t.Cron))
+	if len(t.Params) == 0 {
+		return md5.Encrypt(fmt.Sprintf("%s(%s)", t.Service, t.Cron))
+	}
+	buff, _ := json.Marshal(t.Params)
+	return md5.Encrypt(fmt.Sprintf("%s(%s)%s", t.Service, t.Cron, buff))
+}
+
+//GetOverlap 获取上次执行未结束时的处理策略
+func (t *Task) GetOverlap() string {
+	if t.Overlap == "" {
+		return OverlapAllow
+	}
+	return t.Overlap
 }
 
 //IsImmediately 是否立即
@@ -43,10 +106,45 @@ func (t *Task) IsImmediately() bool {
 	return t.Cron == CronExecuteNow || t.Cron == CronExecuteImmediately
 }
 
+//GetMisfire 获取错过执行的补偿次数，n为错过的执行次数
+func (t *Task) GetMisfire(n int) int {
+	switch t.Misfire {
+	case MisfireOnce:
+		if n > 0 {
+			return 1
+		}
+	case MisfireAll:
+		max := t.MaxMisfire
+		if max <= 0 {
+			max = DefMaxMisfire
+		}
+		if n > max {
+			return max
+		}
+		return n
+	}
+	return 0
+}
+
+//GetLocation 获取任务使用的时区
+func (t *Task) GetLocation() (*time.Location, error) {
+	if t.Zone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(t.Zone)
+	if err != nil {
+		return nil, fmt.Errorf("%s的时区(%s)配置有误 %w", t.Service, t.Zone, err)
+	}
+	return loc, nil
+}
+
 //Validate 验证任务参数
 func (t *Task) Validate() error {
 	if b, err := govalidator.ValidateStruct(t); !b && err != nil {
 		return fmt.Errorf("task配置有误:%v", err)
 	}
+	if _, err := t.GetLocation(); err != nil {
+		return fmt.Errorf("task配置有误:%v", err)
+	}
 	return nil
 }
@@ -0,0 +1,32 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestTask_GetUNQ(t *testing.T) {
+	a := NewTask("@every 10s", "/order/query")
+	b := NewTask("@every 10s", "/order/query", WithTimeout(10), WithOverlapSkip())
+	c := NewTask("@every 10s", "/order/query", WithParams(map[string]interface{}{"id": 1}))
+	d := NewTask("@every 10s", "/order/query", WithParams(map[string]interface{}{"id": 2}))
+	assert.Equal(t, a.GetUNQ(), b.GetUNQ(), "1. 未设置参数的任务标识不变")
+	assert.NotEqual(t, a.GetUNQ(), c.GetUNQ(), "2. 设置参数的任务标识不同")
+	assert.NotEqual(t, c.GetUNQ(), d.GetUNQ(), "3. 参数不同的任务标识不同")
+}
+
+func TestTask_GetOverlap(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{name: "1. 默认允许重叠执行", want: OverlapAllow},
+		{name: "2. 跳过重叠的执行", opts: []Option{WithOverlapSkip()}, want: OverlapSkip},
+		{name: "3. 排队执行一次", opts: []Option{WithOverlapQueue(), WithCluster()}, want: OverlapQueue},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, NewTask("@every 10s", "/order/query", tt.opts...).GetOverlap(), tt.name)
+	}
+}
@@ -64,6 +64,9 @@ func GetConf(cnf conf.IServerConf) (tasks *Tasks, err error) {
 		if b, err := govalidator.ValidateStruct(task); !b {
 			return nil, fmt.Errorf("task配置有误:%v", err)
 		}
+		if _, err := task.GetLocation(); err != nil {
+			return nil, fmt.Errorf("task配置有误:%v", err)
+		}
 	}
 	return tasks, nil
 }
@@ -0,0 +1,63 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/micro-plat/hydra/conf"
+)
+
+//TypeNodeName 分类节点名
+const TypeNodeName = "calendar"
+
+//DateFormat 排除日期的格式
+const DateFormat = "2006-01-02"
+
+//Calendar 任务日历，日历中排除的日期不执行任务
+type Calendar struct {
+	//Exclude 排除的日期，格式为2006-01-02
+	Exclude []string `json:"exclude,omitempty" toml:"exclude,omitempty"`
+
+	dates map[string]bool
+}
+
+//New 构建任务日历
+func New(exclude ...string) *Calendar {
+	c := &Calendar{Exclude: exclude}
+	c.init()
+	return c
+}
+
+func (c *Calendar) init() {
+	c.dates = make(map[string]bool, len(c.Exclude))
+	for _, d := range c.Exclude {
+		c.dates[d] = true
+	}
+}
+
+//IsExcluded 指定时间所在的日期是否被排除
+func (c *Calendar) IsExcluded(t time.Time) bool {
+	if c == nil {
+		return false
+	}
+	return c.dates[t.Format(DateFormat)]
+}
+
+//GetConf 获取任务日历配置
+func GetConf(varConf conf.IVarConf, name string) (c *Calendar, err error) {
+	c = &Calendar{}
+	_, err = varConf.GetObject(TypeNodeName, name, c)
+	if err == conf.ErrNoSetting {
+		return nil, fmt.Errorf("未配置：/var/%s/%s", TypeNodeName, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取./var/%s/%s 配置发生错误 %w", TypeNodeName, name, err)
+	}
+	for _, d := range c.Exclude {
+		if _, err := time.Parse(DateFormat, d); err != nil {
+			return nil, fmt.Errorf("./var/%s/%s 配置的日期(%s)有误，格式应为%s", TypeNodeName, name, d, DateFormat)
+		}
+	}
+	c.init()
+	return c, nil
+}
@@ -0,0 +1,124 @@
+/*
+延迟任务存储配置，保存在/平台/var/delay/名称节点，延迟任务组件按此配置保存任务，cron服务器按此配置获取到期任务并执行。
+存储方式：
+redis:保存到redis有序集合，需指定/var/redis下的配置名称
+db:保存到数据库表，需指定数据库配置名称并预先创建表：
+	create table hydra_delay_job(id varchar(32) primary key,job_key varchar(64),at bigint,content varchar(4000))
+	create unique index idx_hydra_delay_job_key on hydra_delay_job(job_key)
+registry:保存到注册中心/平台/delay/名称/jobs/任务编号节点，适用于任务较少的场景
+*/
+
+package delay
+
+import (
+	"fmt"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+)
+
+//TypeNodeName 分类节点名
+const TypeNodeName = "delay"
+
+//DefName 默认配置名称
+const DefName = "delay"
+
+const (
+	//ProtoRedis 保存到redis
+	ProtoRedis = "redis"
+	//ProtoDB 保存到数据库
+	ProtoDB = "db"
+	//ProtoRegistry 保存到注册中心
+	ProtoRegistry = "registry"
+)
+
+const (
+	//DefKey redis默认的键前缀
+	DefKey = "hydra:delay"
+	//DefTable 默认的数据库表名
+	DefTable = "hydra_delay_job"
+	//DefRetry 默认最大重试次数
+	DefRetry = 3
+	//DefBackoff 默认首次重试间隔(秒)，之后每次加倍
+	DefBackoff = 10
+	//DefTimeout 默认执行超时时长(秒)
+	DefTimeout = 60
+)
+
+//Delay 延迟任务存储配置
+type Delay struct {
+	//Proto 存储方式redis,db,registry
+	Proto string `json:"proto" valid:"in(redis|db|registry),required" toml:"proto"`
+
+	//Redis redis配置名称
+	Redis string `json:"redis,omitempty" toml:"redis,omitempty"`
+
+	//Key redis键前缀
+	Key string `json:"key,omitempty" toml:"key,omitempty"`
+
+	//DB 数据库配置名称
+	DB string `json:"db,omitempty" toml:"db,omitempty"`
+
+	//Table 数据库表名
+	Table string `json:"table,omitempty" valid:"ascii" toml:"table,omitempty"`
+
+	//Retry 执行失败后的最大重试次数
+	Retry int `json:"retry,omitempty" toml:"retry,omitempty"`
+
+	//Backoff 首次重试间隔(秒)，之后每次加倍
+	Backoff int `json:"backoff,omitempty" toml:"backoff,omitempty"`
+
+	//Timeout 执行超时时长(秒)，超时未完成的任务重新执行
+	Timeout int `json:"timeout,omitempty" toml:"timeout,omitempty"`
+}
+
+//New 构建延迟任务存储配置
+func New(proto string, opts ...Option) *Delay {
+	d := &Delay{Proto: proto}
+	for _, opt := range opts {
+		opt(d)
+	}
+	d.setDefault()
+	return d
+}
+
+func (d *Delay) setDefault() {
+	if d.Key == "" {
+		d.Key = DefKey
+	}
+	if d.Table == "" {
+		d.Table = DefTable
+	}
+	if d.Retry <= 0 {
+		d.Retry = DefRetry
+	}
+	if d.Backoff <= 0 {
+		d.Backoff = DefBackoff
+	}
+	if d.Timeout <= 0 {
+		d.Timeout = DefTimeout
+	}
+}
+
+//GetConf 获取延迟任务存储配置
+func GetConf(varConf conf.IVarConf, name string) (d *Delay, err error) {
+	d = &Delay{}
+	_, err = varConf.GetObject(TypeNodeName, name, d)
+	if err == conf.ErrNoSetting {
+		return nil, fmt.Errorf("未配置：/var/%s/%s", TypeNodeName, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取./var/%s/%s 配置发生错误 %w", TypeNodeName, name, err)
+	}
+	if b, err := govalidator.ValidateStruct(d); !b {
+		return nil, fmt.Errorf("./var/%s/%s 配置数据有误:%v", TypeNodeName, name, err)
+	}
+	switch {
+	case d.Proto == ProtoRedis && d.Redis == "":
+		return nil, fmt.Errorf("./var/%s/%s 配置数据有误:未指定redis配置名称", TypeNodeName, name)
+	case d.Proto == ProtoDB && d.DB == "":
+		return nil, fmt.Errorf("./var/%s/%s 配置数据有误:未指定数据库配置名称", TypeNodeName, name)
+	}
+	d.setDefault()
+	return d, nil
+}
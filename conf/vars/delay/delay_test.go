@@ -0,0 +1,24 @@
+package delay
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name  string
+		proto string
+		opts  []Option
+		want  *Delay
+	}{
+		{name: "1. 默认配置", proto: ProtoRegistry, want: &Delay{Proto: ProtoRegistry, Key: DefKey, Table: DefTable, Retry: DefRetry, Backoff: DefBackoff, Timeout: DefTimeout}},
+		{name: "2. redis存储", proto: ProtoRedis, opts: []Option{WithRedis("redis", "order:delay")}, want: &Delay{Proto: ProtoRedis, Redis: "redis", Key: "order:delay", Table: DefTable, Retry: DefRetry, Backoff: DefBackoff, Timeout: DefTimeout}},
+		{name: "3. 数据库存储", proto: ProtoDB, opts: []Option{WithDB("db", "order_delay")}, want: &Delay{Proto: ProtoDB, DB: "db", Key: DefKey, Table: "order_delay", Retry: DefRetry, Backoff: DefBackoff, Timeout: DefTimeout}},
+		{name: "4. 设置重试及超时", proto: ProtoRegistry, opts: []Option{WithRetry(5, 30), WithTimeout(120)}, want: &Delay{Proto: ProtoRegistry, Key: DefKey, Table: DefTable, Retry: 5, Backoff: 30, Timeout: 120}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, New(tt.proto, tt.opts...), tt.name)
+	}
+}
@@ -0,0 +1,39 @@
+package delay
+
+//Option 配置选项
+type Option func(*Delay)
+
+//WithRedis 设置redis配置名称及键前缀
+func WithRedis(name string, key ...string) Option {
+	return func(a *Delay) {
+		a.Redis = name
+		if len(key) > 0 {
+			a.Key = key[0]
+		}
+	}
+}
+
+//WithDB 设置数据库配置名称及表名
+func WithDB(name string, table ...string) Option {
+	return func(a *Delay) {
+		a.DB = name
+		if len(table) > 0 {
+			a.Table = table[0]
+		}
+	}
+}
+
+//WithRetry 设置最大重试次数及首次重试间隔(秒)
+func WithRetry(max int, backoff int) Option {
+	return func(a *Delay) {
+		a.Retry = max
+		a.Backoff = backoff
+	}
+}
+
+//WithTimeout 设置执行超时时长(秒)
+func WithTimeout(timeout int) Option {
+	return func(a *Delay) {
+		a.Timeout = timeout
+	}
+}
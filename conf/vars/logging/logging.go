@@ -0,0 +1,305 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/registry"
+	"github.com/micro-plat/lib4go/logger"
+)
+
+//LogName 日志配置名称
+const LogName = "logging"
+
+//TypeNodeName 分类节点名
+const TypeNodeName = "app"
+
+//DefaultMask 敏感信息默认替换值
+const DefaultMask = "******"
+
+//DefaultRedactHeaders 未配置时也需脱敏的请求头
+var DefaultRedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "X-API-Key"}
+
+//Logging 日志配置，支持全局与按服务设置日志级别、请求日志采样、敏感信息脱敏和临时调试
+type Logging struct {
+	Level    string    `json:"level,omitempty" valid:"in(Off|Info|Warn|Error|Fatal|Debug|All)" toml:"level,omitempty"`
+	Rules    []*Rule   `json:"rules,omitempty" toml:"rules,omitempty"`
+	Sampling *Sampling `json:"sampling,omitempty" toml:"sampling,omitempty"`
+	Redact   *Redact   `json:"redact,omitempty" toml:"redact,omitempty"`
+	Debugs   []*Debug  `json:"debugs,omitempty" toml:"debugs,omitempty"`
+	Disable  bool      `json:"disable,omitempty" toml:"disable,omitempty"`
+	level    int
+	match    []*conf.PathMatch
+	sampling *conf.PathMatch
+}
+
+//Rule 按服务设置日志级别
+type Rule struct {
+	Services []string `json:"services" valid:"required" toml:"services"`
+	Level    string   `json:"level" valid:"required,in(Off|Info|Warn|Error|Fatal|Debug|All)" toml:"level"`
+}
+
+//Sampling 请求日志采样配置，失败请求与慢请求始终记录
+type Sampling struct {
+	Services []string `json:"services,omitempty" toml:"services,omitempty"`
+	Rate     float64  `json:"rate" valid:"range(0|1)" toml:"rate"`
+	Slow     int      `json:"slow,omitempty" toml:"slow,omitempty"`
+}
+
+//Redact 敏感信息脱敏配置
+type Redact struct {
+	Params  []string `json:"params,omitempty" toml:"params,omitempty"`
+	Headers []string `json:"headers,omitempty" toml:"headers,omitempty"`
+	Mask    string   `json:"mask,omitempty" toml:"mask,omitempty"`
+}
+
+//Debug 临时调试开关，指定请求编号或用户在过期时间(unix秒)前输出所有级别日志
+type Debug struct {
+	RequestID string `json:"request-id,omitempty" toml:"request-id,omitempty"`
+	User      string `json:"user,omitempty" toml:"user,omitempty"`
+	Expire    int64  `json:"expire" valid:"required" toml:"expire"`
+}
+
+//New 构建日志配置
+func New(opts ...Option) *Logging {
+	l := &Logging{Level: "Info"}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+//GetLevel 获取服务的日志级别，未配置服务规则时使用全局级别
+func (l *Logging) GetLevel(service string) int {
+	if l == nil || l.Disable {
+		return logger.ILevel_ALL
+	}
+	for i, r := range l.Rules {
+		if ok, _ := l.match[i].Match(service); ok {
+			return logger.GetLevel(r.Level)
+		}
+	}
+	return l.level
+}
+
+//HasDebug 是否存在未过期的调试开关
+func (l *Logging) HasDebug() bool {
+	if l == nil || l.Disable {
+		return false
+	}
+	now := time.Now().Unix()
+	for _, d := range l.Debugs {
+		if d.Expire > now {
+			return true
+		}
+	}
+	return false
+}
+
+//IsDebug 请求编号或用户是否处于调试状态
+func (l *Logging) IsDebug(requestID string, user ...string) bool {
+	if l == nil || l.Disable {
+		return false
+	}
+	now := time.Now().Unix()
+	for _, d := range l.Debugs {
+		if d.Expire <= now {
+			continue
+		}
+		if d.RequestID != "" && d.RequestID == requestID {
+			return true
+		}
+		if d.User == "" {
+			continue
+		}
+		for _, u := range user {
+			if d.User == u {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//IsSampled 服务请求日志是否需要记录，同一请求编号的结果相同
+func (l *Logging) IsSampled(service string, requestID string) bool {
+	if l == nil || l.Disable || l.Sampling == nil {
+		return true
+	}
+	if len(l.Sampling.Services) > 0 {
+		if ok, _ := l.sampling.Match(service); !ok {
+			return true
+		}
+	}
+	if l.Sampling.Rate >= 1 || l.IsDebug(requestID) {
+		return true
+	}
+	if l.Sampling.Rate <= 0 {
+		return false
+	}
+	h := fnv.New32a()
+	h.Write([]byte(requestID))
+	return float64(h.Sum32()%10000) < l.Sampling.Rate*10000
+}
+
+//IsSlow 请求是否为慢请求，慢请求始终记录日志
+func (l *Logging) IsSlow(d time.Duration) bool {
+	if l == nil || l.Disable || l.Sampling == nil || l.Sampling.Slow <= 0 {
+		return false
+	}
+	return d >= time.Duration(l.Sampling.Slow)*time.Millisecond
+}
+
+//RedactMap 将参数中的敏感信息替换为掩码，返回新的map
+func (l *Logging) RedactMap(input map[string]interface{}) map[string]interface{} {
+	if l == nil || l.Disable || l.Redact == nil {
+		return input
+	}
+	return redact(input, l.Redact.Params, l.Redact.Mask)
+}
+
+//RedactHeaders 将请求头中的敏感信息替换为掩码，返回新的map，DefaultRedactHeaders中的请求头始终脱敏
+func (l *Logging) RedactHeaders(input map[string]interface{}) map[string]interface{} {
+	if l == nil || l.Disable || l.Redact == nil {
+		return redact(input, DefaultRedactHeaders, DefaultMask)
+	}
+	keys := make([]string, 0, len(l.Redact.Headers)+len(DefaultRedactHeaders))
+	keys = append(append(keys, l.Redact.Headers...), DefaultRedactHeaders...)
+	return redact(input, keys, l.Redact.Mask)
+}
+
+//RedactURL 将地址查询参数中的敏感信息替换为掩码
+func (l *Logging) RedactURL(u string) string {
+	if l == nil || l.Disable || l.Redact == nil || len(l.Redact.Params) == 0 {
+		return u
+	}
+	i := strings.Index(u, "?")
+	if i < 0 {
+		return u
+	}
+	values, err := url.ParseQuery(u[i+1:])
+	if err != nil {
+		return u
+	}
+	changed := false
+	for k := range values {
+		if contains(l.Redact.Params, k) {
+			values[k] = []string{l.Redact.Mask}
+			changed = true
+		}
+	}
+	if !changed {
+		return u
+	}
+	return u[:i+1] + values.Encode()
+}
+
+func (l *Logging) init() error {
+	if b, err := govalidator.ValidateStruct(l); !b {
+		return err
+	}
+	if l.Level == "" {
+		l.Level = "Info"
+	}
+	l.level = logger.GetLevel(l.Level)
+	l.match = make([]*conf.PathMatch, 0, len(l.Rules))
+	for _, r := range l.Rules {
+		if b, err := govalidator.ValidateStruct(r); !b {
+			return err
+		}
+		l.match = append(l.match, conf.NewPathMatch(r.Services...))
+	}
+	if l.Sampling != nil {
+		if b, err := govalidator.ValidateStruct(l.Sampling); !b {
+			return err
+		}
+		l.sampling = conf.NewPathMatch(l.Sampling.Services...)
+	}
+	if l.Redact != nil && l.Redact.Mask == "" {
+		l.Redact.Mask = DefaultMask
+	}
+	for _, d := range l.Debugs {
+		if b, err := govalidator.ValidateStruct(d); !b {
+			return err
+		}
+		if d.RequestID == "" && d.User == "" {
+			return fmt.Errorf("调试开关未指定请求编号或用户")
+		}
+	}
+	return nil
+}
+
+func redact(input map[string]interface{}, keys []string, mask string) map[string]interface{} {
+	if len(keys) == 0 || len(input) == 0 {
+		return input
+	}
+	out := make(map[string]interface{}, len(input))
+	for k, v := range input {
+		if contains(keys, k) {
+			out[k] = mask
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+func contains(list []string, k string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, k) {
+			return true
+		}
+	}
+	return false
+}
+
+//GetConfByAddr 获取日志配置
+func GetConfByAddr(r registry.IRegistry, platName string) (s *Logging, err error) {
+	path := registry.Join(platName, "var", TypeNodeName, LogName)
+	ok, err := r.Exists(path)
+	if err != nil {
+		return nil, fmt.Errorf("检查日志配置出错 %s %w", path, err)
+	}
+	if !ok {
+		return &Logging{Disable: true}, nil
+	}
+	buff, _, err := r.GetValue(path)
+	if err != nil {
+		return nil, fmt.Errorf("获取日志配置出错 %s %w", path, err)
+	}
+	return parse(path, buff)
+}
+
+//GetConf 获取日志配置
+func GetConf(cnf conf.IVarConf) (s *Logging, err error) {
+	s = &Logging{}
+	_, err = cnf.GetObject(TypeNodeName, LogName, s)
+	if err != nil && err != conf.ErrNoSetting {
+		return nil, fmt.Errorf("读取./var/%s/%s 配置发生错误 %w", TypeNodeName, LogName, err)
+	}
+	if err == conf.ErrNoSetting {
+		s.Disable = true
+		return s, nil
+	}
+	if err := s.init(); err != nil {
+		return nil, fmt.Errorf("./var/%s/%s 配置有误 %w", TypeNodeName, LogName, err)
+	}
+	return s, nil
+}
+
+func parse(path string, buff []byte) (*Logging, error) {
+	s := &Logging{}
+	if err := json.Unmarshal(buff, s); err != nil {
+		return nil, fmt.Errorf("日志配置出错 %s %v", path, err)
+	}
+	if err := s.init(); err != nil {
+		return nil, fmt.Errorf("%s 配置有误 %w", path, err)
+	}
+	return s, nil
+}
@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro-plat/lib4go/assert"
+	"github.com/micro-plat/lib4go/logger"
+)
+
+func TestLogging_GetLevel(t *testing.T) {
+	l := New(WithLevel("Warn"), WithRule("Debug", "/order/**"), WithRule("Off", "/health"))
+	assert.Equal(t, nil, l.init(), "初始化配置")
+	tests := []struct {
+		name    string
+		service string
+		want    int
+	}{
+		{name: "1. 未匹配服务规则,使用全局级别", service: "/user/query", want: logger.ILevel_Warn},
+		{name: "2. 模糊匹配服务规则", service: "/order/pay/notify", want: logger.ILevel_Debug},
+		{name: "3. 完全匹配服务规则", service: "/health", want: logger.ILevel_OFF},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, l.GetLevel(tt.service), tt.name)
+	}
+	assert.Equal(t, logger.ILevel_ALL, New(WithDisable()).GetLevel("/order"), "4. 禁用配置,不限制日志级别")
+}
+
+func TestLogging_IsDebug(t *testing.T) {
+	l := New(WithDebugRequest("abc", 10*time.Minute), WithDebugUser("colin", 10*time.Minute))
+	l.Debugs = append(l.Debugs, &Debug{RequestID: "expired", Expire: time.Now().Add(-time.Minute).Unix()})
+	assert.Equal(t, nil, l.init(), "初始化配置")
+	tests := []struct {
+		name      string
+		requestID string
+		users     []string
+		want      bool
+	}{
+		{name: "1. 请求编号处于调试状态", requestID: "abc", want: true},
+		{name: "2. 用户处于调试状态", requestID: "def", users: []string{"", "colin"}, want: true},
+		{name: "3. 调试开关已过期", requestID: "expired", want: false},
+		{name: "4. 未配置调试开关", requestID: "def", users: []string{"jim"}, want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, l.IsDebug(tt.requestID, tt.users...), tt.name)
+	}
+	assert.Equal(t, true, l.HasDebug(), "5. 存在未过期的调试开关")
+}
+
+func TestLogging_IsSampled(t *testing.T) {
+	tests := []struct {
+		name    string
+		logging *Logging
+		service string
+		want    int
+	}{
+		{name: "1. 未配置采样,全部记录", logging: New(), service: "/order", want: 1000},
+		{name: "2. 采样比例为0,全部不记录", logging: New(WithSampling(0, 0)), service: "/order", want: 0},
+		{name: "3. 未匹配采样服务,全部记录", logging: New(WithSampling(0, 0, "/order/**")), service: "/user", want: 1000},
+		{name: "4. 按比例采样", logging: New(WithSampling(0.5, 0, "/order/**")), service: "/order/query", want: 500},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, nil, tt.logging.init(), tt.name)
+		got := 0
+		for i := 0; i < 1000; i++ {
+			if tt.logging.IsSampled(tt.service, string(rune('a'+i%26))+time.Duration(i).String()) {
+				got++
+			}
+		}
+		if tt.want == 500 {
+			assert.Equal(t, true, got > 400 && got < 600, tt.name, got)
+			continue
+		}
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
+
+func TestLogging_Redact(t *testing.T) {
+	l := New(WithRedactParams("password", "card_no"), WithRedactHeaders("Authorization"))
+	assert.Equal(t, nil, l.init(), "初始化配置")
+
+	params := l.RedactMap(map[string]interface{}{"password": "123456", "name": "colin"})
+	assert.Equal(t, map[string]interface{}{"password": DefaultMask, "name": "colin"}, params, "1. 参数脱敏")
+
+	headers := l.RedactHeaders(map[string]interface{}{"authorization": "Bearer xxx", "Content-Type": "json"})
+	assert.Equal(t, map[string]interface{}{"authorization": DefaultMask, "Content-Type": "json"}, headers, "2. 请求头脱敏,不区分大小写")
+
+	assert.Equal(t, "/order?card_no=%2A%2A%2A%2A%2A%2A&id=1", l.RedactURL("/order?card_no=6222&id=1"), "3. 地址查询参数脱敏")
+	assert.Equal(t, "/order?id=1", l.RedactURL("/order?id=1"), "4. 无敏感参数,地址不变")
+
+	headers = l.RedactHeaders(map[string]interface{}{"Cookie": "sid=1", "X-Api-Key": "key"})
+	assert.Equal(t, map[string]interface{}{"Cookie": DefaultMask, "X-Api-Key": DefaultMask}, headers, "5. 默认脱敏的请求头")
+
+	var empty *Logging
+	headers = empty.RedactHeaders(map[string]interface{}{"Authorization": "Bearer xxx", "Content-Type": "json"})
+	assert.Equal(t, map[string]interface{}{"Authorization": DefaultMask, "Content-Type": "json"}, headers, "6. 未配置日志时默认脱敏")
+}
+
+func TestParse(t *testing.T) {
+	_, err := parse("/hydra/var/app/logging", []byte(`{"level":"Trace"}`))
+	assert.Equal(t, true, err != nil, "1. 日志级别有误")
+
+	_, err = parse("/hydra/var/app/logging", []byte(`{"debugs":[{"expire":1}]}`))
+	assert.Equal(t, true, err != nil, "2. 调试开关未指定请求编号或用户")
+
+	l, err := parse("/hydra/var/app/logging", []byte(`{"level":"Error","sampling":{"rate":0.1,"slow":500}}`))
+	assert.Equal(t, nil, err, "3. 配置正确")
+	assert.Equal(t, true, l.IsSlow(time.Second), "3. 慢请求")
+	assert.Equal(t, nil, Set(l), "3. 设置当前配置")
+	assert.Equal(t, logger.ILevel_Error, Current().GetLevel("/order"), "3. 当前配置生效")
+}
@@ -0,0 +1,83 @@
+package logging
+
+import "time"
+
+//Option 配置选项
+type Option func(*Logging)
+
+//WithLevel 设置全局日志级别
+func WithLevel(level string) Option {
+	return func(a *Logging) {
+		a.Level = level
+	}
+}
+
+//WithRule 设置服务的日志级别，服务支持模糊匹配
+func WithRule(level string, services ...string) Option {
+	return func(a *Logging) {
+		a.Rules = append(a.Rules, &Rule{Services: services, Level: level})
+	}
+}
+
+//WithSampling 设置请求日志采样比例(0-1)，slow为慢请求阈值(毫秒)，未指定服务时对所有服务采样
+func WithSampling(rate float64, slow int, services ...string) Option {
+	return func(a *Logging) {
+		a.Sampling = &Sampling{Rate: rate, Slow: slow, Services: services}
+	}
+}
+
+//WithRedactParams 设置需要脱敏的请求参数
+func WithRedactParams(params ...string) Option {
+	return func(a *Logging) {
+		a.redact().Params = append(a.redact().Params, params...)
+	}
+}
+
+//WithRedactHeaders 设置需要脱敏的请求头
+func WithRedactHeaders(headers ...string) Option {
+	return func(a *Logging) {
+		a.redact().Headers = append(a.redact().Headers, headers...)
+	}
+}
+
+//WithMask 设置脱敏后的替换值
+func WithMask(mask string) Option {
+	return func(a *Logging) {
+		a.redact().Mask = mask
+	}
+}
+
+//WithDebugRequest 指定请求编号在d时间内输出所有级别日志
+func WithDebugRequest(requestID string, d time.Duration) Option {
+	return func(a *Logging) {
+		a.Debugs = append(a.Debugs, &Debug{RequestID: requestID, Expire: time.Now().Add(d).Unix()})
+	}
+}
+
+//WithDebugUser 指定用户在d时间内输出所有级别日志
+func WithDebugUser(user string, d time.Duration) Option {
+	return func(a *Logging) {
+		a.Debugs = append(a.Debugs, &Debug{User: user, Expire: time.Now().Add(d).Unix()})
+	}
+}
+
+//WithDisable 禁用日志配置
+func WithDisable() Option {
+	return func(a *Logging) {
+		a.Disable = true
+	}
+}
+
+//WithEnable 启用日志配置
+func WithEnable() Option {
+	return func(a *Logging) {
+		a.Disable = false
+	}
+}
+
+func (l *Logging) redact() *Redact {
+	if l.Redact == nil {
+		l.Redact = &Redact{}
+	}
+	return l.Redact
+}
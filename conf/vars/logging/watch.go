@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/micro-plat/hydra/registry"
+	"github.com/micro-plat/hydra/registry/watcher"
+	"github.com/micro-plat/lib4go/logger"
+)
+
+var current atomic.Value
+
+var closeCh chan struct{}
+var lock sync.Mutex
+
+func init() {
+	current.Store(&Logging{Disable: true})
+}
+
+//Current 获取当前生效的日志配置，未配置时返回禁用的配置
+func Current() *Logging {
+	return current.Load().(*Logging)
+}
+
+//Set 设置当前生效的日志配置
+func Set(l *Logging) error {
+	if l == nil {
+		l = &Logging{Disable: true}
+	}
+	if err := l.init(); err != nil {
+		return err
+	}
+	current.Store(l)
+	return nil
+}
+
+//Watch 加载注册中心中的日志配置，并监控配置变化，变化后立即生效
+func Watch(r registry.IRegistry, platName string, log logger.ILogging) error {
+	l, err := GetConfByAddr(r, platName)
+	if err != nil {
+		return err
+	}
+	if err := Set(l); err != nil {
+		return err
+	}
+
+	path := registry.Join(platName, "var", TypeNodeName, LogName)
+	w, err := watcher.NewValueWatcherByRegistry(r, []string{path}, log)
+	if err != nil {
+		return fmt.Errorf("日志配置watcher初始化失败 %s,%w", path, err)
+	}
+	notify, err := w.Start()
+	if err != nil {
+		return err
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if closeCh != nil {
+		close(closeCh)
+	}
+	closeCh = make(chan struct{})
+	go loop(w, notify, closeCh, log)
+	return nil
+}
+
+//Close 停止监控日志配置
+func Close() {
+	lock.Lock()
+	defer lock.Unlock()
+	if closeCh != nil {
+		close(closeCh)
+		closeCh = nil
+	}
+}
+
+func loop(w watcher.IValueWatcher, notify chan *watcher.ValueChangeArgs, closeCh chan struct{}, log logger.ILogging) {
+	defer w.Close()
+	for {
+		select {
+		case <-closeCh:
+			return
+		case u := <-notify:
+			if u.OP == watcher.DEL {
+				Set(nil)
+				log.Info("日志配置已删除:", u.Path)
+				continue
+			}
+			l, err := parse(u.Path, u.Content)
+			if err != nil {
+				log.Error(err)
+				continue
+			}
+			current.Store(l)
+			log.Info("日志配置已更新:", u.Path)
+		}
+	}
+}
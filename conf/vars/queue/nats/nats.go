@@ -0,0 +1,103 @@
+package nats
+
+import (
+	"fmt"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf/vars/queue"
+)
+
+const (
+	//ProtoNATS nats消息队列协议名，消息不持久化，无消费者时消息丢失
+	ProtoNATS = "nats"
+	//ProtoJetStream jetstream消息队列协议名，消息持久化，需确认消息
+	ProtoJetStream = "jetstream"
+)
+
+const (
+	//DefGroup 默认的队列组名称
+	DefGroup = "hydra"
+	//DefStream 默认的jetstream流名称
+	DefStream = "HYDRA"
+	//DefAckWait 默认的确认超时时长(秒)
+	DefAckWait = 30
+	//DefDialTimeout 默认的连接超时时长(秒)
+	DefDialTimeout = 3
+)
+
+//NATS nats消息队列配置
+type NATS struct {
+	*queue.Queue
+
+	//Address 服务器地址，多个地址用逗号分隔，如：nats://192.168.0.1:4222
+	Address string `json:"address" toml:"address" valid:"required"`
+
+	UserName string `json:"userName,omitempty" toml:"userName,omitempty"`
+	Password string `json:"password,omitempty" toml:"password,omitempty"`
+	Token    string `json:"token,omitempty" toml:"token,omitempty"`
+
+	//DialTimeout 连接超时时长(秒)
+	DialTimeout int `json:"dial_timeout,omitempty" toml:"dial_timeout,omitempty"`
+
+	//Group 队列组名称，同一队列组内的消息只被一个消费者处理，jetstream作为持久消费者名称
+	Group string `json:"group,omitempty" toml:"group,omitempty" valid:"ascii"`
+
+	//Stream jetstream流名称，流的主题为"流名称.>"，队列名称作为子主题。流使用工作队列保留策略，消息确认后删除，
+	//每个队列只能由一个队列组消费
+	Stream string `json:"stream,omitempty" toml:"stream,omitempty" valid:"alphanum"`
+
+	//AckWait jetstream确认超时时长(秒)，超时未确认的消息重新投递
+	AckWait int `json:"ack_wait,omitempty" toml:"ack_wait,omitempty"`
+
+	//MaxDeliver jetstream最大投递次数，为0时不限制
+	MaxDeliver int `json:"max_deliver,omitempty" toml:"max_deliver,omitempty"`
+
+	//RedeliveryDelay jetstream消息未确认时重新投递的延迟时长(毫秒)
+	RedeliveryDelay int `json:"redelivery_delay,omitempty" toml:"redelivery_delay,omitempty"`
+}
+
+//New 构建nats消息队列配置
+func New(address string, opts ...Option) *NATS {
+	return newNATS(ProtoNATS, address, opts...)
+}
+
+//NewJetStream 构建jetstream消息队列配置
+func NewJetStream(address string, opts ...Option) *NATS {
+	return newNATS(ProtoJetStream, address, opts...)
+}
+
+func newNATS(proto string, address string, opts ...Option) *NATS {
+	n := &NATS{
+		Queue:   &queue.Queue{Proto: proto},
+		Address: address,
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	n.setDefault()
+	return n
+}
+
+//NewByRaw 通过json原串初始化
+func NewByRaw(raw string) *NATS {
+	org := New("", WithRaw(raw))
+	if b, err := govalidator.ValidateStruct(org); !b {
+		panic(fmt.Errorf("nats配置数据有误:%v %+v", err, org))
+	}
+	return org
+}
+
+func (n *NATS) setDefault() {
+	if n.Group == "" {
+		n.Group = DefGroup
+	}
+	if n.Stream == "" {
+		n.Stream = DefStream
+	}
+	if n.AckWait <= 0 {
+		n.AckWait = DefAckWait
+	}
+	if n.DialTimeout <= 0 {
+		n.DialTimeout = DefDialTimeout
+	}
+}
@@ -0,0 +1,41 @@
+package nats
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestNew(t *testing.T) {
+	n := New("nats://192.168.0.1:4222", WithUP("hydra", "123456"), WithGroup("order"))
+	assert.Equal(t, ProtoNATS, n.Proto, "1. 协议名")
+	assert.Equal(t, "nats://192.168.0.1:4222", n.Address, "2. 服务器地址")
+	assert.Equal(t, "hydra", n.UserName, "3. 用户名")
+	assert.Equal(t, "order", n.Group, "4. 队列组")
+	assert.Equal(t, DefStream, n.Stream, "5. 默认流名称")
+	assert.Equal(t, DefAckWait, n.AckWait, "6. 默认确认超时时长")
+
+	j := NewJetStream("nats://192.168.0.1:4222", WithStream("ORDER"), WithAck(10, 5), WithRedeliveryDelay(500))
+	assert.Equal(t, ProtoJetStream, j.Proto, "7. 协议名")
+	assert.Equal(t, DefGroup, j.Group, "8. 默认队列组")
+	assert.Equal(t, "ORDER", j.Stream, "9. 流名称")
+	assert.Equal(t, 10, j.AckWait, "10. 确认超时时长")
+	assert.Equal(t, 5, j.MaxDeliver, "11. 最大投递次数")
+	assert.Equal(t, 500, j.RedeliveryDelay, "12. 重新投递延迟")
+}
+
+func TestNewByRaw(t *testing.T) {
+	buff, err := json.Marshal(NewJetStream("nats://192.168.0.1:4222", WithToken("abc"), WithAck(10, 5)))
+	assert.Equal(t, nil, err, "1. 序列化配置")
+	j := NewByRaw(string(buff))
+	assert.Equal(t, ProtoJetStream, j.Proto, "2. 协议名")
+	assert.Equal(t, "abc", j.Token, "3. 认证令牌")
+	assert.Equal(t, 10, j.AckWait, "4. 确认超时时长")
+	assert.Equal(t, DefStream, j.Stream, "5. 默认流名称")
+
+	defer func() {
+		assert.Equal(t, true, recover() != nil, "6. 未设置服务器地址")
+	}()
+	NewByRaw(`{"proto":"nats"}`)
+}
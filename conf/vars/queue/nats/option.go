@@ -0,0 +1,59 @@
+package nats
+
+import "encoding/json"
+
+//Option 配置选项
+type Option func(*NATS)
+
+//WithUP 设置用户名密码
+func WithUP(userName string, password string) Option {
+	return func(a *NATS) {
+		a.UserName = userName
+		a.Password = password
+	}
+}
+
+//WithToken 设置认证令牌
+func WithToken(token string) Option {
+	return func(a *NATS) {
+		a.Token = token
+	}
+}
+
+//WithGroup 设置队列组名称
+func WithGroup(group string) Option {
+	return func(a *NATS) {
+		a.Group = group
+	}
+}
+
+//WithStream 设置jetstream流名称
+func WithStream(stream string) Option {
+	return func(a *NATS) {
+		a.Stream = stream
+	}
+}
+
+//WithAck 设置jetstream确认超时时长(秒)及最大投递次数
+func WithAck(ackWait int, maxDeliver int) Option {
+	return func(a *NATS) {
+		a.AckWait = ackWait
+		a.MaxDeliver = maxDeliver
+	}
+}
+
+//WithRedeliveryDelay 设置未确认消息重新投递的延迟时长(毫秒)
+func WithRedeliveryDelay(delay int) Option {
+	return func(a *NATS) {
+		a.RedeliveryDelay = delay
+	}
+}
+
+//WithRaw 通过json原串初始化
+func WithRaw(raw string) Option {
+	return func(o *NATS) {
+		if err := json.Unmarshal([]byte(raw), o); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package redisstream
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/micro-plat/hydra/conf/vars/queue/queueredis"
+)
+
+//Option 配置选项
+type Option func(*Stream)
+
+//WithConfigName 设置/var/redis下的redis配置名称
+func WithConfigName(configName string) Option {
+	return func(s *Stream) {
+		s.ConfigName = configName
+	}
+}
+
+//WithGroup 设置消费组名称
+func WithGroup(group string) Option {
+	return func(s *Stream) {
+		s.Group = group
+	}
+}
+
+//WithMaxLen 设置队列最大长度，approx为true时近似裁剪
+func WithMaxLen(maxLen int64, approx bool) Option {
+	return func(s *Stream) {
+		s.MaxLen = maxLen
+		s.Approx = approx
+	}
+}
+
+//WithClaimTimeout 设置待确认消息的超时时长(秒)
+func WithClaimTimeout(timeout int) Option {
+	return func(s *Stream) {
+		s.ClaimTimeout = timeout
+	}
+}
+
+//WithRaw 通过json原串初始化
+func WithRaw(raw string) Option {
+	return func(s *Stream) {
+		queueredis.WithRaw(raw)(s.Redis)
+		opts := struct {
+			Group        string `json:"group"`
+			MaxLen       int64  `json:"max_len"`
+			Approx       bool   `json:"approx"`
+			ClaimTimeout int    `json:"claim_timeout"`
+		}{}
+		if err := json.Unmarshal([]byte(raw), &opts); err != nil {
+			panic(fmt.Errorf("redisstream.WithRaw:%w", err))
+		}
+		s.Group, s.MaxLen, s.Approx, s.ClaimTimeout = opts.Group, opts.MaxLen, opts.Approx, opts.ClaimTimeout
+	}
+}
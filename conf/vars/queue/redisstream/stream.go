@@ -0,0 +1,71 @@
+package redisstream
+
+import (
+	"fmt"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/micro-plat/hydra/conf/vars/queue"
+	"github.com/micro-plat/hydra/conf/vars/queue/queueredis"
+	"github.com/micro-plat/hydra/conf/vars/redis"
+)
+
+//Proto redis stream消息队列协议名
+const Proto = "redis-stream"
+
+//DefGroup 默认消费组名称
+const DefGroup = "hydra"
+
+//DefClaimTimeout 默认的待确认消息超时时长(秒)
+const DefClaimTimeout = 60
+
+//Stream redis stream消息队列配置
+type Stream struct {
+	*queueredis.Redis
+
+	//Group 消费组名称，同一消费组内的消息只被一个消费者处理
+	Group string `json:"group,omitempty" toml:"group,omitempty" valid:"ascii"`
+
+	//MaxLen 队列最大长度，超过时裁剪最早的消息，为0时不裁剪
+	MaxLen int64 `json:"max_len,omitempty" toml:"max_len,omitempty"`
+
+	//Approx 是否近似裁剪，近似裁剪性能更高，实际长度可能略大于MaxLen
+	Approx bool `json:"approx,omitempty" toml:"approx,omitempty"`
+
+	//ClaimTimeout 待确认消息的超时时长(秒)，消费者超时未确认的消息由其它消费者重新处理
+	ClaimTimeout int `json:"claim_timeout,omitempty" toml:"claim_timeout,omitempty"`
+}
+
+//New 构建redis stream消息队列配置
+func New(address string, opts ...Option) *Stream {
+	s := &Stream{
+		Redis: &queueredis.Redis{
+			Queue: &queue.Queue{Proto: Proto},
+			Redis: redis.New(address),
+		},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.setDefault()
+	if b, err := govalidator.ValidateStruct(s); !b {
+		panic(fmt.Errorf("redis-stream配置数据有误:%v %+v", err, s))
+	}
+	if s.ConfigName == "" && len(s.Addrs) == 0 {
+		panic(fmt.Errorf("redis-stream配置数据有误:至少存在Addrs或ConfigName,%+v", s))
+	}
+	return s
+}
+
+//NewByRaw 通过json原串初始化
+func NewByRaw(raw string) *Stream {
+	return New("", WithRaw(raw))
+}
+
+func (s *Stream) setDefault() {
+	if s.Group == "" {
+		s.Group = DefGroup
+	}
+	if s.ClaimTimeout <= 0 {
+		s.ClaimTimeout = DefClaimTimeout
+	}
+}
@@ -0,0 +1,33 @@
+package redisstream
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestNew(t *testing.T) {
+	s := New("192.168.0.1:6379", WithGroup("order"), WithMaxLen(1000, true), WithClaimTimeout(30))
+	assert.Equal(t, Proto, s.Proto, "1. 协议名")
+	assert.Equal(t, []string{"192.168.0.1:6379"}, s.Addrs, "2. redis地址")
+	assert.Equal(t, "order", s.Group, "3. 消费组")
+	assert.Equal(t, int64(1000), s.MaxLen, "4. 最大长度")
+	assert.Equal(t, true, s.Approx, "5. 近似裁剪")
+	assert.Equal(t, 30, s.ClaimTimeout, "6. 超时时长")
+
+	d := New("192.168.0.1:6379")
+	assert.Equal(t, DefGroup, d.Group, "7. 默认消费组")
+	assert.Equal(t, DefClaimTimeout, d.ClaimTimeout, "8. 默认超时时长")
+}
+
+func TestNewByRaw(t *testing.T) {
+	buff, err := json.Marshal(New("192.168.0.1:6379", WithGroup("order"), WithMaxLen(1000, false)))
+	assert.Equal(t, nil, err, "1. 序列化配置")
+	s := NewByRaw(string(buff))
+	assert.Equal(t, Proto, s.Proto, "2. 协议名")
+	assert.Equal(t, []string{"192.168.0.1:6379"}, s.Addrs, "3. redis地址")
+	assert.Equal(t, "order", s.Group, "4. 消费组")
+	assert.Equal(t, int64(1000), s.MaxLen, "5. 最大长度")
+	assert.Equal(t, DefClaimTimeout, s.ClaimTimeout, "6. 默认超时时长")
+}
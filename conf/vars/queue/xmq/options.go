@@ -12,6 +12,13 @@ func WithAddress(address string) Option {
 	}
 }
 
+//WithSignKey 设置消息签名密钥
+func WithSignKey(signKey string) Option {
+	return func(a *XMQ) {
+		a.SignKey = signKey
+	}
+}
+
 //WithRaw 通过json原串初始化
 func WithRaw(raw string) Option {
 	return func(o *XMQ) {
@@ -17,6 +17,31 @@ const (
 
 	XRequestID = "X-Request-Id"
 
+	//XRequestTimeout cron任务处理超时时长(秒)，仅可缩短服务器配置的超时时长，其它服务器忽略该请求头
+	XRequestTimeout = "X-Request-Timeout"
+
+	//XFencingToken cron任务执行租约的防护令牌，令牌随租约转移递增。服务器仅在执行前后检查租约，
+	//执行期间租约转移时需由业务存储比较令牌拒绝过期节点的写入
+	XFencingToken = "X-Fencing-Token"
+
+	//XMessageID 消息编号，由消息发送方生成
+	XMessageID = "X-Message-Id"
+
+	//XMessageCreated 消息的创建时间(RFC3339)
+	XMessageCreated = "X-Message-Created"
+
+	//XMessageAttempts mqc消息当前的处理次数，首次处理为1
+	XMessageAttempts = "X-Message-Attempts"
+
+	//XMessageRetryAt mqc重试消息的到期时间(unix毫秒)，到期前不处理
+	XMessageRetryAt = "X-Message-Retry-At"
+
+	//XOriginQueue 死信消息的原队列名称
+	XOriginQueue = "X-Origin-Queue"
+
+	//XDeadReason 消息转入死信队列的原因
+	XDeadReason = "X-Dead-Reason"
+
 	JSONF  = "application/json; charset=%s"
 	XMLF   = "application/xml; charset=%s"
 	YAMLF  = "text/yaml; charset=%s"
@@ -324,6 +349,15 @@ type ITraceSpan interface {
 
 	//NewSpan 新的时间片
 	NewSpan(opertor string) ITraceSpan
+
+	//SetTag 设置标签
+	SetTag(key string, value interface{})
+
+	//SetError 设置错误信息
+	SetError(err error)
+
+	//GetHeaders 获取需要向下游服务传递的跟踪头
+	GetHeaders() map[string]string
 }
 
 //IEnd 关闭
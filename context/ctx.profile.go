@@ -0,0 +1,152 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//KindMiddleware 中间件处理阶段
+	KindMiddleware = "middleware"
+
+	//KindDB 数据库操作
+	KindDB = "db"
+
+	//KindCache 缓存操作
+	KindCache = "cache"
+
+	//KindRPC RPC请求
+	KindRPC = "rpc"
+
+	//KindHTTP HTTP请求
+	KindHTTP = "http"
+
+	//KindMQ 消息发送
+	KindMQ = "mq"
+)
+
+type profileKey struct{}
+
+//Timing 一次处理的耗时信息，中间件阶段的耗时不包含内层中间件的耗时
+type Timing struct {
+	Kind     string        `json:"kind"`
+	Name     string        `json:"name"`
+	Offset   time.Duration `json:"offset"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+//Profile 请求处理耗时记录，包含各中间件阶段与数据库、缓存、RPC、HTTP等组件调用的耗时
+type Profile struct {
+	start   time.Time
+	lock    sync.Mutex
+	timings []*Timing
+	stack   []*phaseFrame
+}
+
+type phaseFrame struct {
+	timing *Timing
+	child  time.Duration
+}
+
+//NewProfile 构建请求处理耗时记录
+func NewProfile() *Profile {
+	return &Profile{start: time.Now(), timings: make([]*Timing, 0, 32)}
+}
+
+//WithProfile 将请求处理耗时记录保存到context.Context
+func WithProfile(parent context.Context, p *Profile) context.Context {
+	return context.WithValue(parent, profileKey{}, p)
+}
+
+//GetProfile 获取请求处理耗时记录，优先从ctx中获取，未获取到时从当前请求上下文获取
+func GetProfile(ctx ...context.Context) (*Profile, bool) {
+	if len(ctx) > 0 && ctx[0] != nil {
+		if p, ok := ctx[0].Value(profileKey{}).(*Profile); ok {
+			return p, true
+		}
+	}
+	if c, ok := GetContext(); ok && c.Context() != nil {
+		p, ok := c.Context().Value(profileKey{}).(*Profile)
+		return p, ok
+	}
+	return nil, false
+}
+
+//Profiling 开始记录组件调用的耗时，返回结束函数，未启用耗时记录时返回空函数
+func Profiling(kind string, target string, ctx ...context.Context) func(error) {
+	p, ok := GetProfile(ctx...)
+	if !ok {
+		return func(error) {}
+	}
+	return p.Call(kind, target)
+}
+
+//Phase 开始记录中间件阶段的耗时，返回结束函数。阶段按调用层级嵌套，仅记录本阶段自身的耗时
+func (p *Profile) Phase(name string) func() {
+	start := time.Now()
+	p.lock.Lock()
+	t := &Timing{Kind: KindMiddleware, Name: name, Offset: start.Sub(p.start)}
+	p.timings = append(p.timings, t)
+	frame := &phaseFrame{timing: t}
+	p.stack = append(p.stack, frame)
+	p.lock.Unlock()
+	return func() {
+		d := time.Since(start)
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		t.Duration = d - frame.child
+		for i := len(p.stack) - 1; i >= 0; i-- {
+			if p.stack[i] == frame {
+				p.stack = append(p.stack[:i], p.stack[i+1:]...)
+				if i > 0 {
+					p.stack[i-1].child += d
+				}
+				break
+			}
+		}
+	}
+}
+
+//Call 开始记录组件调用的耗时，返回结束函数
+func (p *Profile) Call(kind string, target string) func(error) {
+	start := time.Now()
+	return func(err error) {
+		t := &Timing{Kind: kind, Name: target, Offset: start.Sub(p.start), Duration: time.Since(start)}
+		if err != nil {
+			t.Error = err.Error()
+		}
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		p.timings = append(p.timings, t)
+	}
+}
+
+//Elapsed 请求已处理的时长
+func (p *Profile) Elapsed() time.Duration {
+	return time.Since(p.start)
+}
+
+//Timings 获取已记录的耗时信息
+func (p *Profile) Timings() []*Timing {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	list := make([]*Timing, len(p.timings))
+	copy(list, p.timings)
+	return list
+}
+
+//String 按记录顺序输出耗时明细
+func (p *Profile) String() string {
+	buff := strings.Builder{}
+	for _, t := range p.Timings() {
+		buff.WriteString(fmt.Sprintf("\n  +%-10v %-10s %-40s %v", t.Offset.Round(time.Microsecond), t.Kind, t.Name, t.Duration.Round(time.Microsecond)))
+		if t.Error != "" {
+			buff.WriteString(" err:" + t.Error)
+		}
+	}
+	return buff.String()
+}
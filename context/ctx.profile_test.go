@@ -0,0 +1,36 @@
+package context
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestProfile(t *testing.T) {
+	p := NewProfile()
+	ctx := WithProfile(context.Background(), p)
+
+	//外层中间件调用内层中间件，外层只记录自身耗时
+	outer := p.Phase("Logging")
+	inner := p.Phase("Auth")
+	time.Sleep(20 * time.Millisecond)
+	done := Profiling(KindDB, "db.query", ctx)
+	done(errors.New("timeout"))
+	inner()
+	outer()
+
+	timings := p.Timings()
+	assert.Equal(t, 3, len(timings), "1. 记录数")
+	assert.Equal(t, "Logging", timings[0].Name, "2. 外层中间件")
+	assert.Equal(t, true, timings[0].Duration < 10*time.Millisecond, "2. 外层中间件不包含内层耗时", timings[0].Duration)
+	assert.Equal(t, true, timings[1].Duration >= 20*time.Millisecond, "3. 内层中间件耗时", timings[1].Duration)
+	assert.Equal(t, KindDB, timings[2].Kind, "4. 组件调用")
+	assert.Equal(t, "timeout", timings[2].Error, "4. 组件调用错误")
+
+	_, ok := GetProfile(context.Background())
+	assert.Equal(t, false, ok, "5. 未启用耗时记录")
+	Profiling(KindDB, "db.query", context.Background())(nil)
+}
@@ -0,0 +1,15 @@
+package context
+
+import "context"
+
+//StartSpan 创建并开始子跨度，用于记录调用下游服务、数据库、缓存等，
+//优先使用ctx中的链路跟踪器，未获取到或未启用链路跟踪时返回nil
+func StartSpan(operator string, ctx ...context.Context) ITraceSpan {
+	tracer, ok := GetTracer(ctx...)
+	if !ok || !tracer.Available() {
+		return nil
+	}
+	span := tracer.Root().NewSpan(operator)
+	span.Start()
+	return span
+}
@@ -0,0 +1,111 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/lib4go/logger"
+	"github.com/micro-plat/lib4go/types"
+)
+
+type ctxKey int
+
+const (
+	loggerKey ctxKey = iota
+	tracerKey
+)
+
+//WithValues 将请求编号、日志组件、链路跟踪器保存到context.Context，
+//请求结束后仍可使用，用于在新的goroutine中获取请求信息
+func WithValues(parent context.Context, requestID string, log logger.ILogger, tracer ITracer) context.Context {
+	ctx := context.WithValue(parent, XRequestID, requestID)
+	ctx = context.WithValue(ctx, loggerKey, log)
+	if tracer != nil {
+		ctx = context.WithValue(ctx, tracerKey, tracer)
+	}
+	return ctx
+}
+
+//GetRequestID 获取请求编号，优先从ctx中获取，未获取到时从当前请求上下文获取
+func GetRequestID(ctx ...context.Context) string {
+	if len(ctx) > 0 && ctx[0] != nil {
+		if id := types.GetString(ctx[0].Value(XRequestID)); id != "" {
+			return id
+		}
+	}
+	if c, ok := GetContext(); ok {
+		return c.User().GetRequestID()
+	}
+	return global.RID.GetXRequestID()
+}
+
+//GetLogger 获取请求的日志组件，优先从ctx中获取，未获取到时从当前请求上下文获取，
+//都未获取到时返回全局日志组件
+func GetLogger(ctx ...context.Context) logger.ILogger {
+	if len(ctx) > 0 && ctx[0] != nil {
+		if l, ok := ctx[0].Value(loggerKey).(logger.ILogger); ok {
+			return l
+		}
+	}
+	if c, ok := GetContext(); ok {
+		return c.Log()
+	}
+	return global.Def.Log()
+}
+
+//GetTracer 获取请求的链路跟踪器，优先从ctx中获取，未获取到时从当前请求上下文获取
+func GetTracer(ctx ...context.Context) (ITracer, bool) {
+	if len(ctx) > 0 && ctx[0] != nil {
+		if t, ok := ctx[0].Value(tracerKey).(ITracer); ok {
+			return t, true
+		}
+	}
+	if c, ok := GetContext(); ok && c.Tracer() != nil {
+		return c.Tracer(), true
+	}
+	return nil, false
+}
+
+//Go 在新的goroutine中执行f，f获取的context.Context包含当前请求的编号、日志组件、链路跟踪器，
+//且不会随请求结束而撤销。未指定ctx时使用当前请求上下文
+func Go(f func(context.Context), ctx ...context.Context) {
+	nctx := Detach(getCtx(ctx...))
+	go func() {
+		global.RID.Add(GetRequestID(nctx))
+		defer global.RID.Remove()
+		defer func() {
+			if r := recover(); r != nil {
+				GetLogger(nctx).Errorf("-----[Recovery] panic recovered:\n%s\n%s", r, global.GetStack())
+			}
+		}()
+		f(nctx)
+	}()
+}
+
+//Detach 返回包含ctx中的值，但不随ctx撤销或超时的context.Context
+func Detach(ctx context.Context) context.Context {
+	return detached{parent: ctx}
+}
+
+func getCtx(ctx ...context.Context) context.Context {
+	if len(ctx) > 0 && ctx[0] != nil {
+		return ctx[0]
+	}
+	if c, ok := GetContext(); ok {
+		return c.Context()
+	}
+	return WithValues(context.Background(), global.RID.GetXRequestID(), global.Def.Log(), nil)
+}
+
+//detached 只继承值的context.Context
+type detached struct {
+	parent context.Context
+}
+
+func (detached) Deadline() (deadline time.Time, ok bool) { return }
+func (detached) Done() <-chan struct{}                   { return nil }
+func (detached) Err() error                              { return nil }
+func (d detached) Value(key interface{}) interface{}     { return d.parent.Value(key) }
+func (d detached) String() string                        { return fmt.Sprintf("%v.Detach", d.parent) }
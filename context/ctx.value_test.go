@@ -0,0 +1,42 @@
+package context
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/lib4go/assert"
+	"github.com/micro-plat/lib4go/logger"
+)
+
+func TestGetRequestID(t *testing.T) {
+	log := logger.GetSession("test", "abc123")
+	ctx := WithValues(context.Background(), "abc123", log, nil)
+	assert.Equal(t, "abc123", GetRequestID(ctx), "1. 从ctx获取请求编号")
+	assert.Equal(t, log, GetLogger(ctx), "2. 从ctx获取日志组件")
+	_, ok := GetTracer(ctx)
+	assert.Equal(t, false, ok, "3. ctx中无链路跟踪器")
+	assert.Equal(t, global.RID.GetXRequestID(), GetRequestID(context.Background()), "4. ctx中无请求编号时使用当前goroutine的编号")
+}
+
+func TestDetach(t *testing.T) {
+	parent, cancel := context.WithCancel(WithValues(context.Background(), "abc123", nil, nil))
+	ctx := Detach(parent)
+	cancel()
+	assert.Equal(t, context.Canceled, parent.Err(), "1. 上级已撤销")
+	assert.Equal(t, nil, ctx.Err(), "2. 不随上级撤销")
+	assert.Equal(t, "abc123", GetRequestID(ctx), "3. 继承上级的值")
+}
+
+func TestGo(t *testing.T) {
+	ctx, cancel := context.WithCancel(WithValues(context.Background(), "abc123", nil, nil))
+	ch := make(chan []interface{})
+	Go(func(nctx context.Context) {
+		ch <- []interface{}{GetRequestID(nctx), global.RID.GetXRequestID(), nctx.Err()}
+	}, ctx)
+	cancel()
+	v := <-ch
+	assert.Equal(t, "abc123", v[0], "1. 继承请求编号")
+	assert.Equal(t, "abc123", v[1], "2. 新goroutine绑定请求编号")
+	assert.Equal(t, nil, v[2], "3. 不随请求撤销")
+}
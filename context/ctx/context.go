@@ -8,7 +8,9 @@ import (
 	"github.com/micro-plat/hydra/conf"
 	"github.com/micro-plat/hydra/conf/app"
 	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/lib4go/logger"
+	"github.com/micro-plat/lib4go/types"
 )
 
 var _ context.IContext = &Ctx{}
@@ -48,11 +50,25 @@ func NewCtx(c context.IInnerContext, tp string) *Ctx {
 	}
 	ctx.user = NewUser(c, context.Cache(ctx), ctx.meta)
 	ctx.request = NewRequest(c, ctx.appConf, ctx.meta)
-	ctx.log = logger.GetSession(ctx.appConf.GetServerConf().GetServerName(), ctx.User().GetRequestID())
+	ctx.log = newLogger(logger.GetSession(ctx.appConf.GetServerConf().GetServerName(), ctx.User().GetRequestID()),
+		ctx.request.Path().GetRequestPath(), ctx.user)
 	ctx.response = NewResponse(c, ctx.appConf, ctx.log, ctx.meta)
+	ctx.tracer = newTracer(c, ctx.log, ctx.appConf)
+
+	//请求编号、日志组件、链路跟踪器随context.Context传递，cron任务可通过请求头缩短超时时长
 	timeout := time.Duration(ctx.appConf.GetServerConf().GetMainConf().GetInt("", 30))
-	ctx.ctx, ctx.cancelFunc = r.WithTimeout(r.WithValue(r.Background(), "X-Request-Id", ctx.user.GetRequestID()), time.Second*timeout)
-	ctx.tracer = newTracer(c.GetURL().Path, ctx.log, ctx.appConf)
+	if ts, ok := c.GetHeaders()[context.XRequestTimeout]; ok && tp == global.CRON {
+		if t := time.Duration(types.GetInt(ts[0])); t > 0 && t < timeout {
+			timeout = t
+		}
+	}
+	nctx := context.WithValues(r.Background(), ctx.user.GetRequestID(), ctx.log, ctx.tracer)
+
+	//启用慢请求分析时记录请求处理耗时
+	if p, err := ctx.appConf.GetProfileConf(); err == nil && !p.Disable {
+		nctx = context.WithProfile(nctx, context.NewProfile())
+	}
+	ctx.ctx, ctx.cancelFunc = r.WithTimeout(nctx, time.Second*timeout)
 	return ctx
 }
 
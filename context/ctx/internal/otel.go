@@ -0,0 +1,172 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/micro-plat/hydra/components/pkgs/otel"
+	"github.com/micro-plat/hydra/conf/app"
+	"github.com/micro-plat/hydra/conf/server/apm"
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/lib4go/logger"
+)
+
+//otelTracer 服务器使用的OpenTelemetry跟踪器，配置变化后重建
+type otelTracer struct {
+	conf   *apm.APM
+	tracer *otel.Tracer
+}
+
+var otelTracers = make(map[string]*otelTracer)
+var otelLock sync.Mutex
+
+//GetOTelTracer 创建基于OpenTelemetry的跟踪器，未启用时返回nil。
+//name为根跨度名称，get用于获取上游服务传递的跟踪头
+func GetOTelTracer(c app.IAPPConf, name string, get func(string) string) (*Tracer, error) {
+	conf, err := c.GetAPMConf()
+	if err != nil || conf.Disable || !conf.IsOTel() {
+		return nil, err
+	}
+	tracer, err := getOTelTracer(c, conf)
+	if err != nil {
+		return nil, err
+	}
+	kind := otel.KindServer
+	switch c.GetServerConf().GetServerType() {
+	case global.MQC:
+		kind = otel.KindConsumer
+	case global.CRON:
+		kind = otel.KindInternal
+	}
+	return &Tracer{ITraceSpan: &otelSpan{tracer: tracer, name: name, kind: kind, parent: otel.Extract(get)}}, nil
+}
+
+//CloseOTelTracer 关闭服务器的跟踪器，并上报剩余的跟踪数据
+func CloseOTelTracer(serverType string) {
+	otelLock.Lock()
+	defer otelLock.Unlock()
+	if t, ok := otelTracers[serverType]; ok {
+		t.tracer.Close()
+		delete(otelTracers, serverType)
+	}
+}
+
+func getOTelTracer(c app.IAPPConf, conf *apm.APM) (*otel.Tracer, error) {
+	serverType := c.GetServerConf().GetServerType()
+	otelLock.Lock()
+	defer otelLock.Unlock()
+	if t, ok := otelTracers[serverType]; ok && t.conf == conf {
+		return t.tracer, nil
+	}
+	exporter, err := newExporter(conf)
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := otelTracers[serverType]; ok {
+		go t.tracer.Close()
+	}
+	service := fmt.Sprintf("%s-%s", c.GetServerConf().GetSysName(), serverType)
+	tracer := otel.NewTracer(service, conf.GetRatio(), exporter, logger.New("apm"))
+	otelTracers[serverType] = &otelTracer{conf: conf, tracer: tracer}
+	return tracer, nil
+}
+
+func newExporter(conf *apm.APM) (otel.Exporter, error) {
+	switch conf.Provider {
+	case apm.OTLPGRPC:
+		return otel.NewOTLPGRPC(conf.Address, conf.Headers)
+	case apm.OTLPHTTP:
+		return otel.NewOTLPHTTP(conf.Address, conf.Headers), nil
+	case apm.Zipkin:
+		return otel.NewZipkin(conf.Address, conf.Headers), nil
+	case apm.Stdout:
+		return otel.NewStdout(os.Stdout), nil
+	}
+	return nil, fmt.Errorf("不支持的apm上报方式:%s", conf.Provider)
+}
+
+//otelSpan 基于OpenTelemetry的跟踪处理器，Start后才创建跨度
+type otelSpan struct {
+	tracer *otel.Tracer
+	name   string
+	kind   otel.SpanKind
+	parent *otel.SpanContext
+	span   *otel.Span
+	subs   []*otelSpan
+	lock   sync.Mutex
+	once   sync.Once
+}
+
+//Start 开始跟踪
+func (s *otelSpan) Start() context.IEnd {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.span == nil {
+		s.span = s.tracer.Start(s.name, s.kind, s.parent)
+	}
+	return s
+}
+
+//NewSpan 创建子跨度，用于记录调用下游服务、数据库、缓存等
+func (s *otelSpan) NewSpan(operator string) context.ITraceSpan {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	parent := s.parent
+	if s.span != nil {
+		parent = s.span.SpanContext()
+	}
+	sub := &otelSpan{tracer: s.tracer, name: operator, kind: otel.KindClient, parent: parent}
+	s.subs = append(s.subs, sub)
+	return sub
+}
+
+//Available 是否可用
+func (s *otelSpan) Available() bool {
+	return true
+}
+
+//SetTag 设置标签
+func (s *otelSpan) SetTag(key string, value interface{}) {
+	if span := s.getSpan(); span != nil {
+		span.SetAttribute(key, value)
+	}
+}
+
+//SetError 设置错误信息
+func (s *otelSpan) SetError(err error) {
+	if span := s.getSpan(); span != nil && err != nil {
+		span.SetStatus(otel.StatusError, err.Error())
+	}
+}
+
+//GetHeaders 获取需要向下游服务传递的跟踪头(traceparent,tracestate)
+func (s *otelSpan) GetHeaders() map[string]string {
+	if span := s.getSpan(); span != nil {
+		return span.SpanContext().Headers()
+	}
+	return s.parent.Headers()
+}
+
+//End 结束跟踪，未结束的子跨度同时结束
+func (s *otelSpan) End() {
+	s.once.Do(func() {
+		s.lock.Lock()
+		subs := s.subs
+		span := s.span
+		s.lock.Unlock()
+		for _, sub := range subs {
+			sub.End()
+		}
+		if span != nil {
+			span.End()
+		}
+	})
+}
+
+func (s *otelSpan) getSpan() *otel.Span {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.span
+}
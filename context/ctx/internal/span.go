@@ -2,7 +2,9 @@ package internal
 
 import (
 	r "context"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/SkyAPM/go2sky"
 	"github.com/micro-plat/hydra/context"
@@ -69,3 +71,22 @@ func (s *Span) End() {
 		}
 	})
 }
+
+//SetTag 设置标签
+func (s *Span) SetTag(key string, value interface{}) {
+	if s.span != nil {
+		s.span.Tag(go2sky.Tag(key), fmt.Sprint(value))
+	}
+}
+
+//SetError 设置错误信息
+func (s *Span) SetError(err error) {
+	if s.span != nil && err != nil {
+		s.span.Error(time.Now(), err.Error())
+	}
+}
+
+//GetHeaders 获取需要向下游服务传递的跟踪头
+func (s *Span) GetHeaders() map[string]string {
+	return nil
+}
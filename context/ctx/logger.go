@@ -0,0 +1,113 @@
+package ctx
+
+import (
+	"github.com/micro-plat/hydra/conf/vars/logging"
+	"github.com/micro-plat/lib4go/logger"
+	"github.com/micro-plat/lib4go/types"
+)
+
+//levelLogger 根据日志配置过滤低于服务日志级别的日志，处于调试状态的请求输出所有日志
+type levelLogger struct {
+	logger.ILogger
+	conf    *logging.Logging
+	level   int
+	user    *user
+	isDebug bool
+}
+
+//newLogger 构建请求的日志组件，未启用日志配置时直接使用会话日志
+func newLogger(l logger.ILogger, service string, u *user) logger.ILogger {
+	c := logging.Current()
+	if c.Disable {
+		return l
+	}
+	return &levelLogger{
+		ILogger: l,
+		conf:    c,
+		level:   c.GetLevel(service),
+		user:    u,
+		isDebug: c.IsDebug(u.GetRequestID()),
+	}
+}
+
+func (l *levelLogger) enable(level int) bool {
+	if level >= l.level || l.isDebug {
+		return true
+	}
+	if !l.conf.HasDebug() {
+		return false
+	}
+	return l.conf.IsDebug(l.user.GetRequestID(), getUsers(l.user)...)
+}
+
+//getUsers 获取用户名与jwt中的用户信息
+func getUsers(u *user) []string {
+	users := make([]string, 0, 4)
+	if name := u.GetUserName(); name != "" {
+		users = append(users, name)
+	}
+	if m, ok := u.Auth().Request().(map[string]interface{}); ok {
+		for _, v := range m {
+			if s := types.GetString(v); s != "" {
+				users = append(users, s)
+			}
+		}
+	}
+	return users
+}
+
+//Debug 输出debug日志
+func (l *levelLogger) Debug(content ...interface{}) {
+	if l.enable(logger.ILevel_Debug) {
+		l.ILogger.Debug(content...)
+	}
+}
+
+//Debugf 输出debug日志
+func (l *levelLogger) Debugf(format string, content ...interface{}) {
+	if l.enable(logger.ILevel_Debug) {
+		l.ILogger.Debugf(format, content...)
+	}
+}
+
+//Info 输出info日志
+func (l *levelLogger) Info(content ...interface{}) {
+	if l.enable(logger.ILevel_Info) {
+		l.ILogger.Info(content...)
+	}
+}
+
+//Infof 输出info日志
+func (l *levelLogger) Infof(format string, content ...interface{}) {
+	if l.enable(logger.ILevel_Info) {
+		l.ILogger.Infof(format, content...)
+	}
+}
+
+//Warn 输出warn日志
+func (l *levelLogger) Warn(content ...interface{}) {
+	if l.enable(logger.ILevel_Warn) {
+		l.ILogger.Warn(content...)
+	}
+}
+
+//Warnf 输出warn日志
+func (l *levelLogger) Warnf(format string, content ...interface{}) {
+	if l.enable(logger.ILevel_Warn) {
+		l.ILogger.Warnf(format, content...)
+	}
+}
+
+//Error 输出error日志
+func (l *levelLogger) Error(content ...interface{}) {
+	if l.enable(logger.ILevel_Error) {
+		l.ILogger.Error(content...)
+	}
+}
+
+//Errorf 输出error日志
+func (l *levelLogger) Errorf(format string, content ...interface{}) {
+	if l.enable(logger.ILevel_Error) {
+		l.ILogger.Errorf(format, content...)
+	}
+}
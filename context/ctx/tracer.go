@@ -1,9 +1,13 @@
 package ctx
 
 import (
+	"net/http"
+	"strings"
+
 	"github.com/micro-plat/hydra/conf/app"
 	"github.com/micro-plat/hydra/context"
 	"github.com/micro-plat/hydra/context/ctx/internal"
+	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/lib4go/logger"
 )
 
@@ -12,9 +16,16 @@ type tracer struct {
 	l logger.ILogger
 }
 
-func newTracer(path string, l logger.ILogger, c app.IAPPConf) *tracer {
+func newTracer(c context.IInnerContext, l logger.ILogger, appConf app.IAPPConf) *tracer {
+	t, err := internal.GetOTelTracer(appConf, getSpanName(c, appConf), getHeader(c.GetHeaders()))
+	if err != nil {
+		l.Error("创建链路跟踪器失败:", err)
+	}
+	if t == nil {
+		t = internal.Empty
+	}
 	return &tracer{
-		Tracer: internal.Empty,
+		Tracer: t,
 		l:      l,
 	}
 }
@@ -23,3 +34,36 @@ func newTracer(path string, l logger.ILogger, c app.IAPPConf) *tracer {
 func (t *tracer) Root() context.ITraceSpan {
 	return t.Tracer.Root()
 }
+
+//getSpanName 获取根跨度名称，http请求为[方法] [路由]，其它为服务名
+func getSpanName(c context.IInnerContext, appConf app.IAPPConf) string {
+	name := c.GetRouterPath()
+	if name == "" {
+		name = c.GetService()
+	}
+	switch appConf.GetServerConf().GetServerType() {
+	case global.API, global.Web, global.WS:
+		return c.GetMethod() + " " + name
+	}
+	return name
+}
+
+//getHeader 不区分大小写获取请求头
+func getHeader(h http.Header) func(string) string {
+	return func(key string) string {
+		if v := h.Get(key); v != "" {
+			return v
+		}
+		for k, v := range h {
+			if strings.EqualFold(k, key) && len(v) > 0 {
+				return v[0]
+			}
+		}
+		return ""
+	}
+}
+
+//CloseTracer 关闭服务器的链路跟踪器，并上报剩余的跟踪数据
+func CloseTracer(serverType string) {
+	internal.CloseOTelTracer(serverType)
+}
@@ -17,22 +17,24 @@ type user struct {
 	jwtToken  interface{}
 }
 
-//NewUser 用户信息
+//NewUser 用户信息，gid为当前请求上下文的缓存编号，请求编号优先使用上游传递的X-Request-Id
 func NewUser(ctx context.IInnerContext, gid string, meta conf.IMeta) *user {
-	return &user{
-		ctx:   ctx,
-		gid:   gid,
-		auth:  &Auth{},
-		IMeta: meta,
+	u := &user{
+		ctx:       ctx,
+		gid:       gid,
+		requestID: gid,
+		auth:      &Auth{},
+		IMeta:     meta,
 	}
+	if ids, ok := ctx.GetHeaders()[context.XRequestID]; ok && ids[0] != "" {
+		u.requestID = ids[0]
+	}
+	return u
 }
 
 //GetRequestID 获取请求编号
 func (c *user) GetRequestID() string {
-	if ids, ok := c.ctx.GetHeaders()[context.XRequestID]; ok {
-		global.RID.Add(ids[0])
-	}
-	return global.RID.GetXRequestID()
+	return c.requestID
 }
 
 //GetGID 获取当前处理的goroutine id
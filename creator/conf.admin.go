@@ -0,0 +1,40 @@
+package creator
+
+import (
+	"fmt"
+
+	"github.com/micro-plat/hydra/conf/server/admin"
+	"github.com/micro-plat/hydra/conf/server/auth/basic"
+	"github.com/micro-plat/hydra/conf/server/auth/jwt"
+)
+
+type adminBuilder struct {
+	CustomerBuilder
+}
+
+//newAdmin 构建admin生成器
+func newAdmin(address string, opts ...admin.Option) *adminBuilder {
+	b := &adminBuilder{
+		CustomerBuilder: make(map[string]interface{}),
+	}
+	b.CustomerBuilder[ServerMainNodeName] = admin.New(address, opts...)
+	return b
+}
+
+//Load 加载配置
+func (b *adminBuilder) Load() {
+}
+
+//Jwt jwt认证配置
+func (b *adminBuilder) Jwt(opts ...jwt.Option) *adminBuilder {
+	path := fmt.Sprintf("%s/%s", jwt.ParNodeName, jwt.SubNodeName)
+	b.CustomerBuilder[path] = jwt.NewJWT(opts...)
+	return b
+}
+
+//Basic basic认证配置
+func (b *adminBuilder) Basic(opts ...basic.Option) *adminBuilder {
+	path := fmt.Sprintf("%s/%s", basic.ParNodeName, basic.SubNodeName)
+	b.CustomerBuilder[path] = basic.NewBasic(opts...)
+	return b
+}
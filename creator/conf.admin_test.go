@@ -0,0 +1,31 @@
+package creator
+
+import (
+	"testing"
+
+	"github.com/micro-plat/hydra/conf/server/admin"
+	"github.com/micro-plat/hydra/conf/server/auth/basic"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func Test_newAdmin(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		opts    []admin.Option
+		want    *adminBuilder
+	}{
+		{name: "1. 初始化默认对象", address: ":19999", want: &adminBuilder{CustomerBuilder: map[string]interface{}{"main": admin.New(":19999")}}},
+		{name: "2. 初始化实体对象", address: ":19998", opts: []admin.Option{admin.WithPprof(), admin.WithDisable()},
+			want: &adminBuilder{CustomerBuilder: map[string]interface{}{"main": admin.New(":19998", admin.WithPprof(), admin.WithDisable())}}},
+	}
+	for _, tt := range tests {
+		got := newAdmin(tt.address, tt.opts...)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
+
+func Test_adminBuilder_Basic(t *testing.T) {
+	got := newAdmin(":19999").Basic(basic.WithUP("admin", "123456"))
+	assert.Equal(t, basic.NewBasic(basic.WithUP("admin", "123456")), got.CustomerBuilder["auth/basic"], "basic认证配置")
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/BurntSushi/toml"
+	"github.com/micro-plat/hydra/conf/server/admin"
 	"github.com/micro-plat/hydra/conf/server/api"
 	"github.com/micro-plat/hydra/conf/server/cron"
 	"github.com/micro-plat/hydra/conf/server/mqc"
@@ -61,6 +62,12 @@ type IConf interface {
 	//GetMQC 获取MQC服务器配置
 	GetMQC() *mqcBuilder
 
+	//Admin 构建管理服务器配置
+	Admin(address string, opts ...admin.Option) *adminBuilder
+
+	//GetAdmin 获取管理服务器配置
+	GetAdmin() *adminBuilder
+
 	//Pub 发布服务
 	Pub(platName string, systemName string, clusterName string, registryAddr string, cover bool) error
 
@@ -118,6 +125,8 @@ func (c *conf) Load() error {
 				c.data[global.CRON] = c.GetCRON()
 			case global.MQC:
 				c.data[global.MQC] = c.GetMQC()
+			case global.Admin:
+				c.data[global.Admin] = c.GetAdmin()
 			default:
 				c.data[t] = newCustomerBuilder()
 			}
@@ -222,6 +231,21 @@ func (c *conf) GetMQC() *mqcBuilder {
 	panic("未指定mqc服务器配置")
 }
 
+//Admin 管理服务器配置
+func (c *conf) Admin(address string, opts ...admin.Option) *adminBuilder {
+	b := newAdmin(address, opts...)
+	c.data[global.Admin] = b
+	return b
+}
+
+//GetAdmin 获取当前已配置的管理服务器
+func (c *conf) GetAdmin() *adminBuilder {
+	if b, ok := c.data[global.Admin]; ok {
+		return b.(*adminBuilder)
+	}
+	return c.Admin(admin.DefAddress)
+}
+
 //Vars 平台变量配置
 func (c *conf) Vars() vars {
 	return c.vars
@@ -1,7 +1,16 @@
 package creator
 
 import (
+	"fmt"
+
+	"github.com/micro-plat/hydra/conf/server/acl/fault"
+	"github.com/micro-plat/hydra/conf/server/apm"
+	"github.com/micro-plat/hydra/conf/server/audit"
 	"github.com/micro-plat/hydra/conf/server/cron"
+	"github.com/micro-plat/hydra/conf/server/delay"
+	"github.com/micro-plat/hydra/conf/server/history"
+	"github.com/micro-plat/hydra/conf/server/profile"
+	"github.com/micro-plat/hydra/conf/server/prometheus"
 	"github.com/micro-plat/hydra/conf/server/task"
 	"github.com/micro-plat/hydra/services"
 )
@@ -40,3 +49,46 @@ func (b *cronBuilder) Task(tks ...*task.Task) *cronBuilder {
 	otask.Append(tks...)
 	return b
 }
+
+//Fault 故障注入配置
+func (b *cronBuilder) Fault(opts ...fault.Option) *cronBuilder {
+	path := fmt.Sprintf("%s/%s", fault.ParNodeName, fault.SubNodeName)
+	b.CustomerBuilder[path] = fault.New(opts...)
+	return b
+}
+
+//Prometheus prometheus统计配置，需指定独立的监听地址
+func (b *cronBuilder) Prometheus(address string, opts ...prometheus.Option) *cronBuilder {
+	b.CustomerBuilder[prometheus.TypeNodeName] = prometheus.New(append([]prometheus.Option{prometheus.WithAddress(address)}, opts...)...)
+	return b
+}
+
+//APM 构建APM配置
+func (b *cronBuilder) APM(address string, opts ...apm.Option) *cronBuilder {
+	b.CustomerBuilder[apm.TypeNodeName] = apm.New(address, opts...)
+	return b
+}
+
+//Audit 请求审计配置
+func (b *cronBuilder) Audit(paths []string, opts ...audit.Option) *cronBuilder {
+	b.CustomerBuilder[audit.TypeNodeName] = audit.New(paths, opts...)
+	return b
+}
+
+//Profile 慢请求分析配置
+func (b *cronBuilder) Profile(opts ...profile.Option) *cronBuilder {
+	b.CustomerBuilder[profile.TypeNodeName] = profile.New(opts...)
+	return b
+}
+
+//History 任务执行历史配置，store为registry,file,db
+func (b *cronBuilder) History(store string, opts ...history.Option) *cronBuilder {
+	b.CustomerBuilder[history.TypeNodeName] = history.New(store, opts...)
+	return b
+}
+
+//Delay 执行延迟任务配置，names为/var/delay下的配置名称，未指定时使用默认配置
+func (b *cronBuilder) Delay(names ...string) *cronBuilder {
+	b.CustomerBuilder[delay.TypeNodeName] = delay.New(names...)
+	return b
+}
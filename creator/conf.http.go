@@ -4,17 +4,24 @@ import (
 	"fmt"
 
 	"github.com/micro-plat/hydra/conf/server/acl/blacklist"
+	"github.com/micro-plat/hydra/conf/server/acl/fault"
 	"github.com/micro-plat/hydra/conf/server/acl/limiter"
+	"github.com/micro-plat/hydra/conf/server/acl/mirror"
 	"github.com/micro-plat/hydra/conf/server/acl/proxy"
 	"github.com/micro-plat/hydra/conf/server/acl/whitelist"
 	"github.com/micro-plat/hydra/conf/server/api"
 	"github.com/micro-plat/hydra/conf/server/apm"
+	"github.com/micro-plat/hydra/conf/server/audit"
 	"github.com/micro-plat/hydra/conf/server/auth/apikey"
 	"github.com/micro-plat/hydra/conf/server/auth/basic"
 	"github.com/micro-plat/hydra/conf/server/auth/jwt"
 	"github.com/micro-plat/hydra/conf/server/auth/ras"
+	"github.com/micro-plat/hydra/conf/server/cache"
 	"github.com/micro-plat/hydra/conf/server/header"
+	"github.com/micro-plat/hydra/conf/server/idempotency"
 	"github.com/micro-plat/hydra/conf/server/metric"
+	"github.com/micro-plat/hydra/conf/server/profile"
+	"github.com/micro-plat/hydra/conf/server/prometheus"
 	"github.com/micro-plat/hydra/conf/server/render"
 	"github.com/micro-plat/hydra/conf/server/router"
 	"github.com/micro-plat/hydra/conf/server/static"
@@ -119,6 +126,33 @@ func (b *httpBuilder) Proxy(script string) *httpBuilder {
 	return b
 }
 
+//Gray 声明式灰度配置，按规则将请求转到上游集群
+func (b *httpBuilder) Gray(opts ...proxy.Option) *httpBuilder {
+	path := fmt.Sprintf("%s/%s", proxy.ParNodeName, proxy.SubNodeName)
+	b.CustomerBuilder[path] = proxy.New(opts...)
+	return b
+}
+
+//Mirror 流量复制配置
+func (b *httpBuilder) Mirror(upcluster string, opts ...mirror.Option) *httpBuilder {
+	path := fmt.Sprintf("%s/%s", mirror.ParNodeName, mirror.SubNodeName)
+	b.CustomerBuilder[path] = mirror.New(upcluster, opts...)
+	return b
+}
+
+//Fault 故障注入配置
+func (b *httpBuilder) Fault(opts ...fault.Option) *httpBuilder {
+	path := fmt.Sprintf("%s/%s", fault.ParNodeName, fault.SubNodeName)
+	b.CustomerBuilder[path] = fault.New(opts...)
+	return b
+}
+
+//Prometheus prometheus统计配置
+func (b *httpBuilder) Prometheus(opts ...prometheus.Option) *httpBuilder {
+	b.CustomerBuilder[prometheus.TypeNodeName] = prometheus.New(opts...)
+	return b
+}
+
 //Render 响应渲染配置
 func (b *httpBuilder) Render(script string) *httpBuilder {
 	b.CustomerBuilder[render.TypeNodeName] = script
@@ -126,7 +160,31 @@ func (b *httpBuilder) Render(script string) *httpBuilder {
 }
 
 //APM 构建APM配置
-func (b *httpBuilder) APM(address string) *httpBuilder {
-	b.CustomerBuilder[apm.TypeNodeName] = apm.New(address)
+func (b *httpBuilder) APM(address string, opts ...apm.Option) *httpBuilder {
+	b.CustomerBuilder[apm.TypeNodeName] = apm.New(address, opts...)
+	return b
+}
+
+//Audit 请求审计配置
+func (b *httpBuilder) Audit(paths []string, opts ...audit.Option) *httpBuilder {
+	b.CustomerBuilder[audit.TypeNodeName] = audit.New(paths, opts...)
+	return b
+}
+
+//Profile 慢请求分析配置
+func (b *httpBuilder) Profile(opts ...profile.Option) *httpBuilder {
+	b.CustomerBuilder[profile.TypeNodeName] = profile.New(opts...)
+	return b
+}
+
+//Idempotency 幂等配置
+func (b *httpBuilder) Idempotency(paths []string, opts ...idempotency.Option) *httpBuilder {
+	b.CustomerBuilder[idempotency.TypeNodeName] = idempotency.New(paths, opts...)
+	return b
+}
+
+//Cache 响应缓存配置
+func (b *httpBuilder) Cache(opts ...cache.Option) *httpBuilder {
+	b.CustomerBuilder[cache.TypeNodeName] = cache.New(opts...)
 	return b
 }
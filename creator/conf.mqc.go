@@ -1,7 +1,14 @@
 package creator
 
 import (
+	"fmt"
+
+	"github.com/micro-plat/hydra/conf/server/acl/fault"
+	"github.com/micro-plat/hydra/conf/server/apm"
+	"github.com/micro-plat/hydra/conf/server/audit"
 	"github.com/micro-plat/hydra/conf/server/mqc"
+	"github.com/micro-plat/hydra/conf/server/profile"
+	"github.com/micro-plat/hydra/conf/server/prometheus"
 	"github.com/micro-plat/hydra/conf/server/queue"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/hydra/services"
@@ -41,6 +48,9 @@ func (b *mqcBuilder) Queue(mq ...*queue.Queue) *mqcBuilder {
 		}
 		for _, m := range mq {
 			m.Queue = global.MQConf.GetQueueName(m.Queue)
+			if m.DeadLetter != "" {
+				m.DeadLetter = global.MQConf.GetQueueName(m.DeadLetter)
+			}
 			oqueue.Append(m)
 		}
 	}
@@ -48,3 +58,34 @@ func (b *mqcBuilder) Queue(mq ...*queue.Queue) *mqcBuilder {
 	global.OnReady(f)
 	return b
 }
+
+//Fault 故障注入配置
+func (b *mqcBuilder) Fault(opts ...fault.Option) *mqcBuilder {
+	path := fmt.Sprintf("%s/%s", fault.ParNodeName, fault.SubNodeName)
+	b.CustomerBuilder[path] = fault.New(opts...)
+	return b
+}
+
+//Prometheus prometheus统计配置，需指定独立的监听地址
+func (b *mqcBuilder) Prometheus(address string, opts ...prometheus.Option) *mqcBuilder {
+	b.CustomerBuilder[prometheus.TypeNodeName] = prometheus.New(append([]prometheus.Option{prometheus.WithAddress(address)}, opts...)...)
+	return b
+}
+
+//APM 构建APM配置
+func (b *mqcBuilder) APM(address string, opts ...apm.Option) *mqcBuilder {
+	b.CustomerBuilder[apm.TypeNodeName] = apm.New(address, opts...)
+	return b
+}
+
+//Audit 请求审计配置
+func (b *mqcBuilder) Audit(paths []string, opts ...audit.Option) *mqcBuilder {
+	b.CustomerBuilder[audit.TypeNodeName] = audit.New(paths, opts...)
+	return b
+}
+
+//Profile 慢请求分析配置
+func (b *mqcBuilder) Profile(opts ...profile.Option) *mqcBuilder {
+	b.CustomerBuilder[profile.TypeNodeName] = profile.New(opts...)
+	return b
+}
@@ -1,10 +1,13 @@
 package creator
 
 import (
+	"github.com/micro-plat/hydra/conf/vars/calendar"
+	vardelay "github.com/micro-plat/hydra/conf/vars/delay"
 	"github.com/micro-plat/hydra/conf/vars/redis"
 	"github.com/micro-plat/hydra/conf/vars/rpc"
 
 	"github.com/micro-plat/hydra/conf/vars/http"
+	"github.com/micro-plat/hydra/conf/vars/logging"
 	"github.com/micro-plat/hydra/conf/vars/rlog"
 )
 
@@ -28,6 +31,12 @@ func (v vars) RLog(service string, opts ...rlog.Option) vars {
 	return v
 }
 
+//Logging 添加日志配置，配置变化后立即生效
+func (v vars) Logging(opts ...logging.Option) vars {
+	v.Custom(logging.TypeNodeName, logging.LogName, logging.New(opts...))
+	return v
+}
+
 func (v vars) HTTP(nodeName string, opts ...http.Option) vars {
 	v.Custom(http.HttpTypeNode, nodeName, http.New(opts...))
 	return v
@@ -44,6 +53,18 @@ func (v vars) Redis(nodeName string, address string, opts ...redis.Option) vars
 	return v
 }
 
+//Calendar 添加任务日历配置，exclude为排除的日期，格式为2006-01-02
+func (v vars) Calendar(name string, exclude ...string) vars {
+	v.Custom(calendar.TypeNodeName, name, calendar.New(exclude...))
+	return v
+}
+
+//Delay 添加延迟任务存储配置，proto为redis,db,registry
+func (v vars) Delay(name string, proto string, opts ...vardelay.Option) vars {
+	v.Custom(vardelay.TypeNodeName, name, vardelay.New(proto, opts...))
+	return v
+}
+
 //Custom 自定义配置
 func (v vars) Custom(typ string, nodeName string, i interface{}) vars {
 	if _, ok := v[typ]; !ok {
@@ -4,8 +4,11 @@ import (
 	"testing"
 
 	"github.com/micro-plat/hydra/conf/vars/cache/cacheredis"
+	"github.com/micro-plat/hydra/conf/vars/calendar"
 	"github.com/micro-plat/hydra/conf/vars/db/mysql"
+	vardelay "github.com/micro-plat/hydra/conf/vars/delay"
 	"github.com/micro-plat/hydra/conf/vars/http"
+	"github.com/micro-plat/hydra/conf/vars/logging"
 	"github.com/micro-plat/hydra/conf/vars/queue/queueredis"
 	"github.com/micro-plat/hydra/conf/vars/redis"
 	"github.com/micro-plat/hydra/conf/vars/rlog"
@@ -84,6 +87,23 @@ func Test_vars_RLog(t *testing.T) {
 	}
 }
 
+func Test_vars_Logging(t *testing.T) {
+	tests := []struct {
+		name string
+		v    vars
+		opts []logging.Option
+		want vars
+	}{
+		{name: "1. 节点不存在,设置默认日志配置", v: vars{}, want: vars{logging.TypeNodeName: map[string]interface{}{logging.LogName: logging.New()}}},
+		{name: "2. 节点存在,设置自定义日志配置", v: vars{logging.TypeNodeName: map[string]interface{}{rlog.LogName: "xx"}}, opts: []logging.Option{logging.WithLevel("Warn"), logging.WithRule("Debug", "/order/**")},
+			want: vars{logging.TypeNodeName: map[string]interface{}{rlog.LogName: "xx", logging.LogName: logging.New(logging.WithLevel("Warn"), logging.WithRule("Debug", "/order/**"))}}},
+	}
+	for _, tt := range tests {
+		got := tt.v.Logging(tt.opts...)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
+
 func Test_vars_RPC(t *testing.T) {
 	type args struct {
 		service string
@@ -150,3 +170,35 @@ func Test_vars_Redis(t *testing.T) {
 		assert.Equal(t, tt.want, got, tt.name)
 	}
 }
+
+func Test_vars_Calendar(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       vars
+		exclude []string
+		want    vars
+	}{
+		{name: "1. 设置空日历", v: vars{}, want: vars{calendar.TypeNodeName: map[string]interface{}{"holiday": calendar.New()}}},
+		{name: "2. 设置排除日期的日历", v: vars{}, exclude: []string{"2020-10-01", "2020-10-02"}, want: vars{calendar.TypeNodeName: map[string]interface{}{"holiday": calendar.New("2020-10-01", "2020-10-02")}}},
+	}
+	for _, tt := range tests {
+		got := tt.v.Calendar("holiday", tt.exclude...)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
+
+func Test_vars_Delay(t *testing.T) {
+	tests := []struct {
+		name  string
+		proto string
+		opts  []vardelay.Option
+		want  vars
+	}{
+		{name: "1. 设置注册中心存储", proto: vardelay.ProtoRegistry, want: vars{vardelay.TypeNodeName: map[string]interface{}{"order": vardelay.New(vardelay.ProtoRegistry)}}},
+		{name: "2. 设置redis存储", proto: vardelay.ProtoRedis, opts: []vardelay.Option{vardelay.WithRedis("redis")}, want: vars{vardelay.TypeNodeName: map[string]interface{}{"order": vardelay.New(vardelay.ProtoRedis, vardelay.WithRedis("redis"))}}},
+	}
+	for _, tt := range tests {
+		got := vars{}.Delay("order", tt.proto, tt.opts...)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"github.com/micro-plat/hydra/conf/vars/queue"
 	queuelmq "github.com/micro-plat/hydra/conf/vars/queue/lmq"
 	queuemqtt "github.com/micro-plat/hydra/conf/vars/queue/mqtt"
+	queuenats "github.com/micro-plat/hydra/conf/vars/queue/nats"
 	"github.com/micro-plat/hydra/conf/vars/queue/queueredis"
+	"github.com/micro-plat/hydra/conf/vars/queue/redisstream"
+	queuexmq "github.com/micro-plat/hydra/conf/vars/queue/xmq"
 )
 
 //Varqueue 消息队列配置
@@ -24,6 +27,26 @@ func (c *Varqueue) Redis(nodeName string, address string, opts ...queueredis.Opt
 	return c.Custom(nodeName, queueredis.New(address, opts...))
 }
 
+//RedisStream 添加基于redis stream的消息队列
+func (c *Varqueue) RedisStream(nodeName string, address string, opts ...redisstream.Option) vars {
+	return c.Custom(nodeName, redisstream.New(address, opts...))
+}
+
+//NATS 添加nats消息队列
+func (c *Varqueue) NATS(nodeName string, address string, opts ...queuenats.Option) vars {
+	return c.Custom(nodeName, queuenats.New(address, opts...))
+}
+
+//JetStream 添加基于nats jetstream的持久化消息队列
+func (c *Varqueue) JetStream(nodeName string, address string, opts ...queuenats.Option) vars {
+	return c.Custom(nodeName, queuenats.NewJetStream(address, opts...))
+}
+
+//XMQ 添加xmq消息队列
+func (c *Varqueue) XMQ(nodeName string, address string, opts ...queuexmq.Option) vars {
+	return c.Custom(nodeName, queuexmq.New(address, opts...))
+}
+
 //MQTT 添加MQTT
 func (c *Varqueue) MQTT(nodeName string, address string, opts ...queuemqtt.Option) vars {
 	return c.Custom(nodeName, queuemqtt.New(address, opts...))
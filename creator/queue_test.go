@@ -6,7 +6,10 @@ import (
 	"github.com/micro-plat/hydra/conf/vars/queue"
 	queuelmq "github.com/micro-plat/hydra/conf/vars/queue/lmq"
 	queuemqtt "github.com/micro-plat/hydra/conf/vars/queue/mqtt"
+	queuenats "github.com/micro-plat/hydra/conf/vars/queue/nats"
 	"github.com/micro-plat/hydra/conf/vars/queue/queueredis"
+	"github.com/micro-plat/hydra/conf/vars/queue/redisstream"
+	queuexmq "github.com/micro-plat/hydra/conf/vars/queue/xmq"
 	"github.com/micro-plat/hydra/conf/vars/redis"
 	"github.com/micro-plat/lib4go/assert"
 )
@@ -60,6 +63,28 @@ func TestVarqueue_Redis(t *testing.T) {
 	}
 }
 
+func TestVarqueue_RedisStream(t *testing.T) {
+	got := NewQueue(map[string]map[string]interface{}{}).RedisStream("stream", "192.168.0.1:6379", redisstream.WithGroup("order"))
+	want := vars{queue.TypeNodeName: map[string]interface{}{"stream": redisstream.New("192.168.0.1:6379", redisstream.WithGroup("order"))}}
+	assert.Equal(t, want, got, "1. 初始化redis stream对象")
+}
+
+func TestVarqueue_NATS(t *testing.T) {
+	got := NewQueue(map[string]map[string]interface{}{}).NATS("nats", "nats://192.168.0.1:4222", queuenats.WithGroup("order"))
+	want := vars{queue.TypeNodeName: map[string]interface{}{"nats": queuenats.New("nats://192.168.0.1:4222", queuenats.WithGroup("order"))}}
+	assert.Equal(t, want, got, "1. 初始化nats对象")
+
+	got = NewQueue(map[string]map[string]interface{}{}).JetStream("js", "nats://192.168.0.1:4222", queuenats.WithAck(10, 5))
+	want = vars{queue.TypeNodeName: map[string]interface{}{"js": queuenats.NewJetStream("nats://192.168.0.1:4222", queuenats.WithAck(10, 5))}}
+	assert.Equal(t, want, got, "2. 初始化jetstream对象")
+}
+
+func TestVarqueue_XMQ(t *testing.T) {
+	got := NewQueue(map[string]map[string]interface{}{}).XMQ("xmq", "192.168.0.1:8080", queuexmq.WithSignKey("abc"))
+	want := vars{queue.TypeNodeName: map[string]interface{}{"xmq": queuexmq.New("192.168.0.1:8080", queuexmq.WithSignKey("abc"))}}
+	assert.Equal(t, want, got, "1. 初始化xmq对象")
+}
+
 func TestVarqueue_MQTT(t *testing.T) {
 	type args struct {
 		name string
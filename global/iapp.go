@@ -34,6 +34,9 @@ const CRON = "cron"
 //MQC mqc服务器
 const MQC = "mqc"
 
+//Admin 管理服务器
+const Admin = "admin"
+
 //ServerTypes 支持的所有服务器类型
 var ServerTypes = []string{}
 
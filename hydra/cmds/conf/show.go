@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/micro-plat/hydra/conf"
 	"github.com/micro-plat/hydra/conf/app"
+	"github.com/micro-plat/hydra/conf/server/task"
+	"github.com/micro-plat/hydra/conf/vars/calendar"
+	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/hydra/registry"
 	"github.com/micro-plat/lib4go/types"
 	"github.com/zkfy/log"
@@ -54,6 +58,9 @@ func (s *show) Show() error {
 	if err := s.printVarConf(); err != nil {
 		return err
 	}
+	if err := s.printCronTasks(); err != nil {
+		return err
+	}
 	if extNode != "" {
 		if err := s.printAnyConf(registry.Join(extNode)); err != nil {
 			return err
@@ -124,6 +131,64 @@ func (s *show) printVarConf() error {
 	s.printNodes(s.vars, 0)
 	return nil
 }
+
+//printCronTasks 显示cron任务最近几次的执行时间
+func (s *show) printCronTasks() error {
+	for _, tp := range s.types {
+		if tp != global.CRON {
+			continue
+		}
+		sc, err := app.NewAPPConfBy(s.plat, s.sysName, tp, s.cluster, s.rgst)
+		if err != nil {
+			return err
+		}
+		tasks, err := sc.GetCRONTaskConf()
+		if err != nil {
+			return err
+		}
+		if len(tasks.Tasks) == 0 {
+			return nil
+		}
+		s.print("└─tasks")
+		now := time.Now()
+		for _, t := range tasks.Tasks {
+			s.print(fmt.Sprintf("  └─%s %s %s", t.Service, t.Cron, getNextTimes(sc.GetVarConf(), t, now)))
+		}
+	}
+	return nil
+}
+
+//getNextTimes 获取任务最近几次的执行时间
+func getNextTimes(varConf conf.IVarConf, t *task.Task, now time.Time) string {
+	switch {
+	case t.Disable:
+		return "已禁用"
+	case t.IsImmediately():
+		return "立即执行"
+	}
+	var cal *calendar.Calendar
+	if t.Calendar != "" {
+		c, err := calendar.GetConf(varConf, t.Calendar)
+		if err != nil {
+			return err.Error()
+		}
+		cal = c
+	}
+	schedule, err := t.GetSchedule(cal)
+	if err != nil {
+		return err.Error()
+	}
+	times := schedule.NextN(now, nextTimeCount)
+	list := make([]string, 0, len(times))
+	for _, n := range times {
+		list = append(list, n.Format("2006-01-02 15:04:05 MST"))
+	}
+	return "下次执行:" + strings.Join(list, ",")
+}
+
+//nextTimeCount 显示的最近执行次数
+const nextTimeCount = 3
+
 func (s *show) printAnyConf(root string) error {
 
 	nodes := make(map[string]interface{})
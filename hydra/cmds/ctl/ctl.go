@@ -0,0 +1,119 @@
+package ctl
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/lib4dev/cli/cmds"
+	"github.com/lib4dev/cli/logs"
+	"github.com/micro-plat/hydra/global"
+	"github.com/urfave/cli"
+)
+
+func init() {
+	cmds.RegisterFunc(func() cli.Command {
+		return cli.Command{
+			Name:  "ctl",
+			Usage: "运行控制，暂停、恢复服务器，禁用、启用、立即执行任务，停止、恢复消费队列",
+			Subcommands: []cli.Command{
+				{
+					Name:      "pause",
+					Usage:     "-暂停服务器，如：ctl pause cron",
+					ArgsUsage: "服务器类型",
+					Flags:     getFlags(),
+					Action:    doAction("/admin/ctl/pause", "server"),
+				},
+				{
+					Name:      "resume",
+					Usage:     "-恢复服务器，如：ctl resume cron",
+					ArgsUsage: "服务器类型",
+					Flags:     getFlags(),
+					Action:    doAction("/admin/ctl/resume", "server"),
+				},
+				{
+					Name:  "task",
+					Usage: "-cron任务控制",
+					Subcommands: []cli.Command{
+						{
+							Name:      "disable",
+							Usage:     "-禁用任务，如：ctl task disable /order/query",
+							ArgsUsage: "任务名称或服务名",
+							Flags:     getFlags(),
+							Action:    doAction("/admin/ctl/task/disable", "name"),
+						},
+						{
+							Name:      "enable",
+							Usage:     "-启用任务，如：ctl task enable /order/query",
+							ArgsUsage: "任务名称或服务名",
+							Flags:     getFlags(),
+							Action:    doAction("/admin/ctl/task/enable", "name"),
+						},
+						{
+							Name:      "trigger",
+							Usage:     `-立即执行任务，如：ctl task trigger /order/query --params '{"id":1}'`,
+							ArgsUsage: "任务名称或服务名",
+							Flags:     getFlags(paramsFlag),
+							Action:    doAction("/admin/ctl/task/trigger", "name"),
+						},
+					},
+				},
+				{
+					Name:  "queue",
+					Usage: "-mqc队列控制",
+					Subcommands: []cli.Command{
+						{
+							Name:      "disable",
+							Usage:     "-停止消费队列，如：ctl queue disable order:pay",
+							ArgsUsage: "队列名称",
+							Flags:     getFlags(),
+							Action:    doAction("/admin/ctl/queue/disable", "name"),
+						},
+						{
+							Name:      "enable",
+							Usage:     "-恢复消费队列，如：ctl queue enable order:pay",
+							ArgsUsage: "队列名称",
+							Flags:     getFlags(),
+							Action:    doAction("/admin/ctl/queue/enable", "name"),
+						},
+					},
+				},
+			},
+		}
+	})
+}
+
+//doAction 向集群中的管理服务器发送控制请求
+func doAction(path string, key string) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		//关闭日志显示
+		global.Current().Log().Pause()
+
+		value := c.Args().First()
+		if value == "" {
+			cli.ShowCommandHelp(c, c.Command.Name)
+			return fmt.Errorf("未指定%s", c.Command.ArgsUsage)
+		}
+		form := url.Values{key: []string{value}}
+		if params != "" {
+			form.Set("params", params)
+		}
+
+		nodes, err := getNodes()
+		if err != nil {
+			return err
+		}
+		failed := 0
+		for _, addr := range nodes {
+			if err := request(addr, path, form); err != nil {
+				logs.Log.Errorf("%s %s %v", addr, c.Command.Name, err)
+				failed++
+				continue
+			}
+			logs.Log.Infof("%s %s 成功", addr, c.Command.Name)
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d/%d个节点执行失败", failed, len(nodes))
+		}
+		return nil
+	}
+}
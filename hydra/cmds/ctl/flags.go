@@ -0,0 +1,52 @@
+package ctl
+
+import (
+	"github.com/micro-plat/hydra/conf/server/auth/jwt"
+	"github.com/micro-plat/hydra/hydra/cmds/pkgs"
+	"github.com/urfave/cli"
+)
+
+var node string
+var userName string
+var password string
+var jwtToken string
+var jwtName string
+var params string
+
+//getFlags 获取运行时的参数
+func getFlags(ext ...cli.Flag) []cli.Flag {
+	flags := pkgs.GetRegistryFlags()
+	flags = append(flags, cli.StringFlag{
+		Name:        "node",
+		Destination: &node,
+		Usage:       `-节点地址,格式：ip:port。未指定时发送到集群所有节点`,
+	})
+	flags = append(flags, cli.StringFlag{
+		Name:        "user,u",
+		Destination: &userName,
+		Usage:       `-basic认证用户名`,
+	})
+	flags = append(flags, cli.StringFlag{
+		Name:        "password,P",
+		Destination: &password,
+		Usage:       `-basic认证密码`,
+	})
+	flags = append(flags, cli.StringFlag{
+		Name:        "jwt",
+		Destination: &jwtToken,
+		Usage:       `-jwt认证token`,
+	})
+	flags = append(flags, cli.StringFlag{
+		Name:        "jwt-name",
+		Destination: &jwtName,
+		Value:       jwt.JWTName,
+		Usage:       `-jwt请求头或cookie名称`,
+	})
+	return append(flags, ext...)
+}
+
+var paramsFlag = cli.StringFlag{
+	Name:        "params",
+	Destination: &params,
+	Usage:       `-任务参数，json格式。如：{"id":1}`,
+}
@@ -0,0 +1,98 @@
+package ctl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/hydra/registry"
+	"github.com/micro-plat/lib4go/logger"
+	"github.com/micro-plat/lib4go/types"
+)
+
+//getNodes 从注册中心获取集群中管理服务器的节点地址
+func getNodes() ([]string, error) {
+	platName := types.GetString(global.FlagVal.PlatName, global.Def.PlatName)
+	if platName == "" {
+		return nil, fmt.Errorf("平台名称不能为空")
+	}
+	sysName := types.GetString(global.FlagVal.SysName, global.Def.SysName, global.AppName)
+	clusterName := types.GetString(global.FlagVal.ClusterName, global.Def.ClusterName, "prod")
+	addr := types.GetString(global.FlagVal.RegistryAddr, global.Def.RegistryAddr, "lm://.")
+
+	r, err := registry.GetRegistry(addr, logger.New("hydra"))
+	if err != nil {
+		return nil, fmt.Errorf("注册中心初始化失败 %w", err)
+	}
+	path := registry.Join(platName, sysName, global.Admin, clusterName, "servers")
+	children, _, err := r.GetChildren(path)
+	if err != nil {
+		return nil, fmt.Errorf("获取管理服务器节点失败:%s %w", path, err)
+	}
+	nodes := make([]string, 0, len(children))
+	for _, name := range children {
+		buff, _, err := r.GetValue(registry.Join(path, name))
+		if err != nil {
+			continue
+		}
+		data := make(map[string]interface{})
+		if err := json.Unmarshal(buff, &data); err != nil {
+			continue
+		}
+		addr := types.GetString(data["addr"])
+		if addr == "" || (node != "" && !strings.HasSuffix(addr, "://"+node)) {
+			continue
+		}
+		nodes = append(nodes, addr)
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("未找到管理服务器节点:%s %s", path, node)
+	}
+	return nodes, nil
+}
+
+//request 发送控制请求
+func request(addr string, path string, form url.Values) error {
+	req, err := http.NewRequest(http.MethodPost, addr+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if userName != "" {
+		req.SetBasicAuth(userName, password)
+	}
+	if jwtToken != "" {
+		req.Header.Set(jwtName, jwtToken)
+		req.AddCookie(&http.Cookie{Name: jwtName, Value: jwtToken})
+	}
+	client := &http.Client{Timeout: time.Second * 10}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return errors.New("认证失败")
+	}
+	buff, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(buff, &result); err != nil {
+		return fmt.Errorf("返回结果格式有误:%d %s", resp.StatusCode, buff)
+	}
+	if !result.Success {
+		return errors.New(result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package mqc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/lib4dev/cli/logs"
+	"github.com/micro-plat/hydra/components/queues/mq"
+	"github.com/micro-plat/hydra/conf/vars"
+	varqueue "github.com/micro-plat/hydra/conf/vars/queue"
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/hydra/registry"
+	"github.com/micro-plat/lib4go/logger"
+	"github.com/micro-plat/lib4go/types"
+	"github.com/urfave/cli"
+
+	_ "github.com/micro-plat/hydra/components/queues/mq/mqtt"
+	_ "github.com/micro-plat/hydra/components/queues/mq/nats"
+	_ "github.com/micro-plat/hydra/components/queues/mq/redis"
+	_ "github.com/micro-plat/hydra/components/queues/mq/redisstream"
+	_ "github.com/micro-plat/hydra/components/queues/mq/xmq"
+)
+
+//maxList 查看死信消息时默认显示的消息数
+const maxList = 20
+
+func listNow(c *cli.Context) error {
+	name, p, err := prepare(c)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	total, err := p.Count(name)
+	if err != nil {
+		return err
+	}
+	logs.Log.Infof("%s 共%d条消息", name, total)
+	size := maxList
+	if count > 0 {
+		size = count
+	}
+	messages, err := peek(p, name, 0, int64(size))
+	if err != nil {
+		return err
+	}
+	for i, m := range messages {
+		h := mq.GetHeaders(m)
+		logs.Log.Infof("[%d] 原队列:%s 处理次数:%s 原因:%s", i, h[context.XOriginQueue], h[context.XMessageAttempts], h[context.XDeadReason])
+	}
+	return nil
+}
+
+func inspectNow(c *cli.Context) error {
+	name, p, err := prepare(c)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	index, err := strconv.ParseInt(c.Args().Get(1), 10, 64)
+	if err != nil {
+		cli.ShowCommandHelp(c, c.Command.Name)
+		return fmt.Errorf("消息序号有误:%s", c.Args().Get(1))
+	}
+	messages, err := peek(p, name, index, 1)
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return fmt.Errorf("消息不存在:%s[%d]", name, index)
+	}
+	for k, v := range mq.GetHeaders(messages[0]) {
+		logs.Log.Infof("%s: %s", k, v)
+	}
+	logs.Log.Info(mq.GetBody(messages[0]))
+	return nil
+}
+
+func replayNow(c *cli.Context) error {
+	name, p, err := prepare(c)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	n, err := each(p, name, func(m string) error {
+		h := mq.GetHeaders(m)
+		target := types.GetString(to, h[context.XOriginQueue])
+		if target == "" {
+			return errors.New("未指定目标队列")
+		}
+		return p.Push(target, mq.SetHeaders(m, map[string]string{
+			context.XMessageAttempts: "",
+			context.XMessageRetryAt:  "",
+			context.XOriginQueue:     "",
+			context.XDeadReason:      "",
+		}))
+	})
+	logs.Log.Infof("%s 已重放%d条消息", name, n)
+	return err
+}
+
+func purgeNow(c *cli.Context) error {
+	name, p, err := prepare(c)
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	n, err := each(p, name, func(string) error { return nil })
+	logs.Log.Infof("%s 已清除%d条消息", name, n)
+	return err
+}
+
+//each 依次取出死信消息并处理，处理失败时将消息放回死信队列
+func each(p mq.IMQP, name string, f func(string) error) (int, error) {
+	total, err := p.Count(name)
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 && int64(count) < total {
+		total = int64(count)
+	}
+	for i := 0; i < int(total); i++ {
+		m, err := p.Pop(name)
+		if err == mq.Nil {
+			return i, nil
+		}
+		if err != nil {
+			return i, err
+		}
+		if err := f(m); err != nil {
+			if perr := p.Push(name, m); perr != nil {
+				return i, fmt.Errorf("%v,消息放回死信队列失败:%v %s", err, perr, m)
+			}
+			return i, err
+		}
+	}
+	return int(total), nil
+}
+
+//peek 查看队列中的消息，消息队列需支持查看
+func peek(p mq.IMQP, name string, start int64, n int64) ([]string, error) {
+	b, ok := p.(mq.IMQPBrowser)
+	if !ok {
+		return nil, errors.New("当前消息队列不支持查看消息")
+	}
+	return b.Peek(name, start, n)
+}
+
+//prepare 获取死信队列名称并根据/var/queue配置构建消息生产者
+func prepare(c *cli.Context) (string, mq.IMQP, error) {
+	global.Current().Log().Pause()
+	name := c.Args().First()
+	if name == "" {
+		cli.ShowCommandHelp(c, c.Command.Name)
+		return "", nil, errors.New("未指定死信队列名称")
+	}
+	platName := types.GetString(global.FlagVal.PlatName, global.Def.PlatName)
+	if platName == "" {
+		return "", nil, errors.New("平台名称不能为空")
+	}
+	addr := types.GetString(global.FlagVal.RegistryAddr, global.Def.RegistryAddr, "lm://.")
+	r, err := registry.GetRegistry(addr, logger.New("hydra"))
+	if err != nil {
+		return "", nil, fmt.Errorf("注册中心初始化失败 %w", err)
+	}
+	varConf, err := vars.NewVarConf(platName, r)
+	if err != nil {
+		return "", nil, err
+	}
+	js, err := varConf.GetConf(varqueue.TypeNodeName, confName)
+	if err != nil {
+		return "", nil, fmt.Errorf("获取消息队列配置失败./var/%s/%s %w", varqueue.TypeNodeName, confName, err)
+	}
+	p, err := mq.NewMQP(js.GetString("proto"), string(js.GetRaw()))
+	if err != nil {
+		return "", nil, err
+	}
+	return name, p, nil
+}
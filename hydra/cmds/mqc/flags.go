@@ -0,0 +1,34 @@
+package mqc
+
+import (
+	"github.com/micro-plat/hydra/hydra/cmds/pkgs"
+	"github.com/urfave/cli"
+)
+
+var confName string
+var count int
+var to string
+
+//getFlags 获取运行时的参数
+func getFlags(ext ...cli.Flag) []cli.Flag {
+	flags := pkgs.GetRegistryFlags()
+	flags = append(flags, cli.StringFlag{
+		Name:        "conf",
+		Destination: &confName,
+		Value:       "queue",
+		Usage:       `-消息队列配置名称，/var/queue下的节点名`,
+	})
+	return append(flags, ext...)
+}
+
+var countFlag = cli.IntFlag{
+	Name:        "count,c",
+	Destination: &count,
+	Usage:       `-处理的消息数，未指定时处理全部消息`,
+}
+
+var toFlag = cli.StringFlag{
+	Name:        "to",
+	Destination: &to,
+	Usage:       `-重放的目标队列，未指定时放入消息的原队列`,
+}
@@ -0,0 +1,51 @@
+package mqc
+
+import (
+	"github.com/lib4dev/cli/cmds"
+	"github.com/urfave/cli"
+)
+
+func init() {
+	cmds.RegisterFunc(func() cli.Command {
+		return cli.Command{
+			Name:  "mqc",
+			Usage: "消息队列管理，查看、重放、清除死信消息",
+			Subcommands: []cli.Command{
+				{
+					Name:  "dlq",
+					Usage: "-死信队列管理",
+					Subcommands: []cli.Command{
+						{
+							Name:      "list",
+							Usage:     "-查看死信消息，如：mqc dlq list order:pay:dlq",
+							ArgsUsage: "死信队列名称",
+							Flags:     getFlags(countFlag),
+							Action:    listNow,
+						},
+						{
+							Name:      "inspect",
+							Usage:     "-查看死信消息的头信息及内容，如：mqc dlq inspect order:pay:dlq 0",
+							ArgsUsage: "死信队列名称 消息序号",
+							Flags:     getFlags(),
+							Action:    inspectNow,
+						},
+						{
+							Name:      "replay",
+							Usage:     "-将死信消息重新放入原队列，如：mqc dlq replay order:pay:dlq",
+							ArgsUsage: "死信队列名称",
+							Flags:     getFlags(countFlag, toFlag),
+							Action:    replayNow,
+						},
+						{
+							Name:      "purge",
+							Usage:     "-清除死信消息，如：mqc dlq purge order:pay:dlq",
+							ArgsUsage: "死信队列名称",
+							Flags:     getFlags(countFlag),
+							Action:    purgeNow,
+						},
+					},
+				},
+			},
+		}
+	})
+}
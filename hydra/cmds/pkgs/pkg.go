@@ -87,3 +87,13 @@ var clusterFlag = cli.StringFlag{
 	Destination: &global.FlagVal.ClusterName,
 	Usage:       "-集群名称，默认值为：prod",
 }
+
+//GetRegistryFlags 获取注册中心及集群参数
+func GetRegistryFlags() []cli.Flag {
+	flags := make([]cli.Flag, 0, 4)
+	flags = append(flags, registryFlag)
+	flags = append(flags, platFlag)
+	flags = append(flags, sysNameFlag)
+	flags = append(flags, clusterFlag)
+	return flags
+}
@@ -3,6 +3,7 @@ package pkgs
 import (
 	"github.com/lib4dev/cli/logs"
 
+	"github.com/micro-plat/hydra/conf/vars/logging"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/hydra/hydra/cmds/pkgs/rlog"
 	"github.com/micro-plat/hydra/hydra/servers"
@@ -28,6 +29,12 @@ func (p *ServiceApp) run() (err error) {
 		return err
 	}
 
+	//2.1 加载并监控日志配置，日志级别、采样、脱敏等配置变化后立即生效
+	if err := watchLogging(global.Def.PlatName, global.Def.RegistryAddr); err != nil {
+		logs.Log.Error(err)
+		return err
+	}
+
 	globalData := global.Current()
 
 	//3.创建trace性能跟踪
@@ -52,3 +59,12 @@ func (p *ServiceApp) run() (err error) {
 	}
 	return nil
 }
+
+//watchLogging 加载并监控注册中心中的日志配置
+func watchLogging(platName string, addr string) error {
+	r, err := registry.GetRegistry(addr, global.Def.Log())
+	if err != nil {
+		return err
+	}
+	return logging.Watch(r, platName, global.Def.Log())
+}
@@ -1,6 +1,7 @@
 package pkgs
 
 import (
+	"github.com/micro-plat/hydra/conf/vars/logging"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/hydra/hydra/cmds/pkgs/service"
 	"github.com/micro-plat/hydra/services"
@@ -21,6 +22,9 @@ func (p *ServiceApp) Stop(s service.Service) (err error) {
 	if p.trace != nil {
 		p.trace.Stop()
 	}
+
+	//停止监控日志配置
+	logging.Close()
 	//关闭各服务
 	if err := services.Def.Close(); err != nil {
 		globalLogger.Error("关闭服务失败:", err)
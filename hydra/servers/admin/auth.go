@@ -0,0 +1,57 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/micro-plat/hydra/conf/app"
+	xjwt "github.com/micro-plat/hydra/conf/server/auth/jwt"
+)
+
+//realm basic认证域
+const realm = `Basic realm="Authorization Required"`
+
+//errNoAuth 未配置认证
+var errNoAuth = errors.New("admin服务器未配置auth/basic或auth/jwt认证")
+
+//getAuth 根据basic,jwt配置构建认证函数，通过任一认证即可访问
+func getAuth(cnf app.IAPPConf) (func(r *http.Request) bool, error) {
+	basicConf, err := cnf.GetBasicConf()
+	if err != nil {
+		return nil, err
+	}
+	jwtConf, err := cnf.GetJWTConf()
+	if err != nil {
+		return nil, err
+	}
+	if basicConf.Disable && jwtConf.Disable {
+		return nil, errNoAuth
+	}
+	return func(r *http.Request) bool {
+		if !basicConf.Disable {
+			if _, ok := basicConf.Verify(r.Header.Get("Authorization")); ok {
+				return true
+			}
+		}
+		if !jwtConf.Disable {
+			if _, err := jwtConf.CheckJWT(getToken(r, jwtConf)); err == nil {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+//getToken 从请求头或cookie中获取jwt
+func getToken(r *http.Request, jwt *xjwt.JWTAuth) string {
+	switch strings.ToUpper(jwt.Source) {
+	case xjwt.SourceHeader, xjwt.SourceHeaderShort:
+		return r.Header.Get(jwt.Name)
+	default:
+		if c, err := r.Cookie(jwt.Name); err == nil {
+			return c.Value
+		}
+		return ""
+	}
+}
@@ -0,0 +1,126 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/hydra/hydra/servers"
+)
+
+//controls 运行时控制接口，仅支持POST请求
+var controls = map[string]func(r *http.Request) (interface{}, error){
+	"/admin/ctl/pause":         pauseServer,
+	"/admin/ctl/resume":        resumeServer,
+	"/admin/ctl/task/disable":  taskAction(func(c servers.ITaskController, name string, r *http.Request) error { return c.DisableTask(name) }),
+	"/admin/ctl/task/enable":   taskAction(func(c servers.ITaskController, name string, r *http.Request) error { return c.EnableTask(name) }),
+	"/admin/ctl/task/trigger":  taskAction(triggerTask),
+	"/admin/ctl/queue/disable": queueAction(func(c servers.IQueueController, name string) error { return c.DisableQueue(name) }),
+	"/admin/ctl/queue/enable":  queueAction(func(c servers.IQueueController, name string) error { return c.EnableQueue(name) }),
+}
+
+//pauseServer 暂停服务器，参数server为服务器类型
+func pauseServer(r *http.Request) (interface{}, error) {
+	p, err := getServer(r.FormValue("server"))
+	if err != nil {
+		return nil, err
+	}
+	pauser, ok := p.(servers.IPauser)
+	if !ok {
+		return nil, fmt.Errorf("服务器不支持暂停:%s", r.FormValue("server"))
+	}
+	changed, err := pauser.Pause()
+	return map[string]interface{}{"changed": changed}, err
+}
+
+//resumeServer 恢复服务器，参数server为服务器类型
+func resumeServer(r *http.Request) (interface{}, error) {
+	p, err := getServer(r.FormValue("server"))
+	if err != nil {
+		return nil, err
+	}
+	pauser, ok := p.(servers.IPauser)
+	if !ok {
+		return nil, fmt.Errorf("服务器不支持恢复:%s", r.FormValue("server"))
+	}
+	changed, err := pauser.Resume()
+	return map[string]interface{}{"changed": changed}, err
+}
+
+//taskAction cron任务控制，参数name为任务名称或服务名
+func taskAction(f func(c servers.ITaskController, name string, r *http.Request) error) func(r *http.Request) (interface{}, error) {
+	return func(r *http.Request) (interface{}, error) {
+		s, err := getServer(global.CRON)
+		if err != nil {
+			return nil, err
+		}
+		name := r.FormValue("name")
+		if name == "" {
+			return nil, fmt.Errorf("未指定任务名称")
+		}
+		return nil, f(s.(servers.ITaskController), name, r)
+	}
+}
+
+//triggerTask 立即执行任务，参数params为json格式的任务参数
+func triggerTask(c servers.ITaskController, name string, r *http.Request) error {
+	form := make(map[string]interface{})
+	if params := r.FormValue("params"); params != "" {
+		if err := json.Unmarshal([]byte(params), &form); err != nil {
+			return fmt.Errorf("任务参数格式有误:%w", err)
+		}
+	}
+	return c.TriggerTask(name, form)
+}
+
+//queueAction mqc队列控制，参数name为队列名称
+func queueAction(f func(c servers.IQueueController, name string) error) func(r *http.Request) (interface{}, error) {
+	return func(r *http.Request) (interface{}, error) {
+		s, err := getServer(global.MQC)
+		if err != nil {
+			return nil, err
+		}
+		name := r.FormValue("name")
+		if name == "" {
+			return nil, fmt.Errorf("未指定队列名称")
+		}
+		return nil, f(s.(servers.IQueueController), name)
+	}
+}
+
+func getServer(tp string) (servers.IResponsiveServer, error) {
+	if tp == "" {
+		return nil, fmt.Errorf("未指定服务器类型")
+	}
+	s, ok := servers.GetServer(tp)
+	if !ok {
+		return nil, fmt.Errorf("服务器未运行:%s", tp)
+	}
+	return s, nil
+}
+
+//result 控制请求的处理结果
+type result struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+//execute 执行控制请求并以json格式输出
+func execute(f func(r *http.Request) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		data, err := f(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(&result{Error: err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(&result{Success: true, Data: data})
+	}
+}
@@ -0,0 +1,168 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/micro-plat/hydra/components"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/conf/app"
+	"github.com/micro-plat/hydra/conf/server/router"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/hydra/hydra/servers"
+	"github.com/micro-plat/hydra/services"
+)
+
+//handlers 运行时信息查询接口
+var handlers = map[string]http.HandlerFunc{
+	"/admin/servers":    render(getServers),
+	"/admin/services":   render(getServices),
+	"/admin/cron":       render(inspect(global.CRON)),
+	"/admin/mqc":        render(inspect(global.MQC)),
+	"/admin/components": render(getComponents),
+}
+
+type confItem struct {
+	Path    string          `json:"path"`
+	Version int32           `json:"version"`
+	Content json.RawMessage `json:"content,omitempty"`
+}
+
+type serverItem struct {
+	Type    string      `json:"type"`
+	Path    string      `json:"path"`
+	Version int32       `json:"version"`
+	Started bool        `json:"started"`
+	Running bool        `json:"running"`
+	Confs   []*confItem `json:"confs"`
+	Error   string      `json:"error,omitempty"`
+}
+
+//getServers 获取已加载的服务器配置及版本号
+func getServers() interface{} {
+	list := make([]*serverItem, 0, len(global.Def.GetServerTypes()))
+	for _, tp := range global.Def.GetServerTypes() {
+		item := &serverItem{Type: tp, Confs: make([]*confItem, 0, 4)}
+		_, item.Running = servers.GetServer(tp)
+		list = append(list, item)
+		cnf, err := app.Cache.GetAPPConf(tp)
+		if err != nil {
+			item.Error = err.Error()
+			continue
+		}
+		sconf := cnf.GetServerConf()
+		item.Path = sconf.GetServerPath()
+		item.Version = sconf.GetVersion()
+		item.Started = sconf.IsStarted()
+		item.Confs = append(item.Confs, newConfItem(item.Path, sconf.GetMainConf()))
+		sconf.Iter(func(path string, c *conf.RawConf) bool {
+			item.Confs = append(item.Confs, newConfItem(path, c))
+			return true
+		})
+	}
+	return list
+}
+
+//secretMask 敏感配置的替换值
+const secretMask = "******"
+
+//secretKeys 敏感配置项名称，如:basic认证用户,jwt密钥,apikey密钥,ras密钥参数及统计上报密码
+var secretKeys = []string{"secret", "password", "pwd", "token", "members", "params"}
+
+//newConfItem 构建配置信息，敏感配置项替换为掩码
+func newConfItem(path string, c *conf.RawConf) *confItem {
+	item := &confItem{Path: path, Version: c.GetVersion()}
+	var content interface{}
+	if err := json.Unmarshal(c.GetRaw(), &content); err != nil {
+		return item
+	}
+	item.Content, _ = json.Marshal(redact(content))
+	return item
+}
+
+//redact 将配置中的敏感配置项替换为掩码
+func redact(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, sv := range t {
+			if isSecretKey(k) {
+				t[k] = secretMask
+				continue
+			}
+			t[k] = redact(sv)
+		}
+	case []interface{}:
+		for i, sv := range t {
+			t[i] = redact(sv)
+		}
+	}
+	return v
+}
+
+func isSecretKey(k string) bool {
+	k = strings.ToLower(k)
+	for _, s := range secretKeys {
+		if strings.Contains(k, s) {
+			return true
+		}
+	}
+	return false
+}
+
+type serviceItem struct {
+	Type     string           `json:"type"`
+	Services []string         `json:"services"`
+	Routers  []*router.Router `json:"routers,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+//getServices 获取已注册的服务及路由
+func getServices() interface{} {
+	list := make([]*serviceItem, 0, len(global.Def.GetServerTypes()))
+	for _, tp := range global.Def.GetServerTypes() {
+		item := &serviceItem{Type: tp, Services: services.Def.GetServices(tp)}
+		list = append(list, item)
+		switch tp {
+		case global.API, global.Web, global.WS, global.RPC:
+			routers, err := services.GetRouter(tp).GetRouters()
+			if err != nil {
+				item.Error = err.Error()
+				continue
+			}
+			item.Routers = routers.GetRouters()
+		}
+	}
+	return list
+}
+
+//getComponents 获取已创建的组件
+func getComponents() interface{} {
+	return components.Def.Container().Items()
+}
+
+//inspect 获取指定服务器的运行信息
+func inspect(tp string) func() interface{} {
+	return func() interface{} {
+		s, ok := servers.GetServer(tp)
+		if !ok {
+			return map[string]interface{}{"running": false}
+		}
+		if i, ok := s.(servers.IInspector); ok {
+			return i.Inspect()
+		}
+		return map[string]interface{}{"running": true}
+	}
+}
+
+//render 以json格式输出
+func render(f func() interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(f())
+	}
+}
@@ -0,0 +1,127 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/conf/app"
+	"github.com/micro-plat/hydra/conf/server/admin"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/hydra/hydra/servers"
+	"github.com/micro-plat/hydra/registry/pub"
+	"github.com/micro-plat/lib4go/logger"
+)
+
+//Responsive 响应式服务器
+type Responsive struct {
+	*Server
+	conf     app.IAPPConf
+	comparer conf.IComparer
+	pub      pub.IPublisher
+	log      logger.ILogger
+}
+
+//NewResponsive 创建响应式服务器
+func NewResponsive(cnf app.IAPPConf) (h *Responsive, err error) {
+	h = &Responsive{
+		conf:     cnf,
+		log:      logger.New(cnf.GetServerConf().GetServerName()),
+		pub:      pub.New(cnf.GetServerConf()),
+		comparer: conf.NewComparer(cnf.GetServerConf(), admin.MainConfName, admin.SubConfName...),
+	}
+	app.Cache.Save(cnf)
+	h.Server, err = h.getServer(cnf)
+	return h, err
+}
+
+//Start 启用服务
+func (w *Responsive) Start() (err error) {
+	if !w.conf.GetServerConf().IsStarted() {
+		w.log.Warnf("%s被禁用，未启动", w.conf.GetServerConf().GetServerType())
+		return
+	}
+
+	if err = w.Server.Start(); err != nil {
+		err = fmt.Errorf("%s启动失败 %w", w.conf.GetServerConf().GetServerType(), err)
+		return
+	}
+
+	if err = w.publish(); err != nil {
+		err = fmt.Errorf("%s服务发布失败 %w", w.conf.GetServerConf().GetServerType(), err)
+		w.Shutdown()
+		return err
+	}
+
+	w.log.Infof("启动成功(%s,%s)", w.conf.GetServerConf().GetServerType(), w.Server.GetAddress())
+	return nil
+}
+
+//Notify 服务器配置变更通知
+func (w *Responsive) Notify(c app.IAPPConf) (change bool, err error) {
+	w.comparer.Update(c.GetServerConf())
+	if !w.comparer.IsChanged() {
+		return false, nil
+	}
+	if w.comparer.IsValueChanged() || w.comparer.IsSubConfChanged() {
+		w.log.Info("关键配置发生变化，准备重启服务器")
+		server, err := w.getServer(c)
+		if err != nil {
+			return false, err
+		}
+
+		w.Shutdown()
+		w.conf = c
+		app.Cache.Save(c)
+		if !c.GetServerConf().IsStarted() {
+			w.log.Info("admin服务被禁用，不用重启")
+			return true, nil
+		}
+
+		w.Server = server
+		if err = w.Start(); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	app.Cache.Save(c)
+	w.conf = c
+	return true, nil
+}
+
+//Shutdown 关闭服务器
+func (w *Responsive) Shutdown() {
+	w.log.Infof("关闭[%s]服务...", w.conf.GetServerConf().GetServerType())
+	w.Server.Shutdown()
+	w.pub.Clear()
+}
+
+//publish 将当前服务器的节点信息发布到注册中心
+func (w *Responsive) publish() (err error) {
+	addr := w.Server.GetAddress()
+	serverName := strings.Split(addr, "://")[1]
+	return w.pub.Publish(serverName, addr, w.conf.GetServerConf().GetServerID())
+}
+
+//根据main.conf创建服务嚣
+func (w *Responsive) getServer(cnf app.IAPPConf) (*Server, error) {
+	adminConf, err := admin.GetConf(cnf.GetServerConf())
+	if err != nil {
+		return nil, err
+	}
+	auth, err := getAuth(cnf)
+	if err != nil {
+		return nil, err
+	}
+	return NewServer(adminConf.Address, auth, adminConf.Pprof), nil
+}
+
+func init() {
+	fn := func(c app.IAPPConf) (servers.IResponsiveServer, error) {
+		return NewResponsive(c)
+	}
+	servers.Register(ADMIN, fn)
+}
+
+//ADMIN admin管理服务器
+const ADMIN = global.Admin
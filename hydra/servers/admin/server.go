@@ -0,0 +1,87 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"github.com/micro-plat/hydra/global"
+)
+
+//Server admin管理服务器
+type Server struct {
+	server  *http.Server
+	mux     *http.ServeMux
+	addr    string
+	running bool
+}
+
+//NewServer 创建admin服务器
+func NewServer(address string, auth func(r *http.Request) bool, enablePprof bool) *Server {
+	s := &Server{mux: http.NewServeMux()}
+	s.server = &http.Server{
+		Addr:              address,
+		Handler:           withAuth(s.mux, auth),
+		ReadHeaderTimeout: time.Second * 10,
+	}
+	for path, h := range handlers {
+		s.mux.HandleFunc(path, h)
+	}
+	for path, h := range controls {
+		s.mux.HandleFunc(path, execute(h))
+	}
+	if enablePprof {
+		s.mux.HandleFunc("/debug/pprof/", pprof.Index)
+		s.mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		s.mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		s.mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		s.mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+	return s
+}
+
+//Start 启动admin服务器
+func (s *Server) Start() error {
+	if s.running {
+		return nil
+	}
+	lsr, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("admin监听端口失败:%s %w", s.server.Addr, err)
+	}
+	s.running = true
+	s.addr = fmt.Sprintf("http://%s:%d", global.LocalIP(), lsr.Addr().(*net.TCPAddr).Port)
+	go s.server.Serve(lsr)
+	return nil
+}
+
+//Shutdown 关闭服务器
+func (s *Server) Shutdown() {
+	if !s.running {
+		return
+	}
+	s.running = false
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	s.server.Shutdown(ctx)
+}
+
+//GetAddress 获取当前服务地址
+func (s *Server) GetAddress() string {
+	return s.addr
+}
+
+//withAuth 所有请求须通过认证
+func withAuth(h http.Handler, auth func(r *http.Request) bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !auth(r) {
+			w.Header().Set("WWW-Authenticate", realm)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,101 @@
+package admin
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestServer_Auth(t *testing.T) {
+	token := "Basic " + base64.StdEncoding.EncodeToString([]byte("admin:123456"))
+	auth := func(r *http.Request) bool { return r.Header.Get("Authorization") == token }
+	tests := []struct {
+		name   string
+		path   string
+		method string
+		auth   string
+		pprof  bool
+		want   int
+	}{
+		{name: "1. 未认证", path: "/admin/servers", method: http.MethodGet, want: http.StatusUnauthorized},
+		{name: "2. 认证失败", path: "/admin/servers", method: http.MethodGet, auth: "Basic xxx", want: http.StatusUnauthorized},
+		{name: "3. 认证通过", path: "/admin/servers", method: http.MethodGet, auth: token, want: http.StatusOK},
+		{name: "4. 查询组件", path: "/admin/components", method: http.MethodGet, auth: token, want: http.StatusOK},
+		{name: "5. 查询未启动的cron", path: "/admin/cron", method: http.MethodGet, auth: token, want: http.StatusOK},
+		{name: "6. 不支持的请求方法", path: "/admin/services", method: http.MethodPost, auth: token, want: http.StatusMethodNotAllowed},
+		{name: "7. 未开启pprof", path: "/debug/pprof/", method: http.MethodGet, auth: token, want: http.StatusNotFound},
+		{name: "8. 开启pprof", path: "/debug/pprof/", method: http.MethodGet, auth: token, pprof: true, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		s := NewServer(":0", auth, tt.pprof)
+		r := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.auth != "" {
+			r.Header.Set("Authorization", tt.auth)
+		}
+		w := httptest.NewRecorder()
+		s.server.Handler.ServeHTTP(w, r)
+		assert.Equal(t, tt.want, w.Code, tt.name)
+	}
+}
+
+func TestServer_Start(t *testing.T) {
+	s := NewServer("127.0.0.1:0", func(r *http.Request) bool { return true }, false)
+	err := s.Start()
+	assert.Equal(t, nil, err, "启动服务器")
+	assert.Equal(t, true, s.GetAddress() != "", "获取服务地址")
+	s.Shutdown()
+	assert.Equal(t, false, s.running, "关闭服务器")
+}
+
+func TestServer_Control(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{name: "1. 不支持的请求方法", method: http.MethodGet, path: "/admin/ctl/pause?server=cron", want: http.StatusMethodNotAllowed},
+		{name: "2. 未指定服务器类型", method: http.MethodPost, path: "/admin/ctl/pause", want: http.StatusBadRequest},
+		{name: "3. 服务器未运行", method: http.MethodPost, path: "/admin/ctl/resume?server=cron", want: http.StatusBadRequest},
+		{name: "4. 未运行cron服务器时禁用任务", method: http.MethodPost, path: "/admin/ctl/task/disable?name=/order/query", want: http.StatusBadRequest},
+		{name: "5. 未运行mqc服务器时停止消费队列", method: http.MethodPost, path: "/admin/ctl/queue/disable?name=order:pay", want: http.StatusBadRequest},
+	}
+	s := NewServer(":0", func(r *http.Request) bool { return true }, false)
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		s.server.Handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		assert.Equal(t, tt.want, w.Code, tt.name)
+	}
+}
+
+func TestNewConfItem(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		want   string
+		secret []string
+	}{
+		{name: "1. 无敏感配置", raw: `{"address":":8080"}`, want: `{"address":":8080"}`},
+		{name: "2. basic认证用户", raw: `{"members":{"admin":"pwd-basic"}}`, want: `{"members":"******"}`, secret: []string{"pwd-basic"}},
+		{name: "3. jwt密钥", raw: `{"name":"Authorization-Jwt","secret":"jwt-secret"}`, want: `{"name":"Authorization-Jwt","secret":"******"}`, secret: []string{"jwt-secret"}},
+		{name: "4. ras密钥", raw: `{"auth":[{"service":"/ras","connect":{"secret":{"kv":"k"}},"params":{"key":"ras-key"}}]}`, want: `{"auth":[{"connect":{"secret":"******"},"params":"******","service":"/ras"}]}`, secret: []string{"ras-key"}},
+		{name: "5. 统计上报密码", raw: `{"reporters":[{"password":"influx-pwd","token":"influx-token"}]}`, want: `{"reporters":[{"password":"******","token":"******"}]}`, secret: []string{"influx-pwd", "influx-token"}},
+		{name: "6. 非json配置", raw: `abc`, want: ``},
+	}
+	for _, tt := range tests {
+		c, err := conf.NewByText([]byte(tt.raw), 1)
+		if err != nil {
+			c = conf.EmptyRawConf
+		}
+		item := newConfItem("/t", c)
+		assert.Equal(t, tt.want, string(item.Content), tt.name)
+		for _, s := range tt.secret {
+			assert.Equal(t, false, strings.Contains(string(item.Content), s), tt.name, s)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package servers
+
+//IPauser 可暂停、恢复的服务器
+type IPauser interface {
+	Pause() (bool, error)
+	Resume() (bool, error)
+}
+
+//ITaskController 可按任务禁用、启用、立即执行的服务器
+type ITaskController interface {
+	DisableTask(name string) error
+	EnableTask(name string) error
+	TriggerTask(name string, form map[string]interface{}) error
+}
+
+//IQueueController 可按队列停止、恢复消费的服务器
+type IQueueController interface {
+	DisableQueue(name string) error
+	EnableQueue(name string) error
+}
@@ -70,6 +70,15 @@ func (s *Server) Resume() (bool, error) {
 	return ok, err
 }
 
+//Takeover 启动或切换为主节点时恢复执行，并补偿错过的执行
+func (s *Server) Takeover() (bool, error) {
+	ok, err := s.Processor.Takeover()
+	if ok {
+		s.running = true
+	}
+	return ok, err
+}
+
 //GetAddress 获取当前服务地址
 func (s *Server) GetAddress() string {
 	return fmt.Sprintf("cron://%s", global.LocalIP())
@@ -1,26 +1,28 @@
 package cron
 
 import (
-	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/micro-plat/hydra/conf/server/task"
-	"github.com/robfig/cron"
+	"github.com/micro-plat/hydra/conf/vars/calendar"
+	"github.com/micro-plat/hydra/context"
 )
 
 //CronTask 定时任务
 type CronTask struct {
 	*task.Task
-	Counter  *Counter
-	Round    *Round
-	schedule cron.Schedule
-	method   string
-	form     map[string]interface{}
-	header   map[string]string
+	Counter   *Counter
+	Round     *Round
+	schedule  *task.Schedule
+	scheduled time.Time
+	method    string
+	form      map[string]interface{}
+	header    map[string]string
 }
 
-//NewCronTask 构建定时任务
-func NewCronTask(t *task.Task) (r *CronTask, err error) {
+//NewCronTask 构建定时任务，cal为任务日历，未指定日历时为nil
+func NewCronTask(t *task.Task, cal *calendar.Calendar) (r *CronTask, err error) {
 	r = &CronTask{
 		Task:    t,
 		Counter: &Counter{},
@@ -29,15 +31,21 @@ func NewCronTask(t *task.Task) (r *CronTask, err error) {
 		form:    make(map[string]interface{}),
 		header:  map[string]string{"Client-IP": "127.0.0.1"},
 	}
+	for k, v := range t.Params {
+		r.form[k] = v
+	}
+	for k, v := range t.Headers {
+		r.header[k] = v
+	}
+	if t.Timeout > 0 {
+		r.header[context.XRequestTimeout] = strconv.Itoa(t.Timeout)
+	}
 	if t.IsImmediately() {
 		return r, nil
 	}
 
-	r.schedule, err = cron.ParseStandard(t.Cron)
-	if err != nil {
-		return r, fmt.Errorf("%s的cron表达式(%s)配置有误 %w", t.Service, t.Cron, err)
-	}
-	return r, nil
+	r.schedule, err = t.GetSchedule(cal)
+	return r, err
 }
 
 //GetName 获取任务名称
@@ -50,9 +58,51 @@ func (m *CronTask) NextTime(t time.Time) time.Time {
 	if m.IsImmediately() {
 		return time.Now()
 	}
-	return m.schedule.Next(t)
+	return m.getSchedule().Next(t)
+}
+
+//IsExcluded 计划执行时间是否被当前的任务日历排除，日历在任务加入执行计划后发生变化时不再执行
+func (m *CronTask) IsExcluded(t time.Time) bool {
+	if m.IsImmediately() {
+		return false
+	}
+	return m.getSchedule().IsExcluded(t)
+}
+
+//getSchedule 获取使用最新任务日历的执行计划，日历获取失败时使用原日历
+func (m *CronTask) getSchedule() *task.Schedule {
+	if m.Task.Calendar == "" {
+		return m.schedule
+	}
+	cal, err := loadCalendar(m.Task.Calendar)
+	if err != nil {
+		return m.schedule
+	}
+	return m.schedule.WithCalendar(cal)
 }
 
+//Missed 获取from之后、to之前错过的执行时间，最多返回最近的max个及错过的总次数
+func (m *CronTask) Missed(from time.Time, to time.Time, max int) ([]time.Time, int) {
+	if m.IsImmediately() || max <= 0 {
+		return nil, 0
+	}
+	times := make([]time.Time, 0, max)
+	count := 0
+	schedule := m.getSchedule()
+	for next := schedule.Next(from); !next.IsZero() && next.Before(to) && count < maxMissed; next = schedule.Next(next) {
+		count++
+		if len(times) == max {
+			times = append(times[1:], next)
+			continue
+		}
+		times = append(times, next)
+	}
+	return times, count
+}
+
+//maxMissed 计算错过的执行次数时的最大次数
+const maxMissed = 100000
+
 //GetService 服务名
 func (m *CronTask) GetService() string {
 	return m.Task.Service
@@ -72,3 +122,27 @@ func (m *CronTask) GetForm() map[string]interface{} {
 func (m *CronTask) GetHeader() map[string]string {
 	return m.header
 }
+
+//withHeader 复制任务并设置头信息，用于单次执行
+func (m *CronTask) withHeader(name string, value string) *CronTask {
+	n := *m
+	n.header = make(map[string]string, len(m.header)+1)
+	for k, v := range m.header {
+		n.header[k] = v
+	}
+	n.header[name] = value
+	return &n
+}
+
+//withForm 复制任务并合并输入参数，用于单次执行
+func (m *CronTask) withForm(form map[string]interface{}) *CronTask {
+	n := *m
+	n.form = make(map[string]interface{}, len(m.form)+len(form))
+	for k, v := range m.form {
+		n.form[k] = v
+	}
+	for k, v := range form {
+		n.form[k] = v
+	}
+	return &n
+}
@@ -3,11 +3,13 @@ package cron
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/micro-plat/hydra/conf/server/task"
+	"github.com/micro-plat/hydra/conf/vars/calendar"
+	"github.com/micro-plat/hydra/context"
 	"github.com/micro-plat/lib4go/assert"
 	"github.com/micro-plat/lib4go/security/md5"
-	"github.com/robfig/cron"
 )
 
 func TestNewCronTask(t *testing.T) {
@@ -22,6 +24,7 @@ func TestNewCronTask(t *testing.T) {
 		{name: "3. cron任务对象-初始化一次性任务1", ok: false, args: &task.Task{Cron: task.CronExecuteNow, Service: "/servderi1"}, wantErr: true},
 		{name: "4. cron任务对象-初始化错误的cron表达式", ok: false, args: &task.Task{Cron: "sdsd", Service: "/servderi1"}, wantErr: false},
 		{name: "5. cron任务对象-初始化正确的任务", ok: true, args: &task.Task{Cron: "@every 10s", Service: "/servderi1"}, wantErr: true},
+		{name: "6. cron任务对象-初始化秒级任务", ok: true, args: &task.Task{Cron: "*/5 * * * * *", Service: "/servderi1"}, wantErr: true},
 	}
 	for _, tt := range tests {
 		m := &CronTask{
@@ -33,11 +36,11 @@ func TestNewCronTask(t *testing.T) {
 			header:  map[string]string{"Client-IP": "127.0.0.1"},
 		}
 		if tt.ok {
-			s, err := cron.ParseStandard(tt.args.Cron)
+			s, err := tt.args.GetSchedule(nil)
 			assert.Equalf(t, true, err == nil, tt.name, err)
 			m.schedule = s
 		}
-		gotR, err := NewCronTask(tt.args)
+		gotR, err := NewCronTask(tt.args, nil)
 		assert.Equalf(t, tt.wantErr, err == nil, tt.name, err)
 		assert.Equal(t, m, gotR, tt.name)
 	}
@@ -53,7 +56,7 @@ func TestCronTask_GetName(t *testing.T) {
 		header:  map[string]string{"Client-IP": "192.168.0.101", "Host": "www.baidu.com"},
 	}
 
-	s, err := cron.ParseStandard(m.Task.Cron)
+	s, err := m.Task.GetSchedule(nil)
 	assert.Equalf(t, true, err == nil, "schedule creontask 错误")
 	m.schedule = s
 
@@ -72,3 +75,66 @@ func TestCronTask_GetName(t *testing.T) {
 	got2 := m.GetHeader()
 	assert.Equal(t, map[string]string{"Client-IP": "192.168.0.101", "Host": "www.baidu.com"}, got2, "获取任务的GetForm失败")
 }
+
+func TestCronTask_Missed(t *testing.T) {
+	from := time.Date(2020, 1, 1, 2, 0, 0, 0, time.Local)
+	tests := []struct {
+		name      string
+		cron      string
+		to        time.Time
+		max       int
+		wantLen   int
+		wantCount int
+		wantLast  time.Time
+	}{
+		{name: "1. 未错过执行", cron: "0 * * * *", to: from.Add(time.Minute * 30), max: 10, wantLen: 0, wantCount: 0},
+		{name: "2. 错过一次执行", cron: "0 * * * *", to: from.Add(time.Minute * 90), max: 10, wantLen: 1, wantCount: 1, wantLast: from.Add(time.Hour)},
+		{name: "3. 错过多次执行,返回最近的max个", cron: "0 * * * *", to: from.Add(time.Hour*5 + time.Minute), max: 2, wantLen: 2, wantCount: 5, wantLast: from.Add(time.Hour * 5)},
+		{name: "4. 一次性任务", cron: task.CronExecuteNow, to: from.Add(time.Hour * 5), max: 2, wantLen: 0, wantCount: 0},
+	}
+	for _, tt := range tests {
+		m, err := NewCronTask(task.NewTask(tt.cron, "/order/query"), nil)
+		assert.Equal(t, nil, err, tt.name)
+		times, count := m.Missed(from, tt.to, tt.max)
+		assert.Equal(t, tt.wantLen, len(times), tt.name+",返回的时间数")
+		assert.Equal(t, tt.wantCount, count, tt.name+",错过的次数")
+		if tt.wantLen > 0 {
+			assert.Equal(t, tt.wantLast, times[len(times)-1], tt.name+",最近一次错过的时间")
+		}
+	}
+}
+
+func TestNewCronTask_params(t *testing.T) {
+	tk := task.NewTask("@every 10s", "/order/query", task.WithParams(map[string]interface{}{"id": 1}),
+		task.WithHeader("X-Source", "cron"), task.WithTimeout(5))
+	m, err := NewCronTask(tk, nil)
+	assert.Equal(t, nil, err, "构建任务")
+	assert.Equal(t, map[string]interface{}{"id": 1}, m.GetForm(), "1. 任务参数")
+	assert.Equal(t, map[string]string{"Client-IP": "127.0.0.1", "X-Source": "cron", context.XRequestTimeout: "5"}, m.GetHeader(), "2. 任务头信息及超时时长")
+
+	m.GetForm()["id"] = 2
+	assert.Equal(t, map[string]interface{}{"id": 1}, tk.Params, "3. 修改请求参数不影响任务配置")
+}
+
+func TestCronTask_Calendar(t *testing.T) {
+	now := time.Now()
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	cal := calendar.New()
+	defer func() { loadCalendar = getCalendar }()
+	loadCalendar = func(name string) (*calendar.Calendar, error) {
+		return cal, nil
+	}
+	m, err := NewCronTask(task.NewTask("0 0 * * *", "/order/query", task.WithCalendar("workday")), cal)
+	assert.Equal(t, nil, err, "构建任务")
+	assert.Equal(t, tomorrow, m.NextTime(now), "1. 日历未排除日期")
+	assert.Equal(t, false, m.IsExcluded(tomorrow), "1. 日历未排除日期")
+
+	cal = calendar.New(tomorrow.Format(calendar.DateFormat))
+	assert.Equal(t, tomorrow.AddDate(0, 0, 1), m.NextTime(now), "2. 日历变化后按新的日历计算下次执行时间")
+	assert.Equal(t, true, m.IsExcluded(tomorrow), "2. 已加入执行计划的时间被新的日历排除")
+
+	loadCalendar = func(name string) (*calendar.Calendar, error) {
+		return nil, fmt.Errorf("未配置：/var/calendar/%s", name)
+	}
+	assert.Equal(t, tomorrow, m.NextTime(now), "3. 日历获取失败时使用原日历")
+}
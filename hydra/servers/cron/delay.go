@@ -0,0 +1,67 @@
+package cron
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/micro-plat/hydra/components/delay"
+	"github.com/micro-plat/hydra/conf/server/task"
+	"github.com/micro-plat/hydra/hydra/servers/pkg/middleware"
+)
+
+//pollDelay 获取到期的延迟任务并执行，上次获取的任务未执行完成前不再获取
+func (s *Processor) pollDelay() {
+	defer atomic.StoreInt32(&s.polling, 0)
+	var wg sync.WaitGroup
+	for _, e := range s.delays {
+		jobs, err := e.Claim(s.batch)
+		if err != nil {
+			s.log.Errorf("获取延迟任务失败:%v", err)
+			continue
+		}
+		for _, job := range jobs {
+			t, err := NewCronTask(&task.Task{Cron: task.CronExecuteNow, Service: job.Service, Params: job.Params, Headers: job.Header}, nil)
+			if err != nil {
+				s.fail(e, job, err.Error())
+				continue
+			}
+			if !s.Engine.Find(t.GetService()) {
+				s.Engine.Handle(t.GetMethod(), t.GetService(), middleware.ExecuteHandler(t.Service).DispFunc(CRON))
+			}
+			wg.Add(1)
+			go func(e delay.IExecutor, job *delay.Job, t *CronTask) {
+				defer wg.Done()
+				s.runDelay(e, job, t)
+			}(e, job, t)
+		}
+	}
+	wg.Wait()
+}
+
+//runDelay 执行延迟任务，执行成功后移除任务，失败时按退避策略重试
+func (s *Processor) runDelay(e delay.IExecutor, job *delay.Job, t *CronTask) {
+	w, err := s.Engine.HandleRequest(t)
+	switch {
+	case err != nil:
+		s.fail(e, job, err.Error())
+	case w.Status() >= http.StatusBadRequest:
+		s.fail(e, job, fmt.Sprintf("%d:%s", w.Status(), w.Data()))
+	default:
+		if err := e.Done(job); err != nil {
+			s.log.Errorf("移除延迟任务%s(%s)失败:%v", job.Service, job.ID, err)
+		}
+	}
+}
+
+func (s *Processor) fail(e delay.IExecutor, job *delay.Job, reason string) {
+	retry, err := e.Fail(job, reason)
+	if err != nil {
+		s.log.Errorf("保存延迟任务%s(%s)失败:%v", job.Service, job.ID, err)
+		return
+	}
+	if !retry {
+		s.log.Errorf("延迟任务%s(%s)超过最大重试次数:%s", job.Service, job.ID, reason)
+	}
+}
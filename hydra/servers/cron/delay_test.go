@@ -0,0 +1,49 @@
+package cron
+
+import (
+	"testing"
+
+	"github.com/micro-plat/hydra/components/delay"
+	"github.com/micro-plat/hydra/hydra/servers/pkg/dispatcher"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+type testExecutor struct {
+	jobs []*delay.Job
+	done []string
+	fail []string
+}
+
+func (e *testExecutor) Claim(limit int) ([]*delay.Job, error) {
+	jobs := e.jobs
+	e.jobs = nil
+	return jobs, nil
+}
+
+func (e *testExecutor) Done(job *delay.Job) error {
+	e.done = append(e.done, job.ID)
+	return nil
+}
+
+func (e *testExecutor) Fail(job *delay.Job, reason string) (bool, error) {
+	e.fail = append(e.fail, job.ID)
+	return true, nil
+}
+
+func TestProcessor_pollDelay(t *testing.T) {
+	s := newTestProcessor("/delay/ok")
+	s.Engine.Handle("GET", "/delay/fail", func(c *dispatcher.Context) {
+		c.Writer.WriteHeader(500)
+	})
+	e := &testExecutor{jobs: []*delay.Job{
+		{ID: "1", Service: "/delay/ok", Params: map[string]interface{}{"id": 1}},
+		{ID: "2", Service: "/delay/fail"},
+		{ID: "3", Service: ""},
+	}}
+	s.UseDelay(10, e)
+	s.polling = 1
+	s.pollDelay()
+	assert.Equal(t, []string{"1"}, e.done, "1. 执行成功的任务")
+	assert.Equal(t, 2, len(e.fail), "2. 执行失败或无法构建的任务")
+	assert.Equal(t, int32(0), s.polling, "3. 执行完成后允许再次获取")
+}
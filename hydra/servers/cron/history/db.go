@@ -0,0 +1,72 @@
+package history
+
+import (
+	"fmt"
+
+	"github.com/micro-plat/hydra/components"
+	"github.com/micro-plat/lib4go/types"
+)
+
+//dbStore 数据库存储
+type dbStore struct {
+	db     string
+	insert string
+	last   string
+}
+
+//newDBStore 构建数据库存储
+func newDBStore(db string, table string) IStore {
+	return &dbStore{
+		db: db,
+		insert: fmt.Sprintf(`insert into %s(task,service,scheduled,start_time,end_time,node,status,error,duration,misfire)
+values(@task,@service,@scheduled,@start,@end,@node,@status,@error,@duration,@misfire)`, table),
+		last: fmt.Sprintf(`select task,service,scheduled,start_time,end_time,node,status,error,duration,misfire from %s t
+where t.task=@task and t.scheduled=(select max(scheduled) from %s where task=@task)`, table, table),
+	}
+}
+
+//Save 保存执行记录
+func (s *dbStore) Save(r *Record) error {
+	db, err := components.Def.DB().GetDB(s.db)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = db.Execute(s.insert, map[string]interface{}{
+		"task":      r.Task,
+		"service":   r.Service,
+		"scheduled": r.Scheduled,
+		"start":     r.Start,
+		"end":       r.End,
+		"node":      r.Node,
+		"status":    r.Status,
+		"error":     r.Error,
+		"duration":  r.Duration,
+		"misfire":   types.DecodeInt(r.Misfire, true, 1, 0),
+	})
+	return err
+}
+
+//Last 获取任务最近一次的执行记录
+func (s *dbStore) Last(task string) (*Record, error) {
+	db, err := components.Def.DB().GetDB(s.db)
+	if err != nil {
+		return nil, err
+	}
+	rows, _, _, err := db.Query(s.last, map[string]interface{}{"task": task})
+	if err != nil || rows.IsEmpty() {
+		return nil, err
+	}
+	row := rows.Get(0)
+	return &Record{
+		Task:      row.GetString("task"),
+		Service:   row.GetString("service"),
+		Scheduled: row.GetInt64("scheduled"),
+		Start:     row.GetInt64("start_time"),
+		End:       row.GetInt64("end_time"),
+		Node:      row.GetString("node"),
+		Status:    row.GetInt("status"),
+		Error:     row.GetString("error"),
+		Duration:  row.GetInt64("duration"),
+		Misfire:   row.GetInt("misfire") == 1,
+	}, nil
+}
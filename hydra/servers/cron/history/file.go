@@ -0,0 +1,33 @@
+package history
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+//newFileStore 构建本地文件存储，每个任务保存为一个文件
+func newFileStore(dir string, keep int) (IStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建执行历史目录失败:%s %w", dir, err)
+	}
+	return &listStore{
+		keep: keep,
+		read: func(task string) ([]byte, error) {
+			buff, err := ioutil.ReadFile(filepath.Join(dir, task+".history"))
+			if os.IsNotExist(err) {
+				return nil, nil
+			}
+			return buff, err
+		},
+		write: func(task string, buff []byte) error {
+			path := filepath.Join(dir, task+".history")
+			tmp := path + ".tmp"
+			if err := ioutil.WriteFile(tmp, buff, 0644); err != nil {
+				return err
+			}
+			return os.Rename(tmp, path)
+		},
+	}, nil
+}
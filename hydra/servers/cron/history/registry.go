@@ -0,0 +1,33 @@
+package history
+
+import (
+	"github.com/micro-plat/hydra/registry"
+)
+
+//newRegistryStore 构建注册中心存储，记录保存在/平台/系统/cron/集群/history/任务名称节点
+func newRegistryStore(r registry.IRegistry, root string, cluster string, keep int) IStore {
+	base := registry.Join(root, cluster, "history")
+	return &listStore{
+		keep: keep,
+		read: func(task string) ([]byte, error) {
+			path := registry.Join(base, task)
+			ok, err := r.Exists(path)
+			if err != nil || !ok {
+				return nil, err
+			}
+			buff, _, err := r.GetValue(path)
+			return buff, err
+		},
+		write: func(task string, buff []byte) error {
+			path := registry.Join(base, task)
+			ok, err := r.Exists(path)
+			if err != nil {
+				return err
+			}
+			if ok {
+				return r.Update(path, string(buff))
+			}
+			return r.CreatePersistentNode(path, string(buff))
+		},
+	}
+}
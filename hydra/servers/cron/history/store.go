@@ -0,0 +1,115 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/micro-plat/hydra/conf/app"
+	"github.com/micro-plat/hydra/conf/server/history"
+)
+
+//Record 任务执行记录
+type Record struct {
+	Task      string `json:"task"`
+	Service   string `json:"service"`
+	Scheduled int64  `json:"scheduled"`
+	Start     int64  `json:"start"`
+	End       int64  `json:"end"`
+	Node      string `json:"node"`
+	Status    int    `json:"status"`
+	Error     string `json:"error,omitempty"`
+	Duration  int64  `json:"duration"`
+	Misfire   bool   `json:"misfire,omitempty"`
+}
+
+//GetScheduled 获取计划执行时间
+func (r *Record) GetScheduled() time.Time {
+	return time.Unix(r.Scheduled, 0)
+}
+
+//IStore 执行记录存储
+type IStore interface {
+	//Save 保存执行记录
+	Save(r *Record) error
+
+	//Last 获取任务最近一次的执行记录，无记录时返回nil
+	Last(task string) (*Record, error)
+}
+
+//New 根据cron执行历史配置构建存储，未配置或禁用时返回nil
+func New(cnf app.IAPPConf) (IStore, error) {
+	h, err := cnf.GetCRONHistoryConf()
+	if err != nil {
+		return nil, err
+	}
+	if h.Disable {
+		return nil, nil
+	}
+	switch h.Store {
+	case history.StoreRegistry:
+		server := cnf.GetServerConf()
+		return newRegistryStore(server.GetRegistry(), server.GetServerRoot(), server.GetClusterName(), h.Keep), nil
+	case history.StoreFile:
+		return newFileStore(h.Path, h.Keep)
+	case history.StoreDB:
+		return newDBStore(h.DB, h.Table), nil
+	}
+	return nil, fmt.Errorf("不支持的执行历史存储方式:%s", h.Store)
+}
+
+//listStore 按任务保存最近的执行记录列表
+type listStore struct {
+	keep  int
+	lock  sync.Mutex
+	read  func(task string) ([]byte, error)
+	write func(task string, buff []byte) error
+}
+
+//Save 保存执行记录，按计划执行时间倒序保留最近keep条记录
+func (s *listStore) Save(r *Record) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	list, err := s.load(r.Task)
+	if err != nil {
+		return err
+	}
+	list = append(list, r)
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Scheduled > list[j].Scheduled })
+	if len(list) > s.keep {
+		list = list[:s.keep]
+	}
+	buff, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	return s.write(r.Task, buff)
+}
+
+//Last 获取任务最近一次的执行记录
+func (s *listStore) Last(task string) (*Record, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	list, err := s.load(task)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+func (s *listStore) load(task string) ([]*Record, error) {
+	buff, err := s.read(task)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Record, 0, s.keep+1)
+	if len(buff) == 0 {
+		return list, nil
+	}
+	if err := json.Unmarshal(buff, &list); err != nil {
+		return nil, fmt.Errorf("任务%s的执行记录格式有误:%w", task, err)
+	}
+	return list, nil
+}
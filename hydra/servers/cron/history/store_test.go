@@ -0,0 +1,49 @@
+package history
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/micro-plat/hydra/registry"
+	"github.com/micro-plat/lib4go/assert"
+	"github.com/micro-plat/lib4go/logger"
+
+	_ "github.com/micro-plat/hydra/registry/registry/localmemory"
+)
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	assert.Equal(t, nil, err, "创建临时目录")
+	defer os.RemoveAll(dir)
+	fstore, err := newFileStore(dir, 2)
+	assert.Equal(t, nil, err, "构建文件存储")
+
+	r, err := registry.GetRegistry("lm://.", logger.New("hydra"))
+	assert.Equal(t, nil, err, "构建注册中心")
+	rstore := newRegistryStore(r, registry.Join("/hydra/test/cron", filepath.Base(dir)), "prod", 2)
+
+	stores := map[string]IStore{"file": fstore, "registry": rstore}
+	for name, store := range stores {
+		last, err := store.Last("task1")
+		assert.Equal(t, nil, err, name+",1. 无执行记录")
+		assert.Equal(t, true, last == nil, name+",1. 无执行记录")
+
+		for i := int64(1); i <= 3; i++ {
+			err := store.Save(&Record{Task: "task1", Service: "/order/query", Scheduled: i * 60, Status: 200})
+			assert.Equal(t, nil, err, name+",2. 保存执行记录")
+		}
+		last, err = store.Last("task1")
+		assert.Equal(t, nil, err, name+",3. 获取最近的执行记录")
+		assert.Equal(t, int64(180), last.Scheduled, name+",3. 获取最近的执行记录")
+
+		list, err := store.(*listStore).load("task1")
+		assert.Equal(t, nil, err, name+",4. 仅保留最近的记录")
+		assert.Equal(t, 2, len(list), name+",4. 仅保留最近的记录")
+
+		last, err = store.Last("task2")
+		assert.Equal(t, nil, err, name+",5. 其它任务无执行记录")
+		assert.Equal(t, true, last == nil, name+",5. 其它任务无执行记录")
+	}
+}
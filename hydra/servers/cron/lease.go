@@ -0,0 +1,147 @@
+package cron
+
+import (
+	"hash/fnv"
+	"strconv"
+	"sync"
+
+	"github.com/micro-plat/hydra/registry"
+	"github.com/micro-plat/lib4go/concurrent/cmap"
+)
+
+//leaser 任务执行租约，每个任务只租约给集群中的一个存活节点，
+//租约节点为/平台/系统/cron/集群/lease/任务名称下的序列节点，序号最小的节点持有租约，序号即为防护令牌
+type leaser struct {
+	registry registry.IRegistry
+	root     string
+	nodeID   string
+	nodes    []string
+	leases   cmap.ConcurrentMap
+	lock     sync.RWMutex
+}
+
+func newLeaser(r registry.IRegistry, root string, nodeID string) *leaser {
+	return &leaser{
+		registry: r,
+		root:     root,
+		nodeID:   nodeID,
+		leases:   cmap.New(4),
+	}
+}
+
+//Update 更新集群中存活的节点，释放不再归属当前节点的任务租约
+func (l *leaser) Update(nodes []string) {
+	l.lock.Lock()
+	l.nodes = nodes
+	l.lock.Unlock()
+	for task := range l.leases.Items() {
+		if !l.isOwner(task) {
+			l.release(task)
+		}
+	}
+}
+
+//isOwner 按最高随机权重计算任务归属的节点，节点加入或退出时只迁移该节点上的任务
+func (l *leaser) isOwner(task string) bool {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	owner := ""
+	var max uint32
+	for _, node := range l.nodes {
+		h := fnv.New32a()
+		h.Write([]byte(task + "/" + node))
+		if w := h.Sum32(); owner == "" || w > max || (w == max && node < owner) {
+			owner, max = node, w
+		}
+	}
+	return owner == l.nodeID
+}
+
+//Acquire 获取任务的执行租约，持有租约时返回防护令牌。
+//当前节点不再是任务的归属节点时释放租约，租约节点失效(如会话过期)时重新申请，
+//新的归属节点在原节点释放租约前不能执行，避免任务在集群中重复执行
+func (l *leaser) Acquire(task string) (token int64, ok bool, err error) {
+	if !l.isOwner(task) {
+		l.release(task)
+		return 0, false, nil
+	}
+	base := registry.Join(l.root, task)
+	v, ok := l.leases.Get(task)
+	if !ok {
+		path, err := l.registry.CreateSeqNode(registry.Join(base, "lease_"), l.nodeID)
+		if err != nil {
+			return 0, false, err
+		}
+		l.leases.Set(task, path)
+		v = path
+	}
+	children, _, err := l.registry.GetChildren(base)
+	if err != nil {
+		return 0, false, err
+	}
+	holder, token := lowest(children)
+	name := registry.Split(v.(string))
+	if holder == name[len(name)-1] {
+		return token, true, nil
+	}
+	for _, c := range children {
+		if c == name[len(name)-1] {
+			return 0, false, nil
+		}
+	}
+	l.leases.Remove(task)
+	return 0, false, nil
+}
+
+//Check 检查当前节点是否仍持有任务的租约且防护令牌未变化
+func (l *leaser) Check(task string, token int64) bool {
+	v, ok := l.leases.Get(task)
+	if !ok {
+		return false
+	}
+	children, _, err := l.registry.GetChildren(registry.Join(l.root, task))
+	if err != nil {
+		return false
+	}
+	holder, seq := lowest(children)
+	name := registry.Split(v.(string))
+	return seq == token && holder == name[len(name)-1]
+}
+
+//Close 释放当前节点持有的所有租约
+func (l *leaser) Close() {
+	for task := range l.leases.Items() {
+		l.release(task)
+	}
+}
+
+func (l *leaser) release(task string) {
+	if v, ok := l.leases.Get(task); ok {
+		l.registry.Delete(v.(string))
+		l.leases.Remove(task)
+	}
+}
+
+//lowest 获取序号最小的租约节点及其序号
+func lowest(children []string) (name string, seq int64) {
+	seq = -1
+	for _, c := range children {
+		if n := getSeq(c); n >= 0 && (seq < 0 || n < seq) {
+			name, seq = c, n
+		}
+	}
+	return name, seq
+}
+
+//getSeq 获取序列节点名称末尾的序号
+func getSeq(name string) int64 {
+	i := len(name)
+	for i > 0 && name[i-1] >= '0' && name[i-1] <= '9' {
+		i--
+	}
+	n, err := strconv.ParseInt(name[i:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,119 @@
+package cron
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/micro-plat/hydra/registry"
+	_ "github.com/micro-plat/hydra/registry/registry/localmemory"
+	"github.com/micro-plat/lib4go/assert"
+	"github.com/micro-plat/lib4go/logger"
+)
+
+func TestLeaser_isOwner(t *testing.T) {
+	nodes := []string{"a", "b", "c"}
+	leasers := map[string]*leaser{}
+	for _, n := range nodes {
+		leasers[n] = newLeaser(nil, "/hydra/cron/lease", n)
+		leasers[n].Update(nodes)
+	}
+	owners := map[string]string{}
+	for i := 0; i < 60; i++ {
+		task := fmt.Sprintf("task%d", i)
+		count := 0
+		for _, n := range nodes {
+			if leasers[n].isOwner(task) {
+				owners[task] = n
+				count++
+			}
+		}
+		assert.Equalf(t, 1, count, "1. 每个任务只归属一个节点:%s", task)
+	}
+	spread := map[string]int{}
+	for _, n := range owners {
+		spread[n]++
+	}
+	assert.Equal(t, 3, len(spread), "2. 任务分散到所有节点")
+
+	for _, n := range nodes[:2] {
+		leasers[n].Update(nodes[:2])
+	}
+	for task, owner := range owners {
+		if owner == "c" {
+			assert.Equalf(t, true, leasers["a"].isOwner(task) || leasers["b"].isOwner(task), "3. 退出节点的任务迁移到存活节点:%s", task)
+			continue
+		}
+		assert.Equalf(t, true, leasers[owner].isOwner(task), "4. 存活节点的任务不迁移:%s", task)
+	}
+}
+
+func TestLeaser_Acquire(t *testing.T) {
+	r, err := registry.GetRegistry("lm://.", logger.New("hydra"))
+	assert.Equal(t, nil, err, "获取注册中心")
+	root := "/hydra/cron/lease_test"
+	la := newLeaser(r, root, "a")
+	lb := newLeaser(r, root, "b")
+	la.Update([]string{"a"})
+	lb.Update([]string{"a"})
+
+	token1, ok, err := la.Acquire("task1")
+	assert.Equal(t, nil, err, "1. 归属节点获取租约")
+	assert.Equal(t, true, ok, "1. 归属节点获取租约")
+	_, ok, _ = lb.Acquire("task1")
+	assert.Equal(t, false, ok, "2. 非归属节点不能获取租约")
+
+	lb.Update([]string{"b"})
+	_, ok, _ = lb.Acquire("task1")
+	assert.Equal(t, false, ok, "3. 原节点未释放租约前新的归属节点不能执行")
+
+	_, ok, _ = la.Acquire("task1")
+	assert.Equal(t, true, ok, "4. 原节点未感知节点变化时继续持有租约")
+
+	la.Update([]string{"b"})
+	_, ok, _ = la.Acquire("task1")
+	assert.Equal(t, false, ok, "5. 原节点不再归属时释放租约")
+
+	token2, ok, _ := lb.Acquire("task1")
+	assert.Equal(t, true, ok, "6. 原节点释放后新的归属节点获取租约")
+	assert.Equal(t, true, token2 > token1, "7. 防护令牌随租约转移递增")
+
+	children, _, _ := r.GetChildren(registry.Join(root, "task1"))
+	for _, c := range children {
+		r.Delete(registry.Join(root, "task1", c))
+	}
+	_, ok, _ = lb.Acquire("task1")
+	assert.Equal(t, false, ok, "8. 租约节点失效后不能执行")
+	token3, ok, _ := lb.Acquire("task1")
+	assert.Equal(t, true, ok, "9. 重新申请租约")
+	assert.Equal(t, true, token3 > token2, "10. 重新申请的防护令牌递增")
+
+	lb.Close()
+	children, _, _ = r.GetChildren(registry.Join(root, "task1"))
+	assert.Equal(t, 0, len(children), "11. 关闭时释放租约")
+}
+
+func TestLeaser_Check(t *testing.T) {
+	r, err := registry.GetRegistry("lm://.", logger.New("hydra"))
+	assert.Equal(t, nil, err, "获取注册中心")
+	root := "/hydra/cron/lease_check_test"
+	la := newLeaser(r, root, "a")
+	lb := newLeaser(r, root, "b")
+	la.Update([]string{"a"})
+	lb.Update([]string{"a"})
+
+	token, ok, _ := la.Acquire("task1")
+	assert.Equal(t, true, ok, "获取租约")
+	assert.Equal(t, true, la.Check("task1", token), "1. 持有租约")
+	assert.Equal(t, false, la.Check("task1", token+1), "2. 令牌不一致")
+	assert.Equal(t, false, lb.Check("task1", token), "3. 未申请租约的节点")
+
+	la.Update([]string{"b"})
+	lb.Update([]string{"b"})
+	assert.Equal(t, false, la.Check("task1", token), "4. 租约已释放")
+
+	token2, ok, _ := lb.Acquire("task1")
+	assert.Equal(t, true, ok, "新的归属节点获取租约")
+	assert.Equal(t, false, la.Check("task1", token), "5. 租约已转移到其它节点")
+	assert.Equal(t, true, lb.Check("task1", token2), "6. 新的节点持有租约")
+	lb.Close()
+}
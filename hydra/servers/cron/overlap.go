@@ -0,0 +1,40 @@
+package cron
+
+import (
+	"sync"
+
+	"github.com/micro-plat/hydra/conf/server/task"
+)
+
+//overlap 任务在当前节点的执行状态，用于处理上次执行未结束时的重叠执行
+type overlap struct {
+	lock    sync.Mutex
+	running bool
+	queued  bool
+}
+
+//acquire 获取执行权，上次执行未结束时返回false，queue策略下记录一次排队执行
+func (o *overlap) acquire(policy string) bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if !o.running {
+		o.running = true
+		return true
+	}
+	if policy == task.OverlapQueue {
+		o.queued = true
+	}
+	return false
+}
+
+//release 释放执行权，有排队的执行时返回true并继续持有执行权
+func (o *overlap) release() bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if o.queued {
+		o.queued = false
+		return true
+	}
+	o.running = false
+	return false
+}
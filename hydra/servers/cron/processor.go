@@ -3,20 +3,35 @@ package cron
 import (
 	"errors"
 	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/micro-plat/hydra/components/delay"
+	"github.com/micro-plat/hydra/components/dlock"
+	"github.com/micro-plat/hydra/conf/app"
 	"github.com/micro-plat/hydra/conf/server/task"
+	"github.com/micro-plat/hydra/conf/vars/calendar"
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/hydra/hydra/servers"
+	"github.com/micro-plat/hydra/hydra/servers/cron/history"
 	"github.com/micro-plat/hydra/hydra/servers/pkg/dispatcher"
 	"github.com/micro-plat/hydra/hydra/servers/pkg/middleware"
+	"github.com/micro-plat/hydra/registry"
 	"github.com/micro-plat/lib4go/concurrent/cmap"
+	"github.com/micro-plat/lib4go/logger"
 	"github.com/micro-plat/lib4go/utility"
 )
 
 const (
-	unstarted = 1
-	pause     = 2
-	running   = 4
+	unstarted = servers.StatusUnstarted
+	pause     = servers.StatusPause
+	running   = servers.StatusRunning
 )
 
 //Processor cron管理程序，用于管理多个任务的执行，暂停，恢复，动态添加，移除
@@ -31,7 +46,16 @@ type Processor struct {
 	slots     []cmap.ConcurrentMap //time slots
 	startTime time.Time
 	metric    *middleware.Metric
-	status    int
+	status    int32
+	disabled  cmap.ConcurrentMap
+	history   history.IStore
+	registry  registry.IRegistry
+	leaser    *leaser
+	overlaps  cmap.ConcurrentMap
+	delays    []delay.IExecutor
+	batch     int
+	polling   int32
+	log       logger.ILogger
 }
 
 //NewProcessor 创建processor
@@ -43,12 +67,20 @@ func NewProcessor() (p *Processor) {
 		length:    60,
 		startTime: time.Now(),
 		metric:    middleware.NewMetric(),
+		disabled:  cmap.New(2),
+		overlaps:  cmap.New(4),
+		log:       logger.New("cron"),
 	}
 	p.Engine = dispatcher.New()
 	p.Engine.Use(middleware.Recovery().DispFunc(CRON))
 	p.Engine.Use(middleware.Logging().DispFunc())
 	p.Engine.Use(middleware.Recovery().DispFunc())
-	p.Engine.Use(middleware.Trace().DispFunc()) //跟踪信息
+	p.Engine.Use(middleware.Prometheus().DispFunc()) //prometheus统计
+	p.Engine.Use(middleware.Profile().DispFunc())    //慢请求分析
+	p.Engine.Use(middleware.APM().DispFunc())        //链路跟踪
+	p.Engine.Use(middleware.Audit().DispFunc())      //请求审计
+	p.Engine.Use(middleware.Trace().DispFunc())      //跟踪信息
+	p.Engine.Use(middleware.Fault().DispFunc())      //故障注入
 	p.Engine.Use(p.metric.Handle().DispFunc())
 	p.Engine.Use(middlewares.DispFunc()...)
 
@@ -80,7 +112,11 @@ func (s *Processor) Add(ts ...*task.Task) (err error) {
 			s.Remove(t.GetUNQ())
 			continue
 		}
-		task, err := NewCronTask(t)
+		cal, err := loadCalendar(t.Calendar)
+		if err != nil {
+			return fmt.Errorf("构建cron.task失败:%v", err)
+		}
+		task, err := NewCronTask(t, cal)
 		if err != nil {
 			return fmt.Errorf("构建cron.task失败:%v", err)
 		}
@@ -96,6 +132,21 @@ func (s *Processor) Add(ts ...*task.Task) (err error) {
 
 }
 
+//loadCalendar 获取任务日历，添加任务及计算下次执行时间时调用，日历配置变化后按新的日历执行
+var loadCalendar = getCalendar
+
+//getCalendar 从变量配置中获取任务日历，未指定日历时返回nil
+func getCalendar(name string) (*calendar.Calendar, error) {
+	if name == "" {
+		return nil, nil
+	}
+	varConf, err := app.Cache.GetVarConf()
+	if err != nil {
+		return nil, err
+	}
+	return calendar.GetConf(varConf, name)
+}
+
 func (s *Processor) add(task *CronTask) (offset int, round int, err error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -112,6 +163,7 @@ func (s *Processor) add(task *CronTask) (offset int, round int, err error) {
 		return -1, -1, errors.New("next time less than now.2")
 	}
 	task.Round.Update(round)
+	task.scheduled = nextTime
 	s.slots[offset].Set(utility.GetGUID(), task)
 	return
 }
@@ -123,30 +175,72 @@ func (s *Processor) Remove(name string) {
 	for _, slot := range s.slots {
 		slot.RemoveIterCb(func(k string, value interface{}) bool {
 			task := value.(*CronTask)
+			if task.GetName() != name {
+				return false
+			}
 			task.Disable = true
-			return task.GetName() == name
+			return true
 		})
 	}
 }
 
 //Pause 暂停所有任务
 func (s *Processor) Pause() (bool, error) {
-	if s.status != pause {
-		s.status = pause
-		return true, nil
-	}
-	return false, nil
+	return atomic.SwapInt32(&s.status, pause) != pause, nil
 }
 
-//Resume 恢复所有任务
+//Resume 恢复所有任务，不补偿暂停期间错过的执行
 func (s *Processor) Resume() (bool, error) {
-	if s.status != running {
-		s.status = running
+	return atomic.SwapInt32(&s.status, running) != running, nil
+}
+
+//Takeover 服务器启动或切换为主节点时恢复所有任务，并按任务的misfire策略补偿错过的执行
+func (s *Processor) Takeover() (bool, error) {
+	if atomic.SwapInt32(&s.status, running) != running {
+		go s.misfire()
 		return true, nil
 	}
 	return false, nil
 }
 
+//isRunning 是否正在运行
+func (s *Processor) isRunning() bool {
+	return atomic.LoadInt32(&s.status) == running
+}
+
+//UseRegistry 设置注册中心，用于任务在集群内执行overlap策略
+func (s *Processor) UseRegistry(r registry.IRegistry) {
+	s.registry = r
+}
+
+//UseLease 启用任务租约模式，root为租约节点的根路径，nodeID为当前节点编号
+func (s *Processor) UseLease(r registry.IRegistry, root string, nodeID string) {
+	s.leaser = newLeaser(r, root, nodeID)
+}
+
+//UpdateNodes 更新集群中存活的节点编号，租约模式下按节点重新分配任务
+func (s *Processor) UpdateNodes(nodes ...string) {
+	if s.leaser != nil {
+		s.leaser.Update(nodes)
+	}
+}
+
+//UseDisabled 设置被禁用的任务列表，服务器因配置变化重启后保持任务的禁用状态
+func (s *Processor) UseDisabled(disabled cmap.ConcurrentMap) {
+	s.disabled = disabled
+}
+
+//UseHistory 设置执行记录存储，未设置时不记录执行历史，不补偿错过的执行
+func (s *Processor) UseHistory(store history.IStore) {
+	s.history = store
+}
+
+//UseDelay 设置延迟任务执行器，服务器运行时每个周期获取到期的延迟任务并执行，batch为每次获取的最大任务数
+func (s *Processor) UseDelay(batch int, executors ...delay.IExecutor) {
+	s.batch = batch
+	s.delays = executors
+}
+
 //Close 退出
 func (s *Processor) Close() {
 	defer s.metric.Stop()
@@ -155,11 +249,16 @@ func (s *Processor) Close() {
 	if !s.done {
 		s.done = true
 		close(s.closeChan)
+		if s.leaser != nil {
+			s.leaser.Close()
+		}
 	}
 }
 
 //TaskCount 获取当前启用的Task数量
 func (s *Processor) TaskCount() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	count := 0
 	for i := range s.slots {
 		for item := range s.slots[i].IterBuffered() {
@@ -171,8 +270,104 @@ func (s *Processor) TaskCount() int {
 	return count
 }
 
+//DisableTask 禁用任务，任务仍按计划调度但不执行，name为任务名称或服务名
+func (s *Processor) DisableTask(name string) error {
+	tasks := s.find(name)
+	if len(tasks) == 0 {
+		return fmt.Errorf("任务不存在:%s", name)
+	}
+	for _, task := range tasks {
+		s.disabled.Set(task.GetName(), true)
+	}
+	return nil
+}
+
+//EnableTask 启用被禁用的任务，name为任务名称或服务名
+func (s *Processor) EnableTask(name string) error {
+	tasks := s.find(name)
+	if len(tasks) == 0 {
+		return fmt.Errorf("任务不存在:%s", name)
+	}
+	for _, task := range tasks {
+		s.disabled.Remove(task.GetName())
+	}
+	return nil
+}
+
+//TriggerTask 使用指定参数立即执行一次任务，不影响任务的调度计划，执行时同样遵循任务的overlap策略及租约
+func (s *Processor) TriggerTask(name string, form map[string]interface{}) error {
+	if s.done {
+		return errors.New("服务器已关闭")
+	}
+	tasks := s.find(name)
+	if len(tasks) == 0 {
+		return fmt.Errorf("任务不存在:%s", name)
+	}
+	go s.exec(tasks[0].withForm(form), time.Now(), false)
+	return nil
+}
+
+//TaskInfo 任务运行信息
+type TaskInfo struct {
+	Name     string    `json:"name"`
+	Service  string    `json:"service"`
+	Cron     string    `json:"cron"`
+	Next     time.Time `json:"next"`
+	Executed int       `json:"executed"`
+	Disabled bool      `json:"disabled"`
+	Owned    bool      `json:"owned,omitempty"`
+}
+
+//Tasks 获取当前启用的任务及下次执行时间、执行次数
+func (s *Processor) Tasks() []*TaskInfo {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	now := time.Now()
+	list := make([]*TaskInfo, 0, 8)
+	for i := range s.slots {
+		for item := range s.slots[i].IterBuffered() {
+			task := item.Val.(*CronTask)
+			if task.Disable {
+				continue
+			}
+			list = append(list, &TaskInfo{
+				Name:     task.GetName(),
+				Service:  task.GetService(),
+				Cron:     task.Cron,
+				Next:     task.NextTime(now),
+				Executed: task.Counter.Get(),
+				Disabled: s.disabled.Has(task.GetName()),
+				Owned:    s.leaser != nil && s.leaser.isOwner(task.GetName()),
+			})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Next.Before(list[j].Next) })
+	return list
+}
+
+//Status 获取运行状态
+func (s *Processor) Status() string {
+	return servers.StatusName(atomic.LoadInt32(&s.status))
+}
+
 //-------------------------------------内部处理------------------------------------
 
+//find 根据任务名称或服务名查找启用的任务
+func (s *Processor) find(name string) []*CronTask {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	tasks := make([]*CronTask, 0, 1)
+	for i := range s.slots {
+		for item := range s.slots[i].IterBuffered() {
+			task := item.Val.(*CronTask)
+			if !task.Disable && (task.GetName() == name || task.GetService() == name) {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	return tasks
+}
+
 func (s *Processor) getOffset(now time.Time, next time.Time) (pos int, circle int) {
 	d := next.Sub(now) //剩余时间
 	delaySeconds := int(d/1e9) + 1
@@ -197,19 +392,191 @@ func (s *Processor) execute() {
 		}
 		return false
 	})
+	if s.isRunning() && len(s.delays) > 0 && atomic.CompareAndSwapInt32(&s.polling, 0, 1) {
+		go s.pollDelay()
+	}
 }
 func (s *Processor) handle(task *CronTask) error {
 	if s.done || task.Disable {
 		return nil
 	}
-	if s.status == running {
-		task.Counter.Increase()
-		s.Engine.HandleRequest(task) //触发服务引擎进行业务处理
+	scheduled := task.scheduled
+	var err error
+	if task.Overlap != "" && !task.IsImmediately() {
+		_, _, err = s.add(task) //配置了overlap策略时先加入下次执行计划，执行时长不影响后续调度
 	}
-	if task.IsImmediately() {
-		return nil
+	if s.isRunning() && !s.disabled.Has(task.GetName()) && !task.IsExcluded(scheduled) {
+		s.exec(task, scheduled, false) //触发服务引擎进行业务处理
+	}
+	if task.Overlap == "" && !task.IsImmediately() {
+		_, _, err = s.add(task) //未配置overlap策略时执行结束后再加入下次执行计划，任务不会重叠执行
 	}
-	_, _, err := s.add(task)
 	return err
+}
 
+//exec 按任务的overlap策略执行任务，租约模式下仅执行当前节点持有租约的任务
+func (s *Processor) exec(t *CronTask, scheduled time.Time, misfire bool) {
+	var token int64
+	if s.leaser != nil {
+		var ok bool
+		var err error
+		token, ok, err = s.leaser.Acquire(t.GetName())
+		if err != nil {
+			s.log.Errorf("获取任务%s的执行租约失败:%v", t.GetService(), err)
+		}
+		if !ok {
+			return
+		}
+		t = t.withHeader(context.XFencingToken, strconv.FormatInt(token, 10))
+	}
+	policy := t.GetOverlap()
+	if policy == task.OverlapAllow {
+		t.Counter.Increase()
+		s.runLeased(t, token, scheduled, misfire)
+		return
+	}
+	_, v := s.overlaps.SetIfAbsent(t.GetName(), &overlap{})
+	o := v.(*overlap)
+	if !o.acquire(policy) {
+		s.log.Warnf("任务%s上次执行未结束(%s)", t.GetService(), policy)
+		return
+	}
+	for {
+		if lk, ok := s.tryLock(t, policy); ok {
+			t.Counter.Increase()
+			s.runLeased(t, token, scheduled, misfire)
+			if lk != nil {
+				lk.Unlock()
+			}
+		}
+		if !o.release() {
+			return
+		}
+		scheduled, misfire = time.Now(), false
+	}
+}
+
+//runLeased 租约模式下执行前后检查租约，执行前租约已转移时跳过执行，
+//执行期间租约转移时无法撤销已执行的处理，只记录错误日志，业务写入需比较防护令牌拒绝过期节点的写入
+func (s *Processor) runLeased(t *CronTask, token int64, scheduled time.Time, misfire bool) {
+	if s.leaser == nil {
+		s.run(t, scheduled, misfire)
+		return
+	}
+	if !s.leaser.Check(t.GetName(), token) {
+		s.log.Warnf("任务%s的执行租约已转移(令牌:%d)，跳过执行", t.GetService(), token)
+		return
+	}
+	s.run(t, scheduled, misfire)
+	if !s.leaser.Check(t.GetName(), token) {
+		s.log.Errorf("任务%s执行期间租约已转移(令牌:%d)，可能已由其它节点重复执行", t.GetService(), token)
+	}
+}
+
+//tryLock 任务需在集群内执行overlap策略时获取分布式锁，skip策略未获取到锁时跳过，queue策略等待锁释放
+func (s *Processor) tryLock(t *CronTask, policy string) (dlock.ILock, bool) {
+	if !t.Cluster || s.registry == nil {
+		return nil, true
+	}
+	lk := dlock.NewLockByRegistry(registry.Join("cron", global.Def.SysName, t.GetName()), s.registry)
+	var err error
+	if policy == task.OverlapQueue {
+		err = lk.Lock(getLockTimeout(t))
+	} else {
+		err = lk.TryLock()
+	}
+	if err != nil {
+		s.log.Warnf("任务%s在集群中的上次执行未结束:%v", t.GetService(), err)
+		return nil, false
+	}
+	return lk, true
 }
+
+//getLockTimeout 获取等待分布式锁的超时时长
+func getLockTimeout(t *CronTask) time.Duration {
+	if t.Timeout > 0 {
+		return time.Duration(t.Timeout) * time.Second
+	}
+	return time.Minute
+}
+
+//run 执行任务并保存执行记录
+func (s *Processor) run(task *CronTask, scheduled time.Time, misfire bool) {
+	start := time.Now()
+	w, err := s.Engine.HandleRequest(task)
+	if s.history == nil {
+		return
+	}
+	end := time.Now()
+	r := &history.Record{
+		Task:      task.GetName(),
+		Service:   task.GetService(),
+		Scheduled: scheduled.Unix(),
+		Start:     start.Unix(),
+		End:       end.Unix(),
+		Node:      global.LocalIP(),
+		Status:    w.Status(),
+		Duration:  end.Sub(start).Milliseconds(),
+		Misfire:   misfire,
+	}
+	switch {
+	case err != nil:
+		r.Error = err.Error()
+	case w.Status() >= http.StatusBadRequest:
+		r.Error = string(w.Data())
+	}
+	if len(r.Error) > maxErrorLen {
+		r.Error = r.Error[:maxErrorLen]
+	}
+	if err := s.history.Save(r); err != nil {
+		s.log.Errorf("保存任务%s的执行记录失败:%v", task.GetService(), err)
+	}
+}
+
+//misfire 根据最近一次的执行记录补偿错过的执行
+func (s *Processor) misfire() {
+	if s.history == nil {
+		return
+	}
+	now := time.Now()
+	tasks := map[string]*CronTask{}
+	s.lock.Lock()
+	for i := range s.slots {
+		for item := range s.slots[i].IterBuffered() {
+			task := item.Val.(*CronTask)
+			if !task.Disable && !task.IsImmediately() {
+				tasks[task.GetName()] = task
+			}
+		}
+	}
+	s.lock.Unlock()
+	for name, task := range tasks {
+		max := task.GetMisfire(math.MaxInt32)
+		if max == 0 {
+			continue
+		}
+		last, err := s.history.Last(name)
+		if err != nil {
+			s.log.Errorf("获取任务%s的执行记录失败:%v", task.GetService(), err)
+			continue
+		}
+		if last == nil {
+			continue
+		}
+		times, count := task.Missed(last.GetScheduled(), now, max)
+		n := task.GetMisfire(count)
+		if n == 0 {
+			continue
+		}
+		s.log.Infof("任务%s错过%d次执行，补偿执行%d次", task.GetService(), count, n)
+		for _, t := range times[len(times)-n:] {
+			if s.done || !s.isRunning() || s.disabled.Has(name) {
+				break
+			}
+			s.exec(task, t, true)
+		}
+	}
+}
+
+//maxErrorLen 执行记录中错误信息的最大长度
+const maxErrorLen = 512
@@ -1,10 +1,14 @@
 package cron
 
 import (
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/micro-plat/hydra/conf/server/task"
+	"github.com/micro-plat/hydra/hydra/servers/cron/history"
+	"github.com/micro-plat/hydra/hydra/servers/pkg/dispatcher"
 	"github.com/micro-plat/hydra/hydra/servers/pkg/middleware"
 	"github.com/micro-plat/lib4go/assert"
 	"github.com/micro-plat/lib4go/concurrent/cmap"
@@ -23,7 +27,7 @@ func TestNewProcessor(t *testing.T) {
 	for _, tt := range tests {
 		middlewares = tt.handles
 		gotP := NewProcessor()
-		assert.Equalf(t, 4+len(tt.handles), len(gotP.Engine.RouterGroup.Handlers), tt.name+",中间件数量")
+		assert.Equalf(t, 10+len(tt.handles), len(gotP.Engine.RouterGroup.Handlers), tt.name+",中间件数量")
 		assert.Equalf(t, tt.wantP.slots, gotP.slots, tt.name+",slots")
 		assert.Equalf(t, tt.wantP.span, gotP.span, tt.name+",span")
 		assert.Equalf(t, tt.wantP.length, gotP.length, tt.name+",length")
@@ -56,7 +60,7 @@ func TestProcessor_Add(t *testing.T) {
 		s := NewProcessor()
 		err := s.Add(tt.ts...)
 		assert.Equalf(t, tt.wantErr, err == nil, tt.name, err)
-		assert.Equalf(t, 4+tt.count, len(s.Engine.RouterGroup.Handlers)+len(s.Engine.Routes()), tt.name+",服务数量")
+		assert.Equalf(t, 10+tt.count, len(s.Engine.RouterGroup.Handlers)+len(s.Engine.Routes()), tt.name+",服务数量")
 	}
 }
 
@@ -87,10 +91,85 @@ func TestProcessor_Remove(t *testing.T) {
 	}
 }
 
+func TestProcessor_DisableTask(t *testing.T) {
+	tk := task.NewTask("@every 10s", "/cron/serve1")
+	tests := []struct {
+		name     string
+		disable  string
+		enable   string
+		wantErr  bool
+		disabled bool
+	}{
+		{name: "1. cron-DisableTask-禁用不存在的任务", disable: "/cron/serve2", wantErr: true, disabled: false},
+		{name: "2. cron-DisableTask-按服务名禁用任务", disable: "/cron/serve1", disabled: true},
+		{name: "3. cron-DisableTask-按任务名称禁用任务", disable: tk.GetUNQ(), disabled: true},
+		{name: "4. cron-DisableTask-禁用后启用任务", disable: "/cron/serve1", enable: "/cron/serve1", disabled: false},
+	}
+	for _, tt := range tests {
+		s := NewProcessor()
+		err := s.Add(tk)
+		assert.Equalf(t, nil, err, tt.name, err)
+		err = s.DisableTask(tt.disable)
+		assert.Equalf(t, tt.wantErr, err != nil, tt.name, err)
+		if tt.enable != "" {
+			err = s.EnableTask(tt.enable)
+			assert.Equalf(t, nil, err, tt.name, err)
+		}
+		tasks := s.Tasks()
+		assert.Equalf(t, 1, len(tasks), tt.name+",任务数量")
+		assert.Equalf(t, tt.disabled, tasks[0].Disabled, tt.name+",禁用状态")
+	}
+}
+
+func TestProcessor_UseDisabled(t *testing.T) {
+	tk := task.NewTask("@every 10s", "/cron/serve1")
+	disabled := cmap.New(2)
+	s := NewProcessor()
+	s.UseDisabled(disabled)
+	assert.Equal(t, nil, s.Add(tk), "添加任务")
+	assert.Equal(t, nil, s.DisableTask("/cron/serve1"), "禁用任务")
+	s.Close()
+
+	n := NewProcessor()
+	n.UseDisabled(disabled)
+	assert.Equal(t, nil, n.Add(tk), "重启后添加任务")
+	assert.Equal(t, true, n.Tasks()[0].Disabled, "重启后保持任务的禁用状态")
+	n.Close()
+}
+
+//newTestProcessor 构建使用空服务引擎的处理器，避免执行请求时依赖应用配置
+func newTestProcessor(services ...string) *Processor {
+	s := NewProcessor()
+	s.Engine = dispatcher.New()
+	for _, service := range services {
+		s.Engine.Handle("GET", service, func(c *dispatcher.Context) {
+			c.Writer.WriteHeader(200)
+		})
+	}
+	return s
+}
+
+func TestProcessor_TriggerTask(t *testing.T) {
+	s := newTestProcessor("/cron/serve1")
+	store := &testStore{}
+	s.UseHistory(store)
+	tk := task.NewTask("@every 10s", "/cron/serve1")
+	err := s.Add(tk)
+	assert.Equal(t, nil, err, "添加任务")
+	assert.NotEqual(t, nil, s.TriggerTask("/cron/serve2", nil), "1. 执行不存在的任务")
+	assert.Equal(t, nil, s.TriggerTask("/cron/serve1", map[string]interface{}{"id": 1}), "2. 执行存在的任务")
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 1, store.count(), "2. 保存执行记录")
+	assert.Equal(t, tk.GetUNQ(), store.records[0].Task, "2. 按原任务记录执行历史")
+	assert.Equal(t, 1, s.Tasks()[0].Executed, "2. 执行次数")
+	s.Close()
+	assert.NotEqual(t, nil, s.TriggerTask("/cron/serve1", nil), "3. 服务器关闭后执行任务")
+}
+
 func TestProcessor_Pause(t *testing.T) {
 	tests := []struct {
 		name    string
-		status  int
+		status  int32
 		want    bool
 		wantErr bool
 	}{
@@ -110,7 +189,7 @@ func TestProcessor_Pause(t *testing.T) {
 func TestProcessor_Resume(t *testing.T) {
 	tests := []struct {
 		name    string
-		status  int
+		status  int32
 		want    bool
 		wantErr bool
 	}{
@@ -142,7 +221,7 @@ func TestProcessor_getOffset(t *testing.T) {
 	}
 	for _, tt := range tests {
 		s := NewProcessor()
-		taska, _ := NewCronTask(tt.args)
+		taska, _ := NewCronTask(tt.args, nil)
 		now := time.Now()
 		next := taska.NextTime(now)
 		gotPos, gotCircle := s.getOffset(now, next)
@@ -151,3 +230,170 @@ func TestProcessor_getOffset(t *testing.T) {
 		// fmt.Println("--", gotPos, "--", gotCircle)
 	}
 }
+
+type testStore struct {
+	lock    sync.Mutex
+	records []*history.Record
+}
+
+func (s *testStore) Save(r *history.Record) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.records = append(s.records, r)
+	return nil
+}
+
+func (s *testStore) count() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.records)
+}
+
+func (s *testStore) Last(task string) (*history.Record, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for i := len(s.records) - 1; i >= 0; i-- {
+		if s.records[i].Task == task {
+			return s.records[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func TestProcessor_misfire(t *testing.T) {
+	last := time.Now().Truncate(time.Hour).Add(-time.Hour * 5)
+	tests := []struct {
+		name  string
+		opts  []task.Option
+		count int
+	}{
+		{name: "1. cron-misfire-跳过错过的执行", opts: []task.Option{task.WithMisfireSkip()}, count: 0},
+		{name: "2. cron-misfire-补偿执行一次", opts: []task.Option{task.WithMisfireOnce()}, count: 1},
+		{name: "3. cron-misfire-补偿执行最多3次", opts: []task.Option{task.WithMisfireAll(3)}, count: 3},
+		{name: "4. cron-misfire-补偿执行所有", opts: []task.Option{task.WithMisfireAll(10)}, count: 5},
+	}
+	for _, tt := range tests {
+		tk := task.NewTask("0 * * * *", "/cron/serve1", tt.opts...)
+		store := &testStore{records: []*history.Record{{Task: tk.GetUNQ(), Scheduled: last.Unix()}}}
+		s := newTestProcessor("/cron/serve1")
+		s.UseHistory(store)
+		err := s.Add(tk)
+		assert.Equalf(t, nil, err, tt.name, err)
+		s.status = running
+		s.misfire()
+		assert.Equalf(t, tt.count+1, len(store.records), tt.name+",执行记录数")
+		if tt.count > 0 {
+			r := store.records[len(store.records)-1]
+			assert.Equalf(t, true, r.Misfire, tt.name+",补偿执行标识")
+			assert.Equalf(t, time.Now().Truncate(time.Hour).Unix(), r.Scheduled, tt.name+",最近一次补偿的计划时间")
+		}
+		s.Close()
+	}
+}
+
+func TestProcessor_Takeover(t *testing.T) {
+	tk := task.NewTask("0 * * * *", "/cron/serve1", task.WithMisfireOnce())
+	store := &testStore{records: []*history.Record{{Task: tk.GetUNQ(), Scheduled: time.Now().Truncate(time.Hour).Add(-time.Hour * 5).Unix()}}}
+	s := newTestProcessor("/cron/serve1")
+	defer s.Close()
+	s.UseHistory(store)
+	assert.Equal(t, nil, s.Add(tk), "添加任务")
+
+	ok, err := s.Resume()
+	assert.Equal(t, nil, err, "1. 人工恢复")
+	assert.Equal(t, true, ok, "1. 人工恢复")
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 1, store.count(), "1. 人工恢复不补偿错过的执行")
+
+	s.Pause()
+	ok, err = s.Takeover()
+	assert.Equal(t, nil, err, "2. 切换为主节点")
+	assert.Equal(t, true, ok, "2. 切换为主节点")
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 2, store.count(), "2. 切换为主节点补偿错过的执行")
+}
+
+func TestProcessor_handle(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []task.Option
+		scheduled int
+	}{
+		{name: "1. 未配置overlap时执行结束后再加入执行计划"},
+		{name: "2. 配置overlap时执行前加入执行计划", opts: []task.Option{task.WithOverlapAllow()}, scheduled: 1},
+	}
+	for _, tt := range tests {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		s := NewProcessor()
+		s.Engine = dispatcher.New()
+		s.Engine.Handle("GET", "/cron/serve1", func(c *dispatcher.Context) {
+			close(started)
+			<-release
+			c.Writer.WriteHeader(200)
+		})
+		tk, err := NewCronTask(task.NewTask("@every 10s", "/cron/serve1", tt.opts...), nil)
+		assert.Equalf(t, nil, err, tt.name, err)
+		s.status = running
+		done := make(chan error)
+		go func() {
+			done <- s.handle(tk)
+		}()
+		<-started
+		assert.Equal(t, tt.scheduled, len(s.find(tk.GetName())), tt.name+",执行中的计划数")
+		close(release)
+		assert.Equal(t, nil, <-done, tt.name)
+		assert.Equal(t, 1, len(s.find(tk.GetName())), tt.name+",执行后的计划数")
+		s.Close()
+	}
+}
+
+func TestProcessor_exec(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []task.Option
+		count int32
+	}{
+		{name: "1. cron-overlap-允许重叠执行", opts: []task.Option{task.WithOverlapAllow()}, count: 3},
+		{name: "2. cron-overlap-跳过重叠的执行", opts: []task.Option{task.WithOverlapSkip()}, count: 1},
+		{name: "3. cron-overlap-排队执行一次", opts: []task.Option{task.WithOverlapQueue()}, count: 2},
+	}
+	for _, tt := range tests {
+		var count int32
+		started := make(chan struct{}, 3)
+		release := make(chan struct{})
+		s := NewProcessor()
+		s.Engine = dispatcher.New()
+		s.Engine.Handle("GET", "/cron/serve1", func(c *dispatcher.Context) {
+			atomic.AddInt32(&count, 1)
+			started <- struct{}{}
+			<-release
+			c.Writer.WriteHeader(200)
+		})
+		tk, err := NewCronTask(task.NewTask("@every 10s", "/cron/serve1", tt.opts...), nil)
+		assert.Equalf(t, nil, err, tt.name, err)
+
+		wg := sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.exec(tk, time.Now(), false)
+		}()
+		<-started
+		for i := 0; i < 2; i++ {
+			if tk.GetOverlap() != task.OverlapAllow {
+				s.exec(tk, time.Now(), false)
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.exec(tk, time.Now(), false)
+			}()
+		}
+		close(release)
+		wg.Wait()
+		assert.Equalf(t, tt.count, atomic.LoadInt32(&count), tt.name+",执行次数")
+		s.Close()
+	}
+}
@@ -3,18 +3,29 @@ package cron
 import (
 	"fmt"
 	"strings"
+	"sync/atomic"
 
+	"github.com/micro-plat/hydra/components"
+	"github.com/micro-plat/hydra/components/delay"
 	"github.com/micro-plat/hydra/conf"
 	"github.com/micro-plat/hydra/conf/app"
 	"github.com/micro-plat/hydra/conf/server/cron"
 	"github.com/micro-plat/hydra/conf/server/task"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/hydra/hydra/servers"
+	"github.com/micro-plat/hydra/hydra/servers/cron/history"
+	"github.com/micro-plat/hydra/hydra/servers/pkg/middleware"
+	"github.com/micro-plat/hydra/registry"
 	"github.com/micro-plat/hydra/registry/pub"
 	"github.com/micro-plat/hydra/services"
+	"github.com/micro-plat/lib4go/concurrent/cmap"
 	"github.com/micro-plat/lib4go/logger"
 )
 
+var _ servers.IPauser = &Responsive{}
+var _ servers.ITaskController = &Responsive{}
+var _ servers.IInspector = &Responsive{}
+
 //Responsive 响应式服务器
 type Responsive struct {
 	*Server
@@ -22,6 +33,8 @@ type Responsive struct {
 	comparer conf.IComparer
 	pub      pub.IPublisher
 	log      logger.ILogger
+	paused   int32
+	disabled cmap.ConcurrentMap
 	first    bool
 }
 
@@ -30,6 +43,7 @@ func NewResponsive(cnf app.IAPPConf) (h *Responsive, err error) {
 	h = &Responsive{
 		conf:     cnf,
 		first:    true,
+		disabled: cmap.New(2),
 		log:      logger.New(cnf.GetServerConf().GetServerName()),
 		pub:      pub.New(cnf.GetServerConf()),
 		comparer: conf.NewComparer(cnf.GetServerConf(), cron.MainConfName, cron.SubConfName...),
@@ -55,6 +69,19 @@ func (w *Responsive) Start() (err error) {
 		return
 	}
 
+	//启动prometheus独立端口
+	if err = middleware.ServePrometheus(w.conf); err != nil {
+		w.Server.Shutdown()
+		return err
+	}
+
+	//启动慢请求分析独立端口
+	if err = middleware.ServeProfile(w.conf); err != nil {
+		w.Server.Shutdown()
+		middleware.ClosePrometheus(w.conf.GetServerConf().GetServerType())
+		return err
+	}
+
 	//发布集群节点
 	if err = w.publish(); err != nil {
 		err = fmt.Errorf("%s服务发布失败 %w", w.conf.GetServerConf().GetServerType(), err)
@@ -117,6 +144,10 @@ func (w *Responsive) subscribe() {
 func (w *Responsive) Shutdown() {
 	w.log.Infof("关闭[%s]服务...", w.conf.GetServerConf().GetServerType())
 	w.Server.Shutdown()
+	middleware.ClosePrometheus(w.conf.GetServerConf().GetServerType())
+	middleware.CloseProfile(w.conf.GetServerConf().GetServerType())
+	middleware.CloseAPM(w.conf.GetServerConf().GetServerType())
+	middleware.CloseAudit(w.conf.GetServerConf().GetServerType())
 	w.pub.Clear()
 	if err := services.Def.DoClosing(w.conf); err != nil {
 		w.log.Infof("关闭[%s]服务,出现错误", err)
@@ -125,6 +156,51 @@ func (w *Responsive) Shutdown() {
 	return
 }
 
+//Pause 人工暂停服务器，暂停期间不随集群主从变化自动恢复
+func (w *Responsive) Pause() (bool, error) {
+	atomic.StoreInt32(&w.paused, 1)
+	return w.Server.Pause()
+}
+
+//Resume 恢复人工暂停的服务器，主备或分片模式下仅主节点恢复执行，不补偿暂停期间错过的执行
+func (w *Responsive) Resume() (bool, error) {
+	atomic.StoreInt32(&w.paused, 0)
+	if !w.isMaster() {
+		return false, nil
+	}
+	return w.Server.Resume()
+}
+
+//isPaused 是否被人工暂停
+func (w *Responsive) isPaused() bool {
+	return atomic.LoadInt32(&w.paused) == 1
+}
+
+//isMaster 当前节点是否应执行任务
+func (w *Responsive) isMaster() bool {
+	server, err := cron.GetConf(w.conf.GetServerConf())
+	if err != nil {
+		return false
+	}
+	if server.Sharding == 0 || server.Lease {
+		return true
+	}
+	cluster, err := w.conf.GetServerConf().GetCluster()
+	if err != nil {
+		return false
+	}
+	return cluster.Current().IsMaster(server.Sharding)
+}
+
+//Inspect 获取服务器运行信息
+func (w *Responsive) Inspect() interface{} {
+	return map[string]interface{}{
+		"status": w.Server.Status(),
+		"paused": w.isPaused(),
+		"tasks":  w.Server.Tasks(),
+	}
+}
+
 //publish 将当前服务器的节点信息发布到注册中心
 func (w *Responsive) publish() (err error) {
 	addr := w.Server.GetAddress()
@@ -148,7 +224,7 @@ func (w *Responsive) update(kv ...string) (err error) {
 
 //根据main.conf创建服务嚣
 func (w *Responsive) getServer(cnf app.IAPPConf) (*Server, error) {
-	_, err := cron.GetConf(cnf.GetServerConf())
+	cronConf, err := cron.GetConf(cnf.GetServerConf())
 	if err != nil {
 		return nil, err
 	}
@@ -157,8 +233,48 @@ func (w *Responsive) getServer(cnf app.IAPPConf) (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
+	store, err := history.New(cnf)
+	if err != nil {
+		return nil, err
+	}
+
 	//初始化server
-	return NewServer(task.Tasks...)
+	server, err := NewServer(task.Tasks...)
+	if err != nil {
+		return nil, err
+	}
+	server.UseHistory(store)
+	server.UseDisabled(w.disabled)
+	server.UseRegistry(cnf.GetServerConf().GetRegistry())
+	if err := useDelay(cnf, server); err != nil {
+		return nil, err
+	}
+	if cronConf.Lease {
+		sc := cnf.GetServerConf()
+		server.UseLease(sc.GetRegistry(), registry.Join(sc.GetServerRoot(), sc.GetClusterName(), "lease"), sc.GetServerID())
+	}
+	return server, nil
+}
+
+//useDelay 根据延迟任务执行配置设置延迟任务执行器
+func useDelay(cnf app.IAPPConf, server *Server) error {
+	delayConf, err := cnf.GetCRONDelayConf()
+	if err != nil {
+		return err
+	}
+	if delayConf.Disable {
+		return nil
+	}
+	executors := make([]delay.IExecutor, 0, len(delayConf.Names))
+	for _, name := range delayConf.Names {
+		e, err := components.Def.Delay().GetExecutor(name)
+		if err != nil {
+			return fmt.Errorf("获取延迟任务执行器%s失败:%w", name, err)
+		}
+		executors = append(executors, e)
+	}
+	server.UseDelay(delayConf.Batch, executors...)
+	return nil
 }
 
 func init() {
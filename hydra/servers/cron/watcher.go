@@ -3,6 +3,7 @@ package cron
 import (
 	"time"
 
+	"github.com/micro-plat/hydra/conf"
 	"github.com/micro-plat/hydra/conf/server/cron"
 )
 
@@ -47,8 +48,14 @@ LOOP:
 				continue
 			}
 
-			if server.Sharding == 0 || cluster.Current().IsMaster(server.Sharding) {
-				ok, err := w.Server.Resume()
+			if server.Lease {
+				w.Server.UpdateNodes(getNodeIDs(cluster)...)
+			}
+			if server.Sharding == 0 || server.Lease || cluster.Current().IsMaster(server.Sharding) {
+				if w.isPaused() {
+					continue
+				}
+				ok, err := w.Server.Takeover()
 				if err != nil {
 					w.log.Error("恢复服务器失败:", err)
 					continue
@@ -74,3 +81,13 @@ LOOP:
 		}
 	}
 }
+
+//getNodeIDs 获取集群中所有存活节点的编号
+func getNodeIDs(cluster conf.ICluster) []string {
+	ids := make([]string, 0, cluster.Len())
+	cluster.Iter(func(n conf.ICNode) bool {
+		ids = append(ids, n.GetNodeID())
+		return true
+	})
+	return ids
+}
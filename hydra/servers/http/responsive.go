@@ -9,6 +9,7 @@ import (
 	"github.com/micro-plat/hydra/conf/server/api"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/hydra/hydra/servers"
+	"github.com/micro-plat/hydra/hydra/servers/pkg/middleware"
 	"github.com/micro-plat/hydra/registry/pub"
 	"github.com/micro-plat/hydra/services"
 	"github.com/micro-plat/lib4go/logger"
@@ -53,6 +54,19 @@ func (w *Responsive) Start() (err error) {
 		return
 	}
 
+	//启动prometheus独立端口
+	if err = middleware.ServePrometheus(w.conf); err != nil {
+		w.Server.Shutdown()
+		return err
+	}
+
+	//启动慢请求分析独立端口
+	if err = middleware.ServeProfile(w.conf); err != nil {
+		w.Server.Shutdown()
+		middleware.ClosePrometheus(w.conf.GetServerConf().GetServerType())
+		return err
+	}
+
 	if err = w.publish(); err != nil {
 		err = fmt.Errorf("%s服务发布失败 %w", w.conf.GetServerConf().GetServerType(), err)
 		w.Shutdown()
@@ -99,6 +113,10 @@ func (w *Responsive) Notify(c app.IAPPConf) (change bool, err error) {
 func (w *Responsive) Shutdown() {
 	w.log.Infof("关闭[%s]服务...", w.conf.GetServerConf().GetServerType())
 	w.Server.Shutdown()
+	middleware.ClosePrometheus(w.conf.GetServerConf().GetServerType())
+	middleware.CloseProfile(w.conf.GetServerConf().GetServerType())
+	middleware.CloseAPM(w.conf.GetServerConf().GetServerType())
+	middleware.CloseAudit(w.conf.GetServerConf().GetServerType())
 	w.pub.Clear()
 	if err := services.Def.DoClosing(w.conf); err != nil {
 		w.log.Infof("关闭[%s]服务,出现错误", err)
@@ -17,20 +17,27 @@ func (s *Server) addHttpRouters(routers ...*router.Router) {
 	s.engine.Use(middleware.Recovery().GinFunc(s.serverType))
 	s.engine.Use(middleware.Logging().GinFunc()) //记录请求日志
 	s.engine.Use(middleware.Recovery().GinFunc())
-	// s.engine.Use(middleware.APM().GinFunc())       //链数跟踪
-	s.engine.Use(middleware.Trace().GinFunc())     //跟踪信息
-	s.engine.Use(middleware.BlackList().GinFunc()) //黑名单控制
-	s.engine.Use(middleware.WhiteList().GinFunc()) //白名单控制
-	s.engine.Use(middleware.Proxy().GinFunc())     //灰度配置
-	s.engine.Use(middleware.Delay().GinFunc())     //
-	s.engine.Use(middleware.Limit().GinFunc())     //限流处理
-	s.engine.Use(middleware.Static().GinFunc())    //处理静态文件
-	s.engine.Use(middleware.Header().GinFunc())    //设置请求头
-	s.engine.Use(middleware.Options().GinFunc())   //处理option响应
-	s.engine.Use(middleware.BasicAuth().GinFunc()) //
+	s.engine.Use(middleware.Prometheus().GinFunc()) //prometheus统计
+	s.engine.Use(middleware.Profile().GinFunc())    //慢请求分析
+	s.engine.Use(middleware.APM().GinFunc())        //链路跟踪
+	s.engine.Use(middleware.Audit().GinFunc())      //请求审计
+	s.engine.Use(middleware.Trace().GinFunc())      //跟踪信息
+	s.engine.Use(middleware.BlackList().GinFunc())  //黑名单控制
+	s.engine.Use(middleware.WhiteList().GinFunc())  //白名单控制
+	s.engine.Use(middleware.Proxy().GinFunc())      //灰度配置
+	s.engine.Use(middleware.Delay().GinFunc())      //
+	s.engine.Use(middleware.Fault().GinFunc())      //故障注入
+	s.engine.Use(middleware.Limit().GinFunc())      //限流处理
+	s.engine.Use(middleware.Mirror().GinFunc())     //流量复制
+	s.engine.Use(middleware.Static().GinFunc())     //处理静态文件
+	s.engine.Use(middleware.Header().GinFunc())     //设置请求头
+	s.engine.Use(middleware.Options().GinFunc())    //处理option响应
+	s.engine.Use(middleware.BasicAuth().GinFunc())  //
 	s.engine.Use(middleware.APIKeyAuth().GinFunc())
 	s.engine.Use(middleware.RASAuth().GinFunc())
-	s.engine.Use(middleware.JwtAuth().GinFunc()) //jwt安全认证
+	s.engine.Use(middleware.JwtAuth().GinFunc())     //jwt安全认证
+	s.engine.Use(middleware.Idempotency().GinFunc()) //幂等处理
+	s.engine.Use(middleware.RespCache().GinFunc())   //响应缓存
 	s.engine.Use(middlewares.GinFunc()...)
 
 	s.engine.Use(middleware.Render().GinFunc())    //响应渲染组件
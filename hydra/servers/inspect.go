@@ -0,0 +1,51 @@
+package servers
+
+import (
+	"sort"
+
+	"github.com/micro-plat/lib4go/concurrent/cmap"
+)
+
+//IInspector 可输出运行时信息的服务器
+type IInspector interface {
+	Inspect() interface{}
+}
+
+const (
+	//StatusUnstarted 未启动
+	StatusUnstarted int32 = 1
+	//StatusPause 已暂停
+	StatusPause int32 = 2
+	//StatusRunning 运行中
+	StatusRunning int32 = 4
+)
+
+//StatusName 获取服务器运行状态名称
+func StatusName(status int32) string {
+	switch status {
+	case StatusPause:
+		return "pause"
+	case StatusRunning:
+		return "running"
+	}
+	return "unstarted"
+}
+
+//running 运行中的服务器
+var running = cmap.New(4)
+
+//GetServer 获取运行中的服务器
+func GetServer(tp string) (IResponsiveServer, bool) {
+	v, ok := running.Get(tp)
+	if !ok {
+		return nil, false
+	}
+	return v.(IResponsiveServer), true
+}
+
+//GetRunningTypes 获取运行中的服务器类型
+func GetRunningTypes() []string {
+	tps := running.Keys()
+	sort.Strings(tps)
+	return tps
+}
@@ -2,20 +2,24 @@ package mqc
 
 import (
 	"fmt"
+	"net/http"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/micro-plat/hydra/components/queues/mq"
 	"github.com/micro-plat/hydra/conf/server/queue"
+	"github.com/micro-plat/hydra/hydra/servers"
 	"github.com/micro-plat/hydra/hydra/servers/pkg/dispatcher"
 	"github.com/micro-plat/hydra/hydra/servers/pkg/middleware"
 	"github.com/micro-plat/lib4go/concurrent/cmap"
 )
 
 const (
-	unstarted = 1
-	pause     = 2
-	running   = 4
+	unstarted = servers.StatusUnstarted
+	pause     = servers.StatusPause
+	running   = servers.StatusRunning
 )
 
 //Processor cron管理程序，用于管理多个任务的执行，暂停，恢复，动态添加，移除
@@ -28,7 +32,9 @@ type Processor struct {
 	metric    *middleware.Metric
 	startTime time.Time
 	customer  mq.IMQC
-	status    int
+	retrier   *retrier
+	status    int32
+	disabled  cmap.ConcurrentMap
 }
 
 //NewProcessor 创建processor
@@ -38,7 +44,9 @@ func NewProcessor(proto string, confRaw string) (p *Processor, err error) {
 		closeChan: make(chan struct{}),
 		startTime: time.Now(),
 		queues:    cmap.New(4),
+		disabled:  cmap.New(2),
 		metric:    middleware.NewMetric(),
+		retrier:   newRetrier(proto, confRaw),
 	}
 
 	p.customer, err = mq.NewMQC(proto, confRaw)
@@ -49,7 +57,12 @@ func NewProcessor(proto string, confRaw string) (p *Processor, err error) {
 	p.Engine.Use(middleware.Recovery().DispFunc(MQC))
 	p.Engine.Use(middleware.Logging().DispFunc())
 	p.Engine.Use(middleware.Recovery().DispFunc())
-	p.Engine.Use(middleware.Trace().DispFunc()) //跟踪信息
+	p.Engine.Use(middleware.Prometheus().DispFunc()) //prometheus统计
+	p.Engine.Use(middleware.Profile().DispFunc())    //慢请求分析
+	p.Engine.Use(middleware.APM().DispFunc())        //链路跟踪
+	p.Engine.Use(middleware.Audit().DispFunc())      //请求审计
+	p.Engine.Use(middleware.Trace().DispFunc())      //跟踪信息
+	p.Engine.Use(middleware.Fault().DispFunc())      //故障注入
 	p.Engine.Use(p.metric.Handle().DispFunc())
 	p.Engine.Use(middlewares.DispFunc()...)
 
@@ -66,6 +79,32 @@ func (s *Processor) QueueItems() map[string]interface{} {
 	return s.queues.Items()
 }
 
+//QueueInfo 队列消费信息
+type QueueInfo struct {
+	Queue       string `json:"queue"`
+	Service     string `json:"service"`
+	Concurrency int    `json:"concurrency"`
+	Disabled    bool   `json:"disabled"`
+}
+
+//Queues 获取当前消费的队列及并发数
+func (s *Processor) Queues() []*QueueInfo {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	list := make([]*QueueInfo, 0, s.queues.Count())
+	for _, v := range s.queues.Items() {
+		q := v.(*queue.Queue)
+		list = append(list, &QueueInfo{Queue: q.Queue, Service: q.Service, Concurrency: q.Concurrency, Disabled: s.disabled.Has(q.Queue)})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Queue < list[j].Queue })
+	return list
+}
+
+//Status 获取运行状态
+func (s *Processor) Status() string {
+	return servers.StatusName(atomic.LoadInt32(&s.status))
+}
+
 //Start 所有任务
 func (s *Processor) Start(wait ...bool) error {
 	if err := s.customer.Connect(); err != nil {
@@ -81,7 +120,7 @@ func (s *Processor) Start(wait ...bool) error {
 //Add 添加队列信息
 func (s *Processor) Add(queues ...*queue.Queue) error {
 	for _, queue := range queues {
-		if ok, _ := s.queues.SetIfAbsent(queue.Queue, queue); ok && s.status == running {
+		if ok, _ := s.queues.SetIfAbsent(queue.Queue, queue); ok && s.isRunning() && !s.disabled.Has(queue.Queue) {
 			if err := s.consume(queue); err != nil {
 				return err
 			}
@@ -103,8 +142,7 @@ func (s *Processor) Remove(queues ...*queue.Queue) error {
 func (s *Processor) Pause() (bool, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	if s.status != pause {
-		s.status = pause
+	if atomic.SwapInt32(&s.status, pause) != pause {
 		items := s.queues.Items()
 		for _, v := range items {
 			queue := v.(*queue.Queue)
@@ -119,11 +157,13 @@ func (s *Processor) Pause() (bool, error) {
 func (s *Processor) Resume() (bool, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	if s.status != running {
-		s.status = running
+	if atomic.SwapInt32(&s.status, running) != running {
 		items := s.queues.Items()
 		for _, v := range items {
 			queue := v.(*queue.Queue)
+			if s.disabled.Has(queue.Queue) {
+				continue
+			}
 			if err := s.consume(queue); err != nil {
 				return true, err
 			}
@@ -132,6 +172,48 @@ func (s *Processor) Resume() (bool, error) {
 	}
 	return false, nil
 }
+
+//UseDisabled 设置被停止消费的队列列表，服务器因配置变化重启后保持队列的停止状态
+func (s *Processor) UseDisabled(disabled cmap.ConcurrentMap) {
+	s.disabled = disabled
+}
+
+//isRunning 是否正在运行
+func (s *Processor) isRunning() bool {
+	return atomic.LoadInt32(&s.status) == running
+}
+
+//DisableQueue 停止消费指定队列，服务器恢复后仍不消费
+func (s *Processor) DisableQueue(name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.queues.Has(name) {
+		return fmt.Errorf("队列不存在:%s", name)
+	}
+	if ok, _ := s.disabled.SetIfAbsent(name, true); ok && s.isRunning() {
+		s.customer.UnConsume(name)
+	}
+	return nil
+}
+
+//EnableQueue 恢复消费被停止的队列
+func (s *Processor) EnableQueue(name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	v, ok := s.queues.Get(name)
+	if !ok {
+		return fmt.Errorf("队列不存在:%s", name)
+	}
+	if !s.disabled.Has(name) {
+		return nil
+	}
+	s.disabled.Remove(name)
+	if s.isRunning() {
+		return s.consume(v.(*queue.Queue))
+	}
+	return nil
+}
+
 func (s *Processor) consume(queue *queue.Queue) error {
 	if !s.Engine.Find(queue.Service) {
 		s.Engine.Handle(DefMethod, queue.Service, middleware.ExecuteHandler(queue.Service).DispFunc(MQC))
@@ -152,15 +234,42 @@ func (s *Processor) Close() {
 		close(s.closeChan)
 		s.queues.Clear()
 		s.customer.Close()
+		s.retrier.Close()
 	}
 }
 
 func (s *Processor) handle(queue *queue.Queue) func(mq.IMQCMessage) {
 	return func(m mq.IMQCMessage) {
+		if !s.retrier.Wait(m.GetMessage(), s.closeChan) {
+			m.Nack()
+			return
+		}
 		req, err := NewRequest(queue, m)
 		if err != nil {
 			panic(err)
 		}
-		s.Engine.HandleRequest(req)
+		w, err := s.Engine.HandleRequest(req)
+		switch {
+		case err != nil:
+			s.fail(queue, m, err.Error())
+		case w.Status() >= http.StatusBadRequest:
+			s.fail(queue, m, fmt.Sprintf("%d:%s", w.Status(), w.Data()))
+		default:
+			m.Ack()
+		}
+	}
+}
+
+//fail 消息处理失败，按队列的重试配置重新放入队列或放入死信队列，放入成功后才确认原消息
+func (s *Processor) fail(queue *queue.Queue, m mq.IMQCMessage, reason string) {
+	if queue.Attempts <= 0 && queue.DeadLetter == "" {
+		m.Nack()
+		return
+	}
+	if err := s.retrier.Fail(queue, m.GetMessage(), reason); err != nil {
+		s.retrier.log.Error(err)
+		m.Nack()
+		return
 	}
+	m.Ack()
 }
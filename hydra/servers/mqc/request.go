@@ -1,14 +1,8 @@
 package mqc
 
 import (
-	"encoding/json"
-	"fmt"
-
-	"github.com/micro-plat/lib4go/encoding/base64"
-
 	"github.com/micro-plat/hydra/components/queues/mq"
 	"github.com/micro-plat/hydra/conf/server/queue"
-	"github.com/micro-plat/lib4go/types"
 )
 
 const DefMethod = "GET"
@@ -32,28 +26,16 @@ func NewRequest(queue *queue.Queue, m mq.IMQCMessage) (r *Request, err error) {
 		header:      make(map[string]string),
 	}
 
-	//将消息原串转换为map
-	input := make(map[string]interface{})
+	//解析消息信封，消息编号、内容类型、创建时间、处理次数、跟踪信息等作为请求头
 	message := m.GetMessage()
-	json.Unmarshal(types.StringToBytes(message), &input)
-
-	//检查是否包含头信息
-	r.form["__body__"] = message
-	if v, ok := input["__header__"].(map[string]interface{}); ok {
-		for n, m := range v {
-			r.header[n] = fmt.Sprint(m)
-		}
-	}
+	e := mq.ParseEnvelope(message)
+	r.form["__body__"] = e.Body
+	r.header = e.GetHeaders()
 
 	//处理头信息
 	r.header["__all__"] = message
 	if _, ok := r.header["Content-Type"]; !ok {
-		r.header["Content-Type"] = "application/json"
-	}
-
-	if v, ok := input["__data__"].(string); ok {
-		buff, _ := base64.DecodeBytes(v)
-		r.form["__body__"] = string(buff)
+		r.header["Content-Type"] = mq.DefContentType
 	}
 	return r, nil
 }
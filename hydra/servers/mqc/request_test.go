@@ -0,0 +1,38 @@
+package mqc
+
+import (
+	"testing"
+
+	"github.com/micro-plat/hydra/components/queues/mq"
+	"github.com/micro-plat/hydra/conf/server/queue"
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+type testMessage string
+
+func (m testMessage) Ack() error         { return nil }
+func (m testMessage) Nack() error        { return nil }
+func (m testMessage) GetMessage() string { return string(m) }
+
+func TestNewRequest(t *testing.T) {
+	q := queue.NewQueue("order", "/order")
+	e := mq.NewEnvelope(`{"id":1}`)
+	e.Header[context.XRequestID] = "abc"
+	e.Trace["traceparent"] = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+	r, err := NewRequest(q, testMessage(e.Encode()))
+	assert.Equal(t, nil, err, "1. 构建请求")
+	h := r.GetHeader()
+	assert.Equal(t, e.ID, h[context.XMessageID], "2. 消息编号")
+	assert.Equal(t, "abc", h[context.XRequestID], "3. 请求编号")
+	assert.Equal(t, "1", h[context.XMessageAttempts], "4. 处理次数")
+	assert.Equal(t, e.Trace["traceparent"], h["traceparent"], "5. 跟踪信息")
+	assert.Equal(t, mq.DefContentType, h["Content-Type"], "6. 内容类型")
+	assert.Equal(t, `{"id":1}`, r.GetForm()["__body__"], "7. 消息内容")
+
+	r, err = NewRequest(q, testMessage(`{"id":2}`))
+	assert.Equal(t, nil, err, "8. 构建普通消息的请求")
+	assert.Equal(t, `{"id":2}`, r.GetForm()["__body__"], "9. 普通消息的内容")
+	assert.Equal(t, "application/json", r.GetHeader()["Content-Type"], "10. 默认内容类型")
+}
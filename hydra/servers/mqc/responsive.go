@@ -3,6 +3,7 @@ package mqc
 import (
 	"fmt"
 	"strings"
+	"sync/atomic"
 
 	"github.com/micro-plat/hydra/conf"
 	"github.com/micro-plat/hydra/conf/app"
@@ -11,11 +12,17 @@ import (
 	varqueue "github.com/micro-plat/hydra/conf/vars/queue"
 	"github.com/micro-plat/hydra/global"
 	"github.com/micro-plat/hydra/hydra/servers"
+	"github.com/micro-plat/hydra/hydra/servers/pkg/middleware"
 	"github.com/micro-plat/hydra/registry/pub"
 	"github.com/micro-plat/hydra/services"
+	"github.com/micro-plat/lib4go/concurrent/cmap"
 	"github.com/micro-plat/lib4go/logger"
 )
 
+var _ servers.IPauser = &Responsive{}
+var _ servers.IQueueController = &Responsive{}
+var _ servers.IInspector = &Responsive{}
+
 //Responsive 响应式服务器
 type Responsive struct {
 	*Server
@@ -23,6 +30,8 @@ type Responsive struct {
 	comparer conf.IComparer
 	pub      pub.IPublisher
 	log      logger.ILogger
+	paused   int32
+	disabled cmap.ConcurrentMap
 	first    bool
 }
 
@@ -31,6 +40,7 @@ func NewResponsive(cnf app.IAPPConf) (h *Responsive, err error) {
 	h = &Responsive{
 		conf:     cnf,
 		first:    true,
+		disabled: cmap.New(2),
 		log:      logger.New(cnf.GetServerConf().GetServerName()),
 		pub:      pub.New(cnf.GetServerConf()),
 		comparer: conf.NewComparer(cnf.GetServerConf(), mqc.MainConfName, mqc.SubConfName...),
@@ -54,6 +64,19 @@ func (w *Responsive) Start() (err error) {
 		return
 	}
 
+	//启动prometheus独立端口
+	if err = middleware.ServePrometheus(w.conf); err != nil {
+		w.Server.Shutdown()
+		return err
+	}
+
+	//启动慢请求分析独立端口
+	if err = middleware.ServeProfile(w.conf); err != nil {
+		w.Server.Shutdown()
+		middleware.ClosePrometheus(w.conf.GetServerConf().GetServerType())
+		return err
+	}
+
 	//发布集群节点
 	if err = w.publish(); err != nil {
 		err = fmt.Errorf("%s服务发布失败 %w", w.conf.GetServerConf().GetServerType(), err)
@@ -111,6 +134,10 @@ func (w *Responsive) Notify(c app.IAPPConf) (change bool, err error) {
 func (w *Responsive) Shutdown() {
 	w.log.Infof("关闭[%s]服务...", w.conf.GetServerConf().GetServerType())
 	w.Server.Shutdown()
+	middleware.ClosePrometheus(w.conf.GetServerConf().GetServerType())
+	middleware.CloseProfile(w.conf.GetServerConf().GetServerType())
+	middleware.CloseAPM(w.conf.GetServerConf().GetServerType())
+	middleware.CloseAudit(w.conf.GetServerConf().GetServerType())
 	w.pub.Clear()
 	if err := services.Def.DoClosing(w.conf); err != nil {
 		w.log.Infof("关闭[%s]服务,出现错误", err)
@@ -119,6 +146,51 @@ func (w *Responsive) Shutdown() {
 	return
 }
 
+//Pause 人工暂停服务器，暂停期间不随集群主从变化自动恢复
+func (w *Responsive) Pause() (bool, error) {
+	atomic.StoreInt32(&w.paused, 1)
+	return w.Server.Pause()
+}
+
+//Resume 恢复人工暂停的服务器，主备或分片模式下仅主节点恢复执行
+func (w *Responsive) Resume() (bool, error) {
+	atomic.StoreInt32(&w.paused, 0)
+	if !w.isMaster() {
+		return false, nil
+	}
+	return w.Server.Resume()
+}
+
+//isPaused 是否被人工暂停
+func (w *Responsive) isPaused() bool {
+	return atomic.LoadInt32(&w.paused) == 1
+}
+
+//isMaster 当前节点是否应执行任务
+func (w *Responsive) isMaster() bool {
+	server, err := w.conf.GetMQCMainConf()
+	if err != nil {
+		return false
+	}
+	if server.Sharding == 0 {
+		return true
+	}
+	cluster, err := w.conf.GetServerConf().GetCluster()
+	if err != nil {
+		return false
+	}
+	return cluster.Current().IsMaster(server.Sharding)
+}
+
+//Inspect 获取服务器运行信息
+func (w *Responsive) Inspect() interface{} {
+	return map[string]interface{}{
+		"status": w.Server.Status(),
+		"paused": w.isPaused(),
+		"queues": w.Server.Queues(),
+	}
+}
+
 //publish 将当前服务器的节点信息发布到注册中心
 func (w *Responsive) publish() (err error) {
 	addr := w.Server.GetAddress()
@@ -156,15 +228,20 @@ func (w *Responsive) getServer(cnf app.IAPPConf) (*Server, error) {
 	if err != nil {
 		return nil, fmt.Errorf("mqc服务器监听队列配置有误:%w", err)
 	}
-	if global.IsLocal(proto) {
-		return NewServer(proto, nil, queueObj.Queues...)
+	var raw []byte
+	if !global.IsLocal(proto) {
+		js, err := cnf.GetVarConf().GetConf(varqueue.TypeNodeName, queuename)
+		if err != nil {
+			return nil, fmt.Errorf("获取mqc服务器配置失败./var/%s/%s %w", varqueue.TypeNodeName, queuename, err)
+		}
+		raw = js.GetRaw()
 	}
-
-	js, err := cnf.GetVarConf().GetConf(varqueue.TypeNodeName, queuename)
+	server, err := NewServer(proto, raw, queueObj.Queues...)
 	if err != nil {
-		return nil, fmt.Errorf("获取mqc服务器配置失败./var/%s/%s %w", varqueue.TypeNodeName, queuename, err)
+		return nil, err
 	}
-	return NewServer(proto, js.GetRaw(), queueObj.Queues...)
+	server.UseDisabled(w.disabled)
+	return server, nil
 }
 
 func init() {
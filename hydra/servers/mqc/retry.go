@@ -0,0 +1,124 @@
+package mqc
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/micro-plat/hydra/components/queues/mq"
+	"github.com/micro-plat/hydra/conf/server/queue"
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/hydra/global"
+	"github.com/micro-plat/lib4go/logger"
+)
+
+//retrier 处理失败的消息立即重新放入队列并记录到期时间，消费时等待到期后再处理，
+//超过最大处理次数后放入死信队列。重试消息放入队列成功后才确认原消息，服务器重启不丢失重试消息
+type retrier struct {
+	proto    string
+	raw      string
+	producer mq.IMQP
+	lock     sync.Mutex
+	log      logger.ILogger
+}
+
+func newRetrier(proto string, raw string) *retrier {
+	return &retrier{
+		proto: proto,
+		raw:   raw,
+		log:   logger.New("mqc"),
+	}
+}
+
+//getAttempts 获取消息当前的处理次数
+func getAttempts(message string) int {
+	n, err := strconv.Atoi(mq.GetHeaders(message)[context.XMessageAttempts])
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+//getRetryAt 获取重试消息的到期时间，非重试消息返回零值
+func getRetryAt(message string) time.Time {
+	n, err := strconv.ParseInt(mq.GetHeaders(message)[context.XMessageRetryAt], 10, 64)
+	if err != nil || n <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n*int64(time.Millisecond))
+}
+
+//Fail 消息处理失败，未超过最大处理次数时重新放入队列并在延迟时长后处理，否则放入死信队列
+func (r *retrier) Fail(q *queue.Queue, message string, reason string) error {
+	attempts := getAttempts(message)
+	if attempts < q.Attempts {
+		retryAt := time.Now().Add(q.GetBackoff(attempts))
+		next := mq.SetHeaders(message, map[string]string{
+			context.XMessageAttempts: strconv.Itoa(attempts + 1),
+			context.XMessageRetryAt:  strconv.FormatInt(retryAt.UnixNano()/int64(time.Millisecond), 10),
+		})
+		if err := r.push(q.Queue, next); err != nil {
+			return fmt.Errorf("消息重新放入队列失败(%s):%w", q.Queue, err)
+		}
+		return nil
+	}
+	if q.DeadLetter == "" {
+		if q.Attempts > 0 {
+			r.log.Errorf("消息超过最大处理次数%d,已丢弃(%s):%s", q.Attempts, q.Queue, message)
+		}
+		return nil
+	}
+	dead := mq.SetHeaders(message, map[string]string{
+		context.XMessageAttempts: strconv.Itoa(attempts),
+		context.XMessageRetryAt:  "",
+		context.XOriginQueue:     q.Queue,
+		context.XDeadReason:      reason,
+	})
+	if err := r.push(q.DeadLetter, dead); err != nil {
+		return fmt.Errorf("消息放入死信队列失败(%s):%w", q.DeadLetter, err)
+	}
+	return nil
+}
+
+//Wait 重试消息到期前等待，等待期间占用当前消费协程，服务器关闭时返回false
+func (r *retrier) Wait(message string, closeChan chan struct{}) bool {
+	d := time.Until(getRetryAt(message))
+	if d <= 0 {
+		return true
+	}
+	if d > queue.MaxBackoff {
+		d = queue.MaxBackoff
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-closeChan:
+		return false
+	}
+}
+
+func (r *retrier) push(name string, message string) (err error) {
+	r.lock.Lock()
+	if r.producer == nil {
+		r.producer, err = mq.NewMQP(r.proto, r.raw)
+	}
+	producer := r.producer
+	r.lock.Unlock()
+	if err != nil {
+		return fmt.Errorf("构建消息生产者失败(proto:%s) %w", r.proto, err)
+	}
+	return producer.Push(name, message)
+}
+
+//Close 释放资源
+func (r *retrier) Close() {
+	//本地队列的生产者关闭时会关闭进程内所有队列，不需关闭
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.producer != nil && !global.IsLocal(r.proto) {
+		r.producer.Close()
+	}
+}
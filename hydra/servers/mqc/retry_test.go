@@ -0,0 +1,74 @@
+package mqc
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/micro-plat/hydra/components/queues/mq"
+	"github.com/micro-plat/hydra/components/queues/mq/lmq"
+	"github.com/micro-plat/hydra/conf/server/queue"
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestRetrier_Fail(t *testing.T) {
+	r := newRetrier("lmq", "")
+	defer r.Close()
+	q := queue.NewQueue("retry:order", "/order", queue.WithRetry(2, 1000), queue.WithDeadLetter("retry:order:dlq"))
+	ch := lmq.GetOrAddQueue(q.Queue)
+	dlq := lmq.GetOrAddQueue(q.DeadLetter)
+
+	start := time.Now()
+	err := r.Fail(q, "order-1", "500")
+	assert.Equal(t, nil, err, "1. 首次处理失败")
+	select {
+	case m := <-ch:
+		assert.Equal(t, "2", mq.GetHeaders(m)[context.XMessageAttempts], "2. 重试消息的处理次数")
+		at := getRetryAt(m)
+		assert.Equal(t, true, !at.Before(start.Add(time.Second).Truncate(time.Millisecond)) && at.Before(time.Now().Add(time.Second)), "3. 重试消息的到期时间", at)
+		err = r.Fail(q, m, "500:error")
+		assert.Equal(t, nil, err, "4. 超过最大处理次数")
+	default:
+		t.Fatal("消息未立即重新放入队列")
+	}
+	select {
+	case m := <-dlq:
+		h := mq.GetHeaders(m)
+		assert.Equal(t, q.Queue, h[context.XOriginQueue], "5. 死信消息的原队列")
+		assert.Equal(t, "500:error", h[context.XDeadReason], "6. 死信消息的原因")
+		assert.Equal(t, "", h[context.XMessageRetryAt], "7. 死信消息不保留到期时间")
+		assert.Equal(t, "order-1", mq.GetBody(m), "8. 死信消息的内容")
+	default:
+		t.Fatal("消息未放入死信队列")
+	}
+}
+
+func TestRetrier_Wait(t *testing.T) {
+	r := newRetrier("lmq", "")
+	retryAt := func(d time.Duration) string {
+		ms := time.Now().Add(d).UnixNano() / int64(time.Millisecond)
+		return mq.SetHeaders("order", map[string]string{context.XMessageRetryAt: strconv.FormatInt(ms, 10)})
+	}
+	closed := make(chan struct{})
+	close(closed)
+
+	tests := []struct {
+		name      string
+		message   string
+		closeChan chan struct{}
+		want      bool
+		min       time.Duration
+	}{
+		{name: "1. 非重试消息", message: "order", closeChan: make(chan struct{}), want: true},
+		{name: "2. 已到期的重试消息", message: retryAt(-time.Second), closeChan: make(chan struct{}), want: true},
+		{name: "3. 等待到期", message: retryAt(100 * time.Millisecond), closeChan: make(chan struct{}), want: true, min: 50 * time.Millisecond},
+		{name: "4. 等待时服务器关闭", message: retryAt(time.Hour), closeChan: closed, want: false},
+	}
+	for _, tt := range tests {
+		start := time.Now()
+		got := r.Wait(tt.message, tt.closeChan)
+		assert.Equal(t, tt.want, got, tt.name)
+		assert.Equal(t, true, time.Since(start) >= tt.min, tt.name, time.Since(start))
+	}
+}
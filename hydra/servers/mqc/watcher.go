@@ -44,6 +44,9 @@ LOOP:
 			}
 
 			if server.Sharding == 0 || cluster.Current().IsMaster(server.Sharding) {
+				if w.isPaused() {
+					continue
+				}
 				ok, err := w.Server.Resume()
 				if err != nil {
 					w.log.Error("恢复mqc服务器失败:", err)
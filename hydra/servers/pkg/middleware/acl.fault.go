@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+//Fault 故障注入，按配置对指定比例的请求注入延时、错误、中断连接或panic
+func Fault() Handler {
+	return func(ctx IMiddleContext) {
+		conf, err := ctx.APPConf().GetFaultConf()
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, err)
+			return
+		}
+		rule, ok := conf.Match(ctx.Request().Path().GetRequestPath(), func(k string) string {
+			return ctx.Request().Headers().GetString(k)
+		})
+		if !ok {
+			ctx.Next()
+			return
+		}
+		ctx.Response().AddSpecial("fault")
+		ctx.Log().Warnf("故障注入[%s]:delay:%s status:%d abort:%v panic:%v", rule.Name, rule.Delay, rule.Status, rule.Abort, rule.Panic)
+
+		//1. 注入延时
+		if d := rule.GetDelay(); d > 0 {
+			time.Sleep(d)
+		}
+
+		//2. 触发panic
+		if rule.Panic {
+			panic(fmt.Sprintf("故障注入[%s]", rule.Name))
+		}
+
+		//3. 中断连接
+		if rule.Abort {
+			abortConn(ctx)
+			return
+		}
+
+		//4. 返回错误
+		if rule.Status > 0 {
+			if rule.Content != "" {
+				ctx.Response().Abort(rule.Status, rule.Content)
+				return
+			}
+			ctx.Response().Abort(rule.Status, fmt.Errorf("故障注入[%s]", rule.Name))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+//abortConn 关闭http连接并终止后续处理，非http请求直接终止处理不返回内容
+func abortConn(ctx IMiddleContext) {
+	if _, resp := ctx.GetHttpReqResp(); resp != nil {
+		if hj, ok := resp.(http.Hijacker); ok {
+			if conn, _, err := hj.Hijack(); err == nil {
+				conn.Close()
+				ctx.Response().NoNeedWrite(http.StatusServiceUnavailable)
+				if c, ok := ctx.Meta().Get("__context_"); ok {
+					c.(*gin.Context).Abort()
+				}
+				return
+			}
+		}
+	}
+	ctx.Response().Abort(http.StatusServiceUnavailable)
+}
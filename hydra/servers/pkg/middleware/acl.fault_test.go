@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micro-plat/hydra/conf/server/acl/fault"
+	"github.com/micro-plat/hydra/registry"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestFault(t *testing.T) {
+	next := make(chan struct{}, 1)
+	engine := newTestEngine(t, "api", map[string]interface{}{
+		registry.Join(fault.ParNodeName, fault.SubNodeName): fault.New(fault.WithRule(
+			fault.NewRule(100, fault.WithName("delay"), fault.WithHeader("X-Fault", "delay"), fault.WithDelay("50ms")),
+			fault.NewRule(100, fault.WithName("status"), fault.WithHeader("X-Fault", "status"), fault.WithStatus(http.StatusInternalServerError, "fault")),
+			fault.NewRule(100, fault.WithName("abort"), fault.WithHeader("X-Fault", "abort"), fault.WithAbort()),
+		)),
+	}, Fault(), func(ctx IMiddleContext) {
+		next <- struct{}{}
+		ctx.Next()
+	})
+	server := httptest.NewServer(engine)
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		fault    string
+		minDelay time.Duration
+		status   int
+		body     string
+		wantErr  bool
+	}{
+		{name: "1. 未匹配规则", status: http.StatusOK, body: "success"},
+		{name: "2. 注入延时", fault: "delay", minDelay: 50 * time.Millisecond, status: http.StatusOK, body: "success"},
+		{name: "3. 返回错误状态码", fault: "status", status: http.StatusInternalServerError, body: "fault"},
+		{name: "4. 中断连接", fault: "abort", wantErr: true},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodGet, server.URL+"/order/query", nil)
+		if tt.fault != "" {
+			r.Header.Set("X-Fault", tt.fault)
+		}
+		start := time.Now()
+		resp, err := http.DefaultClient.Do(r)
+		if tt.wantErr {
+			assert.NotEqual(t, nil, err, tt.name)
+			select {
+			case <-next:
+				t.Error(tt.name, "中断连接后不应继续执行后续处理")
+			case <-time.After(100 * time.Millisecond):
+			}
+			continue
+		}
+		assert.Equal(t, nil, err, tt.name)
+		select {
+		case <-next:
+		default:
+		}
+		buff := make([]byte, 64)
+		n, _ := resp.Body.Read(buff)
+		resp.Body.Close()
+		assert.Equal(t, true, time.Since(start) >= tt.minDelay, tt.name, "延时")
+		assert.Equal(t, tt.status, resp.StatusCode, tt.name)
+		assert.Equal(t, true, strings.Contains(string(buff[:n]), tt.body), tt.name, string(buff[:n]))
+	}
+}
+
+func TestFault_Conf(t *testing.T) {
+	engine := newTestEngine(t, "api", map[string]interface{}{
+		registry.Join(fault.ParNodeName, fault.SubNodeName): fault.New(fault.WithRule(fault.NewRule(100, fault.WithDelay("abc")))),
+	}, Fault())
+	w := serveTest(engine, httptest.NewRequest(http.MethodGet, "/order/query", nil))
+	assert.Equal(t, http.StatusNotExtended, w.Code, "配置有误")
+}
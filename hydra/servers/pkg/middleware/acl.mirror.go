@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/micro-plat/hydra/conf/server/acl/mirror"
+	"github.com/micro-plat/lib4go/logger"
+)
+
+//mirrorResult 主请求的处理结果，用于与影子请求对比
+type mirrorResult struct {
+	status  int
+	content string
+	elapsed time.Duration
+}
+
+//Mirror 流量复制，将采样的请求异步复制到上游集群，不影响主请求的处理，影子请求并发数受配置限制
+func Mirror() Handler {
+	return func(ctx IMiddleContext) {
+		conf, err := ctx.APPConf().GetMirrorConf()
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, err)
+			return
+		}
+		path := ctx.Request().Path().GetRequestPath()
+		if conf.Disable || !conf.IsMirror(path) {
+			ctx.Next()
+			return
+		}
+
+		//影子请求与文件上传请求不再复制
+		req, _ := ctx.GetHttpReqResp()
+		if req == nil || req.Header.Get(mirror.HeaderName) != "" ||
+			strings.Contains(req.Header.Get("Content-Type"), "multipart/form-data") {
+			ctx.Next()
+			return
+		}
+
+		//读取请求内容，body已缓存后续处理可继续读取
+		body, err := ctx.Request().GetBody()
+		if err != nil {
+			ctx.Next()
+			return
+		}
+		shadow := req.Clone(req.Context())
+		shadow.Header.Set(mirror.HeaderName, ctx.APPConf().GetServerConf().GetServerID())
+		if shadow.Header.Get("X-Request-Id") == "" {
+			shadow.Header.Set("X-Request-Id", ctx.User().GetRequestID())
+		}
+		ctx.Response().AddSpecial("mirror")
+
+		//处理主请求
+		start := time.Now()
+		ctx.Next()
+		primary := &mirrorResult{elapsed: time.Since(start)}
+		if conf.Compare {
+			primary.status, primary.content, _ = ctx.Response().GetFinalResponse()
+		}
+
+		//异步发送影子请求，超过最大并发数时丢弃
+		server := ctx.APPConf().GetServerConf()
+		if !conf.Acquire() {
+			ctx.Log().Debug("流量复制已达到最大并发数，丢弃影子请求")
+			markMeterBy(server.GetServerType(), server.GetServerName(), "server.mirror", "url", path, "result", "dropped")
+			return
+		}
+		go func() {
+			defer conf.Release()
+			sendMirror(conf, shadow, body, primary, ctx.Log(), server.GetServerType(), server.GetServerName())
+		}()
+	}
+}
+
+//sendMirror 发送影子请求，丢弃响应结果，启用对比时记录与主请求的差异
+func sendMirror(conf *mirror.Mirror, req *http.Request, body []byte, primary *mirrorResult, log logger.ILogger, serverType string, serverName string) {
+	path := req.URL.Path
+	u, err := conf.Next()
+	if err != nil {
+		log.Warn("流量复制获取上游服务器失败:", err)
+		markMeterBy(serverType, serverName, "server.mirror", "url", path, "result", "error")
+		return
+	}
+	shadow, err := http.NewRequest(req.Method, fmt.Sprintf("%s%s", u.String(), req.URL.RequestURI()), bytes.NewReader(body))
+	if err != nil {
+		log.Warn("流量复制构建请求失败:", err)
+		return
+	}
+	shadow.Header = req.Header
+	shadow.Host = req.Host
+
+	start := time.Now()
+	client := &http.Client{Timeout: time.Duration(conf.Timeout) * time.Second}
+	resp, err := client.Do(shadow)
+	elapsed := time.Since(start)
+	if err != nil {
+		log.Warn("流量复制请求失败:", u.Host, err)
+		markMeterBy(serverType, serverName, "server.mirror", "url", path, "result", "error")
+		return
+	}
+	defer resp.Body.Close()
+	if !conf.Compare {
+		ioutil.ReadAll(resp.Body)
+		markMeterBy(serverType, serverName, "server.mirror", "url", path, "result", "sent")
+		return
+	}
+
+	//对比主请求与影子请求的处理结果
+	content, _ := ioutil.ReadAll(resp.Body)
+	result := "same"
+	switch {
+	case resp.StatusCode != primary.status:
+		result = "status.diff"
+	case string(content) != primary.content:
+		result = "body.diff"
+	}
+	updateTimerBy(serverType, serverName, "server.mirror", elapsed, "url", path)
+	markMeterBy(serverType, serverName, "server.mirror", "url", path, "result", result)
+	if result != "same" {
+		log.Warnf("流量复制结果不一致[%s]:%s 主请求:%d %v 影子请求:%d %v", result, u.Host, primary.status, primary.elapsed, resp.StatusCode, elapsed)
+		return
+	}
+	log.Infof("流量复制结果一致:%s 主请求:%d %v 影子请求:%d %v", u.Host, primary.status, primary.elapsed, resp.StatusCode, elapsed)
+}
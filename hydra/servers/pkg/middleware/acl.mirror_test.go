@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micro-plat/hydra/conf/app"
+	"github.com/micro-plat/hydra/conf/server/acl/mirror"
+	"github.com/micro-plat/hydra/registry"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+//newMirrorUpstream 启动上游服务器并注册到gray集群，block不为空时影子请求等待block关闭后返回
+func newMirrorUpstream(t *testing.T, received chan string, block chan struct{}) *httptest.Server {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received <- r.Header.Get(mirror.HeaderName) + ":" + string(body)
+		if block != nil {
+			<-block
+		}
+		w.Write([]byte("success"))
+	}))
+	c, err := app.Cache.GetAPPConf("api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := c.GetServerConf()
+	u, _ := url.Parse(upstream.URL)
+	node := registry.Join(server.GetServerPubPath("gray"), u.Host+"_"+"mirror")
+	if err := server.GetRegistry().CreatePersistentNode(node, "{}"); err != nil {
+		t.Fatal(err)
+	}
+	return upstream
+}
+
+func TestMirror(t *testing.T) {
+	engine := newTestEngine(t, "api", map[string]interface{}{
+		registry.Join(mirror.ParNodeName, mirror.SubNodeName): mirror.New("gray", mirror.WithPaths("/order/*")),
+	}, Mirror())
+	received := make(chan string, 4)
+	upstream := newMirrorUpstream(t, received, nil)
+	defer upstream.Close()
+
+	tests := []struct {
+		name   string
+		path   string
+		mirror string
+		want   string
+	}{
+		{name: "1. 复制请求", path: "/order/create", want: ":order"},
+		{name: "2. 不匹配的路径", path: "/user/create"},
+		{name: "3. 影子请求不再复制", path: "/order/create", mirror: "abc"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader("order"))
+		if tt.mirror != "" {
+			r.Header.Set(mirror.HeaderName, tt.mirror)
+		}
+		w := serveTest(engine, r)
+		assert.Equal(t, http.StatusOK, w.Code, tt.name)
+		select {
+		case v := <-received:
+			assert.Equal(t, true, tt.want != "" && strings.HasSuffix(v, tt.want) && !strings.HasPrefix(v, ":"), tt.name, v)
+		case <-time.After(time.Second):
+			assert.Equal(t, "", tt.want, tt.name, "未收到影子请求")
+		}
+	}
+}
+
+func TestMirror_Concurrency(t *testing.T) {
+	engine := newTestEngine(t, "api", map[string]interface{}{
+		registry.Join(mirror.ParNodeName, mirror.SubNodeName): mirror.New("gray", mirror.WithConcurrency(1)),
+	}, Mirror())
+	received := make(chan string, 4)
+	block := make(chan struct{})
+	upstream := newMirrorUpstream(t, received, block)
+	defer upstream.Close()
+	defer close(block)
+
+	for i := 0; i < 3; i++ {
+		w := serveTest(engine, httptest.NewRequest(http.MethodPost, "/order/create", strings.NewReader("order")))
+		assert.Equal(t, http.StatusOK, w.Code, "主请求")
+	}
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Fatal("未收到影子请求")
+	}
+	select {
+	case v := <-received:
+		t.Error("超过最大并发数的影子请求应被丢弃", v)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -1,12 +1,14 @@
 package middleware
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httputil"
 	"strings"
 
 	"github.com/micro-plat/hydra/conf/server/acl/proxy"
+	"github.com/micro-plat/lib4go/types"
 )
 
 //Proxy 代理配置
@@ -23,13 +25,16 @@ func Proxy() Handler {
 		}
 
 		//检查当前请求是否需要进行代理
-		cluster, need, err := proxy.Check()
+		cluster, decision, err := proxy.Check(&proxyRequest{ctx: ctx})
 		if err != nil {
 			ctx.Response().AddSpecial("proxy")
 			ctx.Response().Abort(http.StatusBadGateway, err)
 			return
 		}
-		if !need {
+		if decision != nil {
+			ctx.Log().Debug("灰度分流:", decision)
+		}
+		if cluster == nil {
 			ctx.Next()
 			return
 		}
@@ -39,6 +44,52 @@ func Proxy() Handler {
 		useProxy(ctx, cluster)
 	}
 }
+
+//proxyRequest 灰度规则匹配所需的请求信息
+type proxyRequest struct {
+	ctx    IMiddleContext
+	claims types.XMap
+}
+
+func (r *proxyRequest) GetPath() string {
+	return r.ctx.Request().Path().GetRequestPath()
+}
+
+func (r *proxyRequest) GetHeader(name string) string {
+	return r.ctx.Request().Headers().GetString(name)
+}
+
+func (r *proxyRequest) GetCookie(name string) string {
+	return r.ctx.Request().Cookies().GetString(name)
+}
+
+func (r *proxyRequest) GetClientIP() string {
+	return r.ctx.User().GetClientIP()
+}
+
+func (r *proxyRequest) GetUser() string {
+	return getAuthUser(r.ctx)
+}
+
+//GetClaim 获取jwt中的用户信息字段，灰度分流在认证之前执行，jwt从配置的请求头或cookie中解析
+func (r *proxyRequest) GetClaim(name string) string {
+	if r.claims == nil {
+		r.claims = types.NewXMap()
+		switch v := getAuthData(r.ctx).(type) {
+		case nil:
+		case string:
+			r.claims, _ = types.NewXMapByJSON(v)
+		default:
+			buff, _ := json.Marshal(v)
+			r.claims, _ = types.NewXMapByJSON(string(buff))
+		}
+		if r.claims == nil {
+			r.claims = types.NewXMap()
+		}
+	}
+	return r.claims.GetString(name)
+}
+
 func useProxy(ctx IMiddleContext, cluster *proxy.UpCluster) {
 
 	//检查当前请求
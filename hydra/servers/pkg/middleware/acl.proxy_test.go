@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/micro-plat/hydra/conf/server/acl/proxy"
+	"github.com/micro-plat/hydra/conf/server/auth/jwt"
+	"github.com/micro-plat/hydra/registry"
+	"github.com/micro-plat/lib4go/assert"
+	xjwt "github.com/micro-plat/lib4go/security/jwt"
+)
+
+func TestProxy_Claim(t *testing.T) {
+	auth := jwt.NewJWT(jwt.WithSecret("12345678"), jwt.WithHeader())
+	engine := newTestEngine(t, "api", map[string]interface{}{
+		registry.Join(proxy.ParNodeName, proxy.SubNodeName): proxy.New(proxy.WithRule(proxy.NewRule(proxy.WithClaim("role", "tester"), proxy.WithUpCluster("gray")))),
+		registry.Join(jwt.ParNodeName, jwt.SubNodeName):     auth,
+	}, Proxy())
+
+	token := func(role string) string {
+		v, err := xjwt.Encrypt(auth.Secret, auth.Mode, map[string]interface{}{"role": role}, auth.ExpireAt)
+		assert.Equal(t, nil, err, "生成jwt")
+		return v
+	}
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{name: "1. 未传入jwt", want: http.StatusOK},
+		{name: "2. jwt字段不匹配", token: token("dev"), want: http.StatusOK},
+		{name: "3. jwt无效", token: "xxx", want: http.StatusOK},
+		{name: "4. jwt字段匹配,转到无可用服务器的上游集群", token: token("tester"), want: http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/order/query", nil)
+		if tt.token != "" {
+			r.Header.Set(auth.Name, tt.token)
+		}
+		assert.Equal(t, tt.want, serveTest(engine, r).Code, tt.name)
+	}
+}
@@ -1,5 +1,12 @@
 package middleware
 
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/micro-plat/hydra/context/ctx"
+)
+
 //APM 跟踪数据
 func APM() Handler {
 	return func(ctx IMiddleContext) {
@@ -8,8 +15,37 @@ func APM() Handler {
 			return
 		}
 		ctx.Response().AddSpecial("apm")
-		ctx.Tracer().Start()
-		defer ctx.Tracer().End()
+		root := ctx.Tracer().Root()
+		root.Start()
+		defer root.End()
+
+		//请求信息
+		root.SetTag("hydra.server_type", ctx.APPConf().GetServerConf().GetServerType())
+		root.SetTag("hydra.service", ctx.Request().Path().GetService())
+		root.SetTag("hydra.request_id", ctx.User().GetRequestID())
+		if req, _ := ctx.GetHttpReqResp(); req != nil {
+			root.SetTag("http.method", req.Method)
+			root.SetTag("http.target", req.URL.RequestURI())
+			root.SetTag("http.client_ip", ctx.User().GetClientIP())
+		}
+
 		ctx.Next()
+
+		//处理结果，状态码使用业务错误转换后的最终状态码
+		status, _, _ := ctx.Response().GetFinalResponse()
+		root.SetTag("http.status_code", status)
+		if status >= http.StatusInternalServerError {
+			_, content, _ := ctx.Response().GetRawResponse()
+			if err, ok := content.(error); ok {
+				root.SetError(err)
+				return
+			}
+			root.SetError(fmt.Errorf("处理失败，状态码:%d", status))
+		}
 	}
 }
+
+//CloseAPM 关闭服务器的链路跟踪器，并上报剩余的跟踪数据
+func CloseAPM(serverType string) {
+	ctx.CloseTracer(serverType)
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/micro-plat/hydra/conf/server/apm"
+	"github.com/micro-plat/lib4go/assert"
+	"github.com/micro-plat/lib4go/errs"
+)
+
+func TestAPM_Error(t *testing.T) {
+	spans := make(chan []map[string]interface{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body []map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		spans <- body
+	}))
+	defer srv.Close()
+
+	engine := newTestEngine(t, "api", map[string]interface{}{
+		apm.TypeNodeName: apm.New(srv.URL, apm.WithProvider(apm.Zipkin)),
+	}, APM(), func(ctx IMiddleContext) {
+		ctx.Response().Abort(http.StatusOK, errs.NewError(http.StatusInternalServerError, "处理失败"))
+	})
+	w := serveTest(engine, httptest.NewRequest(http.MethodPost, "/apm/fail", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code, "1. 业务处理返回错误")
+	CloseAPM("api")
+
+	body := <-spans
+	assert.Equal(t, 1, len(body), "2. 上报根跨度")
+	tags, _ := body[0]["tags"].(map[string]interface{})
+	assert.Equal(t, "500", tags["http.status_code"], "3. 使用最终状态码")
+	assert.Equal(t, "处理失败", tags["error"], "4. 记录业务错误")
+}
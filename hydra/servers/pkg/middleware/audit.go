@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/micro-plat/hydra/components"
+	paudit "github.com/micro-plat/hydra/components/pkgs/audit"
+	"github.com/micro-plat/hydra/conf/server/audit"
+	"github.com/micro-plat/lib4go/logger"
+)
+
+//auditEmitter 服务器对应的审计事件输出器，配置变化后重新构建
+type auditEmitter struct {
+	conf    *audit.Audit
+	emitter *paudit.Emitter
+}
+
+var auditEmitters = make(map[string]*auditEmitter)
+var auditLock sync.Mutex
+
+//Audit 请求审计，处理完成后异步输出审计事件
+func Audit() Handler {
+	return func(ctx IMiddleContext) {
+		conf, err := ctx.APPConf().GetAuditConf()
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, err)
+			return
+		}
+		path := ctx.Request().Path().GetRequestPath()
+		if !conf.IsEnable(path) {
+			ctx.Next()
+			return
+		}
+		ctx.Response().AddSpecial("audit")
+
+		//1. 业务处理
+		start := time.Now()
+		ctx.Next()
+
+		//2. 构建审计事件并提交，缓冲区已满时丢弃
+		serverType := ctx.APPConf().GetServerConf().GetServerType()
+		status, _, _ := ctx.Response().GetFinalResponse()
+		event := &paudit.Event{
+			Time:       start,
+			RequestID:  ctx.User().GetRequestID(),
+			ServerType: serverType,
+			Service:    path,
+			Method:     ctx.Request().Path().GetMethod(),
+			ClientIP:   ctx.User().GetClientIP(),
+			User:       getAuthUser(ctx),
+			Params:     conf.GetParams(ctx.Request().GetMap()),
+			Status:     status,
+			Latency:    time.Since(start).Milliseconds(),
+		}
+		getAuditEmitter(serverType, conf).Emit(event)
+	}
+}
+
+//getAuditEmitter 获取服务器对应的审计事件输出器，配置变化后关闭原输出器
+func getAuditEmitter(serverType string, conf *audit.Audit) *paudit.Emitter {
+	auditLock.Lock()
+	defer auditLock.Unlock()
+	if e, ok := auditEmitters[serverType]; ok {
+		if e.conf == conf {
+			return e.emitter
+		}
+		go e.emitter.Close()
+	}
+	e := &auditEmitter{conf: conf, emitter: paudit.NewEmitter(newAuditWriter(conf), conf.Buffer, logger.New("audit"))}
+	auditEmitters[serverType] = e
+	return e.emitter
+}
+
+//newAuditWriter 根据配置构建审计事件输出，未配置消息队列时写入本地文件
+func newAuditWriter(conf *audit.Audit) paudit.IWriter {
+	if conf.Queue == "" {
+		return paudit.NewFile(conf.File)
+	}
+	return paudit.WriterFunc(func(p []byte) error {
+		q, err := components.Def.Queue().GetQueue(conf.Queue)
+		if err != nil {
+			return fmt.Errorf("审计获取消息队列失败:%s %w", conf.Queue, err)
+		}
+		return q.Send(conf.Key, string(p))
+	})
+}
+
+//CloseAudit 关闭服务器的审计事件输出器，并输出剩余的审计事件
+func CloseAudit(serverType string) {
+	auditLock.Lock()
+	e, ok := auditEmitters[serverType]
+	delete(auditEmitters, serverType)
+	auditLock.Unlock()
+	if ok {
+		e.emitter.Close()
+	}
+}
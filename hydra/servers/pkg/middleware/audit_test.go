@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	paudit "github.com/micro-plat/hydra/components/pkgs/audit"
+	"github.com/micro-plat/hydra/conf/server/audit"
+	"github.com/micro-plat/hydra/conf/server/auth/basic"
+	"github.com/micro-plat/hydra/registry"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestAudit_Event(t *testing.T) {
+	dir := t.TempDir()
+	engine := newTestEngine(t, "api", map[string]interface{}{
+		audit.TypeNodeName: audit.New([]string{"/order/*"}, audit.WithParams("*"), audit.WithFile(filepath.Join(dir, "%date.audit"))),
+		registry.Join(basic.ParNodeName, basic.SubNodeName): basic.NewBasic(basic.WithUP("admin", "123456")),
+	}, Audit())
+	r := httptest.NewRequest(http.MethodPost, "/order/create?order_no=1&password=123456", nil)
+	r.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte("admin:123456")))
+	w := serveTest(engine, r)
+	assert.Equal(t, http.StatusOK, w.Code, "1. 业务处理")
+	CloseAudit("api")
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.audit"))
+	assert.Equal(t, 1, len(files), "2. 写入审计文件")
+	buff, _ := ioutil.ReadFile(files[0])
+	event := &paudit.Event{}
+	assert.Equal(t, nil, json.Unmarshal([]byte(strings.TrimSpace(string(buff))), event), "3. 审计事件格式")
+	assert.Equal(t, "admin", event.User, "4. 认证中间件执行前获取用户")
+	assert.Equal(t, "1", event.Params["order_no"], "5. 记录参数")
+	assert.Equal(t, audit.DefMask, event.Params["password"], "6. 未配置日志时敏感参数脱敏")
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"encoding/json"
+
+	"github.com/micro-plat/hydra/context"
+)
+
+//getAuthData 获取请求的认证信息，认证中间件尚未处理时(如灰度分流在认证之前执行)根据jwt、basic认证配置从请求中解析
+func getAuthData(ctx IMiddleContext) interface{} {
+	switch v := ctx.User().Auth().Request().(type) {
+	case nil, func() interface{}:
+	default:
+		return v
+	}
+	if jwt, err := ctx.APPConf().GetJWTConf(); err == nil && !jwt.Disable {
+		if data, err := jwt.CheckJWT(getToken(ctx, jwt)); err == nil {
+			return data
+		}
+	}
+	if basic, err := ctx.APPConf().GetBasicConf(); err == nil && !basic.Disable {
+		if user, ok := basic.Verify(ctx.Request().Headers().GetString("Authorization")); ok {
+			return map[string]interface{}{context.UserName: user}
+		}
+	}
+	return nil
+}
+
+//getAuthUser 获取请求的用户信息，basic认证返回用户名，jwt认证返回jwt中保存的用户信息，未认证时返回空
+func getAuthUser(ctx IMiddleContext) string {
+	if name := ctx.User().GetUserName(); name != "" {
+		return name
+	}
+	data := getAuthData(ctx)
+	if data == nil {
+		return ""
+	}
+	if v, ok := data.(string); ok {
+		return v
+	}
+	if v, ok := data.(map[string]interface{}); ok && len(v) == 1 {
+		if name, ok := v[context.UserName].(string); ok {
+			return name
+		}
+	}
+	buff, _ := json.Marshal(data)
+	return string(buff)
+}
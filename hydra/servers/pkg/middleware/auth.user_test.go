@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/micro-plat/hydra/conf/server/auth/basic"
+	"github.com/micro-plat/hydra/registry"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestGetAuthUser(t *testing.T) {
+	var user string
+	engine := newTestEngine(t, "api", map[string]interface{}{
+		registry.Join(basic.ParNodeName, basic.SubNodeName): basic.NewBasic(basic.WithUP("admin", "123456")),
+	}, func(ctx IMiddleContext) {
+		user = getAuthUser(ctx)
+		ctx.Next()
+	})
+	tests := []struct {
+		name string
+		auth string
+		want string
+	}{
+		{name: "1. 未认证", want: ""},
+		{name: "2. 认证失败", auth: "Basic " + base64.StdEncoding.EncodeToString([]byte("admin:000000")), want: ""},
+		{name: "3. 认证中间件执行前获取basic用户名", auth: "Basic " + base64.StdEncoding.EncodeToString([]byte("admin:123456")), want: "admin"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/order/query", nil)
+		if tt.auth != "" {
+			r.Header.Set("Authorization", tt.auth)
+		}
+		serveTest(engine, r)
+		assert.Equal(t, tt.want, user, tt.name)
+	}
+}
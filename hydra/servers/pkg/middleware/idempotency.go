@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/micro-plat/hydra/components"
+	"github.com/micro-plat/hydra/components/caches"
+	cacheredis "github.com/micro-plat/hydra/components/caches/cache/redis"
+	"github.com/micro-plat/hydra/conf"
+	"github.com/micro-plat/hydra/conf/server/idempotency"
+	varredis "github.com/micro-plat/hydra/conf/vars/redis"
+	"github.com/micro-plat/lib4go/types"
+)
+
+//idemResult 保存到缓存中的幂等处理结果
+type idemResult struct {
+	Done        bool              `json:"done"`
+	Status      int               `json:"status,omitempty"`
+	Content     string            `json:"content,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+//Idempotency 幂等处理，相同幂等键的请求只处理一次，重复请求返回首次处理的结果
+func Idempotency() Handler {
+	return func(ctx IMiddleContext) {
+
+		//1. 获取幂等配置
+		idem, err := ctx.APPConf().GetIdempotencyConf()
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, err)
+			return
+		}
+		path := ctx.Request().Path().GetRequestPath()
+		if !idem.IsEnable(path, ctx.Request().Path().GetMethod()) {
+			ctx.Next()
+			return
+		}
+
+		//2. 获取幂等键，未传入时不做处理
+		key := ctx.Request().Headers().GetString(idem.Header)
+		if key == "" && idem.Param != "" {
+			key = ctx.Request().GetString(idem.Param)
+		}
+		if key == "" {
+			ctx.Next()
+			return
+		}
+		ctx.Response().AddSpecial("idem")
+		cache, err := getIdemCache(idem)
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, fmt.Errorf("幂等处理获取缓存失败:%w", err))
+			return
+		}
+
+		//3. 锁定调用方的幂等键，锁定失败时返回已保存的结果
+		ckey := idem.GetKey(ctx.APPConf().GetServerConf().GetServerType(), path, getIdemCaller(ctx), key)
+		processing, _ := json.Marshal(&idemResult{})
+		if err := cache.Add(ckey, string(processing), idem.LockTimeout); err != nil {
+			replayIdemResult(ctx, cache, ckey, err)
+			return
+		}
+
+		//4. 业务处理
+		ctx.Next()
+
+		//5. 保存最终响应结果，服务器错误时释放锁允许客户端重试
+		status, content, ctp := ctx.Response().GetFinalResponse()
+		if status >= http.StatusInternalServerError {
+			cache.Delete(ckey)
+			return
+		}
+		status = types.DecodeInt(status, 0, http.StatusOK, status)
+		result := &idemResult{Done: true, Status: status, Content: content, ContentType: ctp, Headers: make(map[string]string)}
+		for k, v := range ctx.Response().GetHeaders() {
+			if k != "Set-Cookie" && k != "Content-Length" {
+				result.Headers[k] = fmt.Sprint(v)
+			}
+		}
+		buff, err := json.Marshal(result)
+		if err != nil {
+			ctx.Log().Errorf("幂等处理结果转换失败:%s %v", ckey, err)
+			cache.Delete(ckey)
+			return
+		}
+		if err := cache.Set(ckey, string(buff), idem.Expire); err != nil {
+			ctx.Log().Errorf("幂等处理结果保存失败:%s %v", ckey, err)
+		}
+	}
+}
+
+//getIdemCache 获取存储幂等结果的缓存，设置了redis配置时使用var/redis/[name]，否则使用var/cache/[name]
+func getIdemCache(idem *idempotency.Idempotency) (caches.ICache, error) {
+	if idem.Redis == "" {
+		return components.Def.Cache().GetCache(idem.Cache)
+	}
+	obj, err := components.Def.Container().GetOrCreate(varredis.TypeNodeName, idem.Redis, func(conf *conf.RawConf) (interface{}, error) {
+		if conf.IsEmpty() {
+			return nil, fmt.Errorf("节点/%s/%s未配置，或不可用", varredis.TypeNodeName, idem.Redis)
+		}
+		return cacheredis.NewByConfig(varredis.NewByRaw(string(conf.GetRaw())))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj.(caches.ICache), nil
+}
+
+//getIdemCaller 获取调用方标识，已认证的请求使用认证用户，否则使用客户端IP
+func getIdemCaller(ctx IMiddleContext) string {
+	if name := ctx.User().GetUserName(); name != "" {
+		return "user:" + name
+	}
+	switch v := ctx.User().Auth().Request().(type) {
+	case nil, func() interface{}:
+	case string:
+		return "user:" + v
+	default:
+		buff, _ := json.Marshal(v)
+		return "user:" + string(buff)
+	}
+	return "ip:" + ctx.User().GetClientIP()
+}
+
+//replayIdemResult 输出已保存的处理结果，首次请求仍在处理中时返回409
+func replayIdemResult(ctx IMiddleContext, cache caches.ICache, ckey string, lerr error) {
+	data, err := cache.Get(ckey)
+	if err != nil {
+		ctx.Response().Abort(http.StatusInternalServerError, fmt.Errorf("幂等处理获取结果失败:%w", err))
+		return
+	}
+	if data == "" {
+		ctx.Response().Abort(http.StatusInternalServerError, fmt.Errorf("幂等处理锁定失败:%w", lerr))
+		return
+	}
+	result := &idemResult{}
+	if err := json.Unmarshal([]byte(data), result); err != nil {
+		ctx.Response().Abort(http.StatusInternalServerError, fmt.Errorf("幂等处理结果格式有误:%w", err))
+		return
+	}
+	if !result.Done {
+		ctx.Response().Abort(http.StatusConflict, fmt.Errorf("请求正在处理中,请勿重复提交"))
+		return
+	}
+	ctx.Response().AddSpecial("replay")
+	for k, v := range result.Headers {
+		ctx.Response().Header(k, v)
+	}
+	ctx.Response().ContentType(result.ContentType)
+	ctx.Response().Abort(result.Status, result.Content)
+}
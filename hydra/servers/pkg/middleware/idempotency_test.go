@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/micro-plat/hydra/components"
+	"github.com/micro-plat/hydra/conf/server/idempotency"
+	"github.com/micro-plat/hydra/conf/vars/cache/gocache"
+	varredis "github.com/micro-plat/hydra/conf/vars/redis"
+	"github.com/micro-plat/lib4go/assert"
+
+	_ "github.com/micro-plat/hydra/components/caches/cache/gocache"
+)
+
+type idemTest struct {
+	name   string
+	ip     string
+	key    string
+	status int
+	want   string
+}
+
+func runIdemTests(t *testing.T, sub *idempotency.Idempotency, vars map[string]interface{}, tests []idemTest) {
+	n := 0
+	engine := newTestEngineWithVars(t, "api", map[string]interface{}{
+		idempotency.TypeNodeName: sub,
+	}, vars, Idempotency(), func(ctx IMiddleContext) {
+		n++
+		ctx.Response().Abort(http.StatusOK, fmt.Sprint(n))
+	})
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/order/create", nil)
+		r.RemoteAddr = tt.ip + ":10000"
+		r.Header.Set(idempotency.DefHeader, tt.key)
+		w := serveTest(engine, r)
+		assert.Equal(t, tt.status, w.Code, tt.name)
+		assert.Equal(t, tt.want, w.Body.String(), tt.name)
+	}
+}
+
+func TestIdempotency_Caller(t *testing.T) {
+	runIdemTests(t, idempotency.New([]string{"/order/*"}), map[string]interface{}{
+		"cache/cache": gocache.New(),
+	}, []idemTest{
+		{name: "1. 首次请求", ip: "192.0.2.1", key: "k1", status: http.StatusOK, want: "1"},
+		{name: "2. 相同调用方重复请求返回首次结果", ip: "192.0.2.1", key: "k1", status: http.StatusOK, want: "1"},
+		{name: "3. 其它调用方使用相同的幂等键", ip: "192.0.2.2", key: "k1", status: http.StatusOK, want: "2"},
+		{name: "4. 相同调用方使用不同的幂等键", ip: "192.0.2.1", key: "k2", status: http.StatusOK, want: "3"},
+	})
+}
+
+func TestIdempotency_NoResponse(t *testing.T) {
+	engine := newTestEngineWithVars(t, "api", map[string]interface{}{
+		idempotency.TypeNodeName: idempotency.New([]string{"/order/*"}),
+	}, map[string]interface{}{
+		"cache/cache": gocache.New(),
+	}, Idempotency())
+	r := httptest.NewRequest(http.MethodPost, "/order/create", nil)
+	r.RemoteAddr = "192.0.2.1:10000"
+	r.Header.Set(idempotency.DefHeader, "k1")
+	w := serveTest(engine, r)
+	assert.Equal(t, http.StatusOK, w.Code, "1. 业务处理直接输出响应")
+
+	cache, err := components.Def.Cache().GetCache("cache")
+	assert.Equal(t, nil, err, "2. 获取缓存")
+	data, err := cache.Get(idempotency.New(nil).GetKey("api", "/order/create", "ip:192.0.2.1", "k1"))
+	assert.Equal(t, nil, err, "3. 获取保存的结果")
+	result := &idemResult{}
+	json.Unmarshal([]byte(data), result)
+	assert.Equal(t, true, result.Done, "4. 处理已完成")
+	assert.Equal(t, http.StatusOK, result.Status, "5. 未设置状态码时保存200")
+}
+
+func TestIdempotency_Redis(t *testing.T) {
+	addr := os.Getenv("HYDRA_TEST_REDIS")
+	if addr == "" {
+		t.Skip("未设置HYDRA_TEST_REDIS")
+	}
+	runIdemTests(t, idempotency.New([]string{"/order/*"}, idempotency.WithRedis("idem")), map[string]interface{}{
+		"redis/idem": varredis.New(addr),
+	}, []idemTest{
+		{name: "1. 未配置cache时使用redis保存结果", ip: "192.0.2.1", key: fmt.Sprint(os.Getpid()), status: http.StatusOK, want: "1"},
+		{name: "2. 重复请求返回redis中保存的结果", ip: "192.0.2.1", key: fmt.Sprint(os.Getpid()), status: http.StatusOK, want: "1"},
+	})
+}
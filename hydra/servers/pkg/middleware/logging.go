@@ -3,16 +3,22 @@ package middleware
 import (
 	"net/http"
 	"time"
+
+	"github.com/micro-plat/hydra/conf/vars/logging"
 )
 
 //Logging 记录日志
 func Logging() Handler {
 	return func(ctx IMiddleContext) {
 
-		//1. 整个服务的开始,记录请求时间与日志
+		//1. 整个服务的开始,记录请求时间与日志，未命中采样时不记录请求日志
 		start := time.Now()
-		path := ctx.Request().Path().GetURL()
-		ctx.Log().Info(ctx.APPConf().GetServerConf().GetServerType()+".request:", ctx.Request().Path().GetMethod(), path, "from", ctx.User().GetClientIP())
+		conf := logging.Current()
+		path := conf.RedactURL(ctx.Request().Path().GetURL())
+		sampled := conf.IsSampled(ctx.Request().Path().GetRequestPath(), ctx.User().GetRequestID())
+		if sampled {
+			ctx.Log().Info(ctx.APPConf().GetServerConf().GetServerType()+".request:", ctx.Request().Path().GetMethod(), path, "from", ctx.User().GetClientIP())
+		}
 
 		//2. 处理业务
 		ctx.Next()
@@ -20,10 +26,12 @@ func Logging() Handler {
 		//3. 将结果刷新到响应流
 		ctx.Response().Flush()
 
-		//4. 处理响应日志
+		//4. 处理响应日志，失败请求与慢请求始终记录
 		code, _, _ := ctx.Response().GetFinalResponse()
 		if code >= http.StatusOK && code < http.StatusBadRequest {
-			ctx.Log().Info(ctx.APPConf().GetServerConf().GetServerType()+".response:", ctx.Request().Path().GetMethod(), path, code, ctx.Response().GetSpecials(), time.Since(start))
+			if sampled || conf.IsSlow(time.Since(start)) {
+				ctx.Log().Info(ctx.APPConf().GetServerConf().GetServerType()+".response:", ctx.Request().Path().GetMethod(), path, code, ctx.Response().GetSpecials(), time.Since(start))
+			}
 		} else {
 			ctx.Log().Error(ctx.APPConf().GetServerConf().GetServerType()+".response:", ctx.Request().Path().GetMethod(), path, code, ctx.Response().GetSpecials(), time.Since(start))
 		}
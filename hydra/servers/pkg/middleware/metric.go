@@ -2,20 +2,25 @@ package middleware
 
 import (
 	"fmt"
+	"net/http"
 	"sync"
+	"time"
 
+	xmetrics "github.com/micro-plat/hydra/components/metrics"
 	"github.com/micro-plat/hydra/components/pkgs/metrics"
+	"github.com/micro-plat/hydra/conf/server/metric"
 	"github.com/micro-plat/lib4go/logger"
 	"github.com/micro-plat/hydra/global"
 )
 
 //Metric 服务器处理能力统计
 type Metric struct {
-	reporter        metrics.IReporter
+	reporters       []metrics.IReporter
 	logger          *logger.Logger
 	currentRegistry metrics.Registry
 	needCollect     bool
-	once            sync.Once
+	conf            *metric.Metric
+	lock            sync.RWMutex
 	ip              string
 }
 
@@ -24,45 +29,103 @@ func NewMetric() *Metric {
 	return &Metric{}
 
 }
-func (m *Metric) onceDo(ctx IMiddleContext) {
-	m.once.Do(func() {
-		metric, err := ctx.APPConf().GetMetricConf()
-		if err != nil {
-			panic(fmt.Errorf("metric配置获取失败:%w", err))
-		}
 
-		if metric.Disable {
-			return
-		}
+//check 检查metric配置，配置变化后重新构建上报服务
+func (m *Metric) check(ctx IMiddleContext) error {
+	conf, err := ctx.APPConf().GetMetricConf()
+	if err != nil {
+		return fmt.Errorf("metric配置获取失败:%w", err)
+	}
+	m.lock.RLock()
+	changed := conf != m.conf
+	m.lock.RUnlock()
+	if !changed {
+		return nil
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if conf == m.conf {
+		return nil
+	}
 
+	//1. 关闭已有的上报服务
+	m.conf = conf
+	m.closeReporters()
+	serverType := ctx.APPConf().GetServerConf().GetServerType()
+	if conf.Disable {
+		m.needCollect = false
+		xmetrics.Unregister(serverType)
+		return nil
+	}
+	if m.currentRegistry == nil {
 		m.currentRegistry = metrics.NewRegistry()
 		m.ip = global.LocalIP()
 		m.logger = logger.New("metric")
+	}
+	xmetrics.Register(serverType, m.currentRegistry)
 
-		//2. 创建上报服务
-		m.reporter, err = metrics.InfluxDB(m.currentRegistry,
-			metric.Cron,
-			metric.Host,
-			metric.DataBase,
-			metric.UserName,
-			metric.Password, m.logger)
+	//2. 创建上报服务
+	for _, r := range conf.GetReporters() {
+		reporter, err := newReporter(m.currentRegistry, conf.GetCron(), r, m.logger)
 		if err != nil {
-			panic(fmt.Errorf("初始化metric失败:%w", err))
+			m.closeReporters()
+			m.needCollect = false
+			m.conf = nil //下次请求时重新创建上报服务
+			xmetrics.Unregister(serverType)
+			m.logger.Error("初始化metric失败:", err)
+			return nil
 		}
-		m.needCollect = true
+		m.reporters = append(m.reporters, reporter)
+
 		//定时上报
-		go m.reporter.Run()
+		go reporter.Run()
+	}
+	m.needCollect = true
+	return nil
+}
+
+//newReporter 根据配置构建上报服务
+func newReporter(r metrics.Registry, cron string, c *metric.Reporter, l *logger.Logger) (metrics.IReporter, error) {
+	switch c.Type {
+	case metric.InfluxDB:
+		return metrics.InfluxDB(r, cron, c.Address, c.DataBase, c.UserName, c.Password, l)
+	case metric.InfluxDB2:
+		return metrics.InfluxDB2(r, cron, c.Address, c.Org, c.DataBase, c.Token, l)
+	case metric.Graphite:
+		return metrics.GraphiteReporter(r, cron, c.Address, c.Prefix, l)
+	case metric.OpenTSDB:
+		return metrics.OpenTSDBReporter(r, cron, c.Address, c.Prefix, l)
+	case metric.StatsD:
+		return metrics.StatsD(r, cron, c.Address, c.Prefix, l)
+	case metric.Log:
+		return metrics.LogReporter(r, cron, l)
+	case metric.JSON:
+		return metrics.JSONFileReporter(r, cron, c.Path, l)
+	}
+	return nil, fmt.Errorf("不支持的上报服务类型:%s", c.Type)
+}
 
-	})
+func (m *Metric) closeReporters() {
+	for _, r := range m.reporters {
+		r.Close()
+	}
+	m.reporters = nil
 }
 
 //Handle 处理请求
 func (m *Metric) Handle() Handler {
 	return func(ctx IMiddleContext) {
 
-		//执行首次初始化
-		m.onceDo(ctx)
-		if !m.needCollect {
+		//检查配置变化
+		if err := m.check(ctx); err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, err)
+			return
+		}
+		m.lock.RLock()
+		needCollect, registry := m.needCollect, m.currentRegistry
+		m.lock.RUnlock()
+		if !needCollect {
 			ctx.Next()
 			return
 		}
@@ -76,14 +139,14 @@ func (m *Metric) Handle() Handler {
 		requestName := metrics.MakeName(ctx.APPConf().GetServerConf().GetServerType()+".server.request", metrics.QPS, "server", ctx.APPConf().GetServerConf().GetServerName(), "host", m.ip, "url", url)    //请求数
 
 		//2. 对QPS进行计数
-		metrics.GetOrRegisterQPS(requestName, m.currentRegistry).Mark(1)
+		metrics.GetOrRegisterQPS(requestName, registry).Mark(1)
 
 		//3.对正在请求的服务进行计数
-		counter := metrics.GetOrRegisterCounter(conterName, m.currentRegistry)
+		counter := metrics.GetOrRegisterCounter(conterName, registry)
 		counter.Inc(1)
 
 		//4. 对服务处理时长进行统计
-		metrics.GetOrRegisterTimer(timerName, m.currentRegistry).Time(func() {
+		metrics.GetOrRegisterTimer(timerName, registry).Time(func() {
 			ctx.Next()
 		})
 
@@ -95,15 +158,43 @@ func (m *Metric) Handle() Handler {
 		responseName := metrics.MakeName(ctx.APPConf().GetServerConf().GetServerType()+".server.response", metrics.METER, "server", ctx.APPConf().GetServerConf().GetServerName(), "host", m.ip,
 			"url", url, "status", fmt.Sprintf("%d", statusCode)) //完成数
 		//7. 对服务处理结果的状态码进行上报
-		metrics.GetOrRegisterMeter(responseName, m.currentRegistry).Mark(1)
+		metrics.GetOrRegisterMeter(responseName, registry).Mark(1)
+	}
+
+}
+
+//markMeter 使用当前服务器的统计器进行计数，未启用metric时不处理
+func markMeter(ctx IMiddleContext, name string, params ...string) {
+	server := ctx.APPConf().GetServerConf()
+	markMeterBy(server.GetServerType(), server.GetServerName(), name, params...)
+}
+
+//markMeterBy 使用指定服务器类型的统计器进行计数，用于请求处理完成后的异步统计
+func markMeterBy(serverType string, serverName string, name string, params ...string) {
+	r, ok := xmetrics.GetRegistry(serverType)
+	if !ok {
+		return
 	}
+	tags := append([]string{"server", serverName, "host", global.LocalIP()}, params...)
+	meterName := metrics.MakeName(serverType+"."+name, metrics.METER, tags...)
+	metrics.GetOrRegisterMeter(meterName, r).Mark(1)
+}
 
+//updateTimerBy 使用指定服务器类型的统计器记录处理时长
+func updateTimerBy(serverType string, serverName string, name string, d time.Duration, params ...string) {
+	r, ok := xmetrics.GetRegistry(serverType)
+	if !ok {
+		return
+	}
+	tags := append([]string{"server", serverName, "host", global.LocalIP()}, params...)
+	timerName := metrics.MakeName(serverType+"."+name, metrics.TIMER, tags...)
+	metrics.GetOrRegisterTimer(timerName, r).Update(d)
 }
 
 //Stop stop metric
 func (m *Metric) Stop() {
-	if m.reporter != nil {
-		m.reporter.Close()
-		m.reporter = nil
-	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.closeReporters()
+	m.conf = nil
 }
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/micro-plat/hydra/conf/server/metric"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestMetric_Retry(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "metric")
+	m := NewMetric()
+	defer m.Stop()
+	engine := newTestEngine(t, "api", map[string]interface{}{
+		metric.TypeNodeName: metric.New("", "", "@every 1h", metric.WithReporter(metric.NewReporter(metric.JSON, "", metric.WithPath(filepath.Join(dir, "metric.json"))))),
+	}, m.Handle())
+
+	w := serveTest(engine, httptest.NewRequest(http.MethodGet, "/order/query", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "1. 上报服务创建失败时不影响请求")
+	assert.Equal(t, false, m.needCollect, "1. 不进行统计")
+
+	os.MkdirAll(dir, 0755)
+	serveTest(engine, httptest.NewRequest(http.MethodGet, "/order/query", nil))
+	assert.Equal(t, true, m.needCollect, "2. 下次请求时重新创建上报服务")
+}
@@ -2,6 +2,9 @@ package middleware
 
 import (
 	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/micro-plat/hydra/context"
@@ -54,6 +57,7 @@ type Handler func(IMiddleContext)
 
 //GinFunc 返回GIN对应的处理函数
 func (h Handler) GinFunc(tps ...string) gin.HandlerFunc {
+	name := h.name()
 	return func(c *gin.Context) {
 		v, ok := c.Get("__middle_context__")
 		if !ok {
@@ -63,12 +67,13 @@ func (h Handler) GinFunc(tps ...string) gin.HandlerFunc {
 			v = newMiddleContext(nctx, rawCtx, c.Request, c.Writer)
 			c.Set("__middle_context__", v)
 		}
-		h(v.(IMiddleContext))
+		h.handle(name, v.(IMiddleContext))
 	}
 }
 
 //DispFunc 返回disp对应的处理函数
 func (h Handler) DispFunc(tps ...string) dispatcher.HandlerFunc {
+	name := h.name()
 	return func(c *dispatcher.Context) {
 		v, ok := c.Get("__middle_context__")
 		if !ok {
@@ -78,6 +83,33 @@ func (h Handler) DispFunc(tps ...string) dispatcher.HandlerFunc {
 			v = newMiddleContext(nctx, rawCtx, nil, nil)
 			c.Set("__middle_context__", v)
 		}
-		h(v.(IMiddleContext))
+		h.handle(name, v.(IMiddleContext))
 	}
 }
+
+//handle 执行中间件，启用慢请求分析时记录中间件阶段的耗时
+func (h Handler) handle(name string, ctx IMiddleContext) {
+	if p, ok := context.GetProfile(ctx.Context()); ok {
+		defer p.Phase(name)()
+	}
+	h(ctx)
+}
+
+//name 获取中间件名称，如Logging、(*Metric).Handle
+func (h Handler) name() string {
+	f := runtime.FuncForPC(reflect.ValueOf(h).Pointer())
+	if f == nil {
+		return "unknown"
+	}
+	name := f.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.LastIndex(name, ".func"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+)
+
+//sharedServer 独立端口的数据输出服务，refs为使用该服务的服务器类型
+type sharedServer struct {
+	addr    string
+	handler http.Handler
+	server  *http.Server
+	refs    map[string]bool
+}
+
+//sharedServers 按地址管理的独立端口服务，同一地址由多个服务器共用
+type sharedServers struct {
+	name    string
+	servers map[string]*sharedServer
+	lock    sync.Mutex
+}
+
+func newSharedServers(name string) *sharedServers {
+	return &sharedServers{name: name, servers: make(map[string]*sharedServer)}
+}
+
+//serve 在指定地址上启动服务，地址已启动时只增加引用，handler仅在首次启动时创建
+func (s *sharedServers) serve(addr string, serverType string, handler func() http.Handler) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if v, ok := s.servers[addr]; ok {
+		v.refs[serverType] = true
+		return nil
+	}
+	lsr, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("%s监听端口失败:%s %w", s.name, addr, err)
+	}
+	v := &sharedServer{addr: addr, handler: handler(), refs: map[string]bool{serverType: true}}
+	v.server = &http.Server{Handler: v.handler}
+	s.servers[addr] = v
+	go v.server.Serve(lsr)
+	return nil
+}
+
+//close 移除服务器类型的引用，地址未被其它服务器使用时关闭端口
+func (s *sharedServers) close(serverType string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for addr, v := range s.servers {
+		delete(v.refs, serverType)
+		if len(v.refs) == 0 {
+			v.server.Close()
+			delete(s.servers, addr)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestSharedServers(t *testing.T) {
+	lsr, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Equal(t, nil, err, "获取可用端口")
+	addr := lsr.Addr().String()
+	lsr.Close()
+
+	created := 0
+	handler := func() http.Handler {
+		created++
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("success"))
+		})
+	}
+	get := func() error {
+		resp, err := http.Get("http://" + addr + "/")
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	s := newSharedServers("test")
+	assert.Equal(t, nil, s.serve(addr, "api", handler), "1. 启动服务")
+	assert.Equal(t, nil, s.serve(addr, "rpc", handler), "2. 相同地址共用服务")
+	assert.Equal(t, 1, created, "2. 仅创建一次处理函数")
+	assert.Equal(t, nil, get(), "2. 访问服务")
+
+	s.close("api")
+	assert.Equal(t, nil, get(), "3. 地址仍被其它服务器使用")
+
+	s.close("rpc")
+	assert.NotEqual(t, nil, get(), "4. 地址未被使用时关闭端口")
+	assert.Equal(t, 0, len(s.servers), "4. 移除服务")
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/micro-plat/hydra/conf/app"
+	"github.com/micro-plat/hydra/context"
+	"github.com/micro-plat/hydra/registry"
+	"github.com/micro-plat/lib4go/assert"
+	"github.com/micro-plat/lib4go/logger"
+
+	_ "github.com/micro-plat/hydra/registry/registry/localmemory"
+	_ "github.com/micro-plat/hydra/registry/watcher/wchild"
+	_ "github.com/micro-plat/hydra/registry/watcher/wvalue"
+)
+
+var testPlatID int32
+
+//newTestEngine 将子配置写入本地内存注册中心并缓存为当前服务器配置，返回使用指定中间件的gin引擎，
+//业务处理返回200
+func newTestEngine(t *testing.T, serverType string, subs map[string]interface{}, handlers ...Handler) *gin.Engine {
+	return newTestEngineWithVars(t, serverType, subs, nil, handlers...)
+}
+
+//newTestEngineWithVars 同newTestEngine，vars为var配置，如:cache/cache
+func newTestEngineWithVars(t *testing.T, serverType string, subs map[string]interface{}, vars map[string]interface{}, handlers ...Handler) *gin.Engine {
+	r, err := registry.GetRegistry("lm://.", logger.New("hydra"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plat := fmt.Sprintf("middleware%d", atomic.AddInt32(&testPlatID, 1))
+	root := registry.Join(plat, "sys", serverType, "t", "conf")
+	if err := r.CreatePersistentNode(root, `{"address":":8080"}`); err != nil {
+		t.Fatal(err)
+	}
+	nodes := make(map[string]interface{})
+	for k, v := range subs {
+		nodes[registry.Join(root, k)] = v
+	}
+	for k, v := range vars {
+		nodes[registry.Join(plat, "var", k)] = v
+	}
+	for k, v := range nodes {
+		buff, _ := json.Marshal(v)
+		if s, ok := v.(string); ok {
+			buff = []byte(s)
+		}
+		if err := r.CreatePersistentNode(k, string(buff)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c, err := app.NewAPPConfBy(plat, "sys", serverType, "t", r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.Cache.Save(c)
+
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	for _, h := range handlers {
+		engine.Use(h.GinFunc(serverType))
+	}
+	engine.Any("/*path", func(c *gin.Context) {
+		c.String(http.StatusOK, "success")
+	})
+	return engine
+}
+
+//serveTest 处理请求并返回响应结果
+func serveTest(engine *gin.Engine, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, r)
+	return w
+}
+
+func TestMiddle_RequestID(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	engine := newTestEngine(t, "api", nil, func(ctx IMiddleContext) {
+		wg.Done()
+		wg.Wait()
+		cur := context.Current()
+		ctx.Response().Abort(http.StatusOK, fmt.Sprintf("%s,%s,%v", ctx.User().GetRequestID(),
+			cur.Request().Path().GetRequestPath(), cur.User().GetGID() == ctx.User().GetGID()))
+	})
+	ch := make(chan string, 2)
+	for _, path := range []string{"/a", "/b"} {
+		go func(path string) {
+			r := httptest.NewRequest(http.MethodGet, path, nil)
+			r.Header.Set(context.XRequestID, "abc123")
+			ch <- path + ":" + serveTest(engine, r).Body.String()
+		}(path)
+	}
+	got := map[string]bool{<-ch: true, <-ch: true}
+	assert.Equal(t, true, got["/a:abc123,/a,true"], "1. 使用上游请求编号且获取到自己的上下文")
+	assert.Equal(t, true, got["/b:abc123,/b,true"], "2. 相同请求编号的并发请求互不影响")
+}
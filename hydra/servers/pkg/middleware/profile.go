@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/micro-plat/hydra/components/pkgs/slowlog"
+	"github.com/micro-plat/hydra/conf/app"
+	"github.com/micro-plat/hydra/context"
+)
+
+//Profile 慢请求分析，处理时长超过阈值时输出各阶段与组件调用的耗时明细，并汇总最慢的请求与组件调用
+func Profile() Handler {
+	return func(ctx IMiddleContext) {
+		conf, err := ctx.APPConf().GetProfileConf()
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, err)
+			return
+		}
+		if conf.Disable {
+			ctx.Next()
+			return
+		}
+
+		path := ctx.Request().Path().GetRequestPath()
+		p, ok := context.GetProfile(ctx.Context())
+		ctx.Next()
+		if !ok {
+			return
+		}
+
+		//处理时长超过阈值时输出耗时明细
+		elapsed := p.Elapsed()
+		if elapsed < conf.GetThreshold(path) {
+			return
+		}
+		ctx.Response().AddSpecial("slow")
+		timings := p.Timings()
+		serverType := ctx.APPConf().GetServerConf().GetServerType()
+		slowlog.Default.Add(serverType, path, elapsed, timings)
+		ctx.Log().Warnf("%s.slow: %s %s %v%s", serverType, ctx.Request().Path().GetMethod(), path, elapsed, p.String())
+	}
+}
+
+var profileServers = newSharedServers("profile")
+
+//ServeProfile 在独立端口上输出慢请求汇总数据，同一地址由多个服务器共用，未指定独立端口时不输出
+func ServeProfile(cnf app.IAPPConf) error {
+	conf, err := cnf.GetProfileConf()
+	if err != nil {
+		return err
+	}
+	if conf.Disable || conf.Address == "" {
+		return nil
+	}
+	return profileServers.serve(conf.Address, cnf.GetServerConf().GetServerType(), func() http.Handler {
+		mux := http.NewServeMux()
+		top := conf.Top
+		mux.HandleFunc(conf.Path, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			json.NewEncoder(w).Encode(slowlog.Default.Top(top))
+		})
+		return mux
+	})
+}
+
+//CloseProfile 关闭服务器对应的慢请求汇总数据输出服务，地址未被其它服务器使用时关闭端口
+func CloseProfile(serverType string) {
+	profileServers.close(serverType)
+}
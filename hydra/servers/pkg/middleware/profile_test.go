@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/micro-plat/hydra/conf/server/profile"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestProfile_Report(t *testing.T) {
+	engine := newTestEngine(t, "api", map[string]interface{}{
+		profile.TypeNodeName: profile.New(),
+	}, Profile())
+	w := serveTest(engine, httptest.NewRequest(http.MethodGet, profile.DefPath, nil))
+	assert.Equal(t, http.StatusOK, w.Code, "1. 未指定独立端口")
+	assert.Equal(t, "success", w.Body.String(), "1. 服务端口上不输出汇总数据")
+}
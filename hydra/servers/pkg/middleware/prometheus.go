@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/micro-plat/hydra/components/pkgs/metrics"
+	"github.com/micro-plat/hydra/conf/app"
+)
+
+//Prometheus 统计请求数与处理时长，api,web服务器未指定独立端口时输出统计数据
+func Prometheus() Handler {
+	return func(ctx IMiddleContext) {
+		conf, err := ctx.APPConf().GetPrometheusConf()
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, err)
+			return
+		}
+		if conf.Disable {
+			ctx.Next()
+			return
+		}
+
+		//输出统计数据
+		if conf.IsMetricPath(ctx.Request().Path().GetRequestPath()) {
+			ctx.Response().AddSpecial("prometheus")
+			buff := &strings.Builder{}
+			openMetrics := isOpenMetrics(ctx.Request().Headers().GetString("Accept"))
+			if err := metrics.DefaultPrometheus.Write(buff, openMetrics); err != nil {
+				ctx.Response().Abort(http.StatusInternalServerError, err)
+				return
+			}
+			ctx.Response().ContentType(getPromContentType(openMetrics))
+			ctx.Response().Abort(http.StatusOK, buff.String())
+			return
+		}
+
+		//统计请求数与处理时长
+		start := time.Now()
+		ctx.Next()
+		ctx.Response().Flush()
+		status, _, _ := ctx.Response().GetFinalResponse()
+		service := ctx.Request().Path().GetService()
+		if service == "" {
+			service = "unknown"
+		}
+		metrics.DefaultPrometheus.Observe(ctx.APPConf().GetServerConf().GetServerType(), service,
+			ctx.Request().Path().GetMethod(), status, time.Since(start))
+	}
+}
+
+var promServers = newSharedServers("prometheus")
+
+//ServePrometheus 在独立端口上输出统计数据，同一地址由多个服务器共用
+func ServePrometheus(cnf app.IAPPConf) error {
+	conf, err := cnf.GetPrometheusConf()
+	if err != nil {
+		return err
+	}
+	if conf.Disable || conf.Address == "" {
+		return nil
+	}
+	return promServers.serve(conf.Address, cnf.GetServerConf().GetServerType(), func() http.Handler {
+		mux := http.NewServeMux()
+		mux.HandleFunc(conf.Path, func(w http.ResponseWriter, r *http.Request) {
+			openMetrics := isOpenMetrics(r.Header.Get("Accept"))
+			w.Header().Set("Content-Type", getPromContentType(openMetrics))
+			metrics.DefaultPrometheus.Write(w, openMetrics)
+		})
+		return mux
+	})
+}
+
+//ClosePrometheus 关闭服务器对应的统计数据输出服务，地址未被其它服务器使用时关闭端口
+func ClosePrometheus(serverType string) {
+	promServers.close(serverType)
+}
+
+func isOpenMetrics(accept string) bool {
+	return strings.Contains(accept, "application/openmetrics-text")
+}
+
+func getPromContentType(openMetrics bool) string {
+	if openMetrics {
+		return metrics.OpenMetricsTextType
+	}
+	return metrics.PrometheusTextType
+}
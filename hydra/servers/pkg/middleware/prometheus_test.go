@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/micro-plat/hydra/components/pkgs/metrics"
+	"github.com/micro-plat/hydra/conf/server/prometheus"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestPrometheus_Status(t *testing.T) {
+	engine := newTestEngine(t, "api", map[string]interface{}{
+		prometheus.TypeNodeName: prometheus.New(),
+	}, Prometheus(), func(ctx IMiddleContext) {
+		ctx.Response().Abort(http.StatusOK, errors.New("处理失败"))
+	})
+	w := serveTest(engine, httptest.NewRequest(http.MethodPost, "/prometheus/fail", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code, "1. 业务处理返回错误")
+
+	buff := &strings.Builder{}
+	metrics.DefaultPrometheus.Write(buff, false)
+	assert.Equal(t, true, strings.Contains(buff.String(), `server_type="api",service="unknown",method="POST",status="400"`), "2. 按最终状态码统计")
+}
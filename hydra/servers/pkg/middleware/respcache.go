@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/micro-plat/hydra/components"
+	"github.com/micro-plat/hydra/components/caches/respcache"
+)
+
+//不需要缓存的响应头
+var respCacheExcludeHeaders = []string{"Set-Cookie", "Content-Length", "Date"}
+
+//RespCache 响应缓存，对匹配规则的GET,HEAD请求缓存处理结果
+func RespCache() Handler {
+	return func(ctx IMiddleContext) {
+
+		//1. 获取缓存配置
+		conf, err := ctx.APPConf().GetCacheConf()
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, err)
+			return
+		}
+		method := strings.ToUpper(ctx.Request().Path().GetMethod())
+		if conf.Disable || (method != http.MethodGet && method != http.MethodHead) {
+			ctx.Next()
+			return
+		}
+		path := ctx.Request().Path().GetRequestPath()
+		ok, rule := conf.GetRule(path)
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		//2. 请求指定不使用缓存
+		reqControl := strings.ToLower(ctx.Request().Headers().GetString("Cache-Control"))
+		if strings.Contains(reqControl, "no-store") {
+			ctx.Next()
+			return
+		}
+		c, err := components.Def.Cache().GetCache(conf.Cache)
+		if err != nil {
+			ctx.Response().Abort(http.StatusNotExtended, fmt.Errorf("响应缓存获取缓存失败:%w", err))
+			return
+		}
+
+		//3. 构建缓存键，获取缓存的响应结果
+		store := respcache.New(c, ctx.APPConf().GetServerConf().GetServerType())
+		key := store.GetKey(rule.GetKeyRaw(path, func(k string) string {
+			return ctx.Request().GetString(k)
+		}, func(k string) string {
+			return ctx.Request().Headers().GetString(k)
+		}, getCacheUser(ctx)))
+		versions, err := store.GetVersions(path, rule.Tags...)
+		if err != nil {
+			ctx.Log().Error("响应缓存获取版本号失败:", err)
+			ctx.Next()
+			return
+		}
+		if !strings.Contains(reqControl, "no-cache") {
+			entry, ok, err := store.Get(key, versions)
+			if err != nil {
+				ctx.Log().Error("响应缓存获取结果失败:", err)
+			}
+			if ok {
+				ctx.Response().AddSpecial("cache.hit")
+				markMeter(ctx, "server.cache", "url", path, "result", "hit")
+				writeCacheEntry(ctx, entry, method)
+				return
+			}
+		}
+
+		//4. 业务处理
+		ctx.Response().AddSpecial("cache.miss")
+		markMeter(ctx, "server.cache", "url", path, "result", "miss")
+		ctx.Next()
+
+		//5. 保存处理结果
+		status, content, ctp := ctx.Response().GetFinalResponse()
+		if status != http.StatusOK || method != http.MethodGet {
+			return
+		}
+		headers := ctx.Response().GetHeaders()
+		respControl := strings.ToLower(headers.GetString("Cache-Control"))
+		if strings.Contains(respControl, "no-store") || (strings.Contains(respControl, "private") && !rule.User) {
+			return
+		}
+		entry := &respcache.Entry{
+			Status:      status,
+			Content:     content,
+			ContentType: ctp,
+			ETag:        respcache.MakeETag(content),
+			Headers:     make(map[string]string),
+			Versions:    versions,
+		}
+		for k, v := range headers {
+			if !isRespCacheExcludeHeader(ctx, k) {
+				entry.Headers[k] = fmt.Sprint(v)
+			}
+		}
+		if respControl == "" {
+			entry.Headers["Cache-Control"] = rule.GetCacheControl()
+			ctx.Response().Header("Cache-Control", entry.Headers["Cache-Control"])
+		}
+		ctx.Response().Header("ETag", entry.ETag)
+		if err := store.Save(key, entry, rule.Expire); err != nil {
+			ctx.Log().Error("响应缓存保存结果失败:", err)
+		}
+		if isNotModified(ctx, entry.ETag) {
+			ctx.Response().Write(http.StatusNotModified, "")
+		}
+	}
+}
+
+//writeCacheEntry 输出缓存的响应结果，ETag未变化时返回304，HEAD请求只输出响应头
+func writeCacheEntry(ctx IMiddleContext, entry *respcache.Entry, method string) {
+	for k, v := range entry.Headers {
+		ctx.Response().Header(k, v)
+	}
+	ctx.Response().Header("ETag", entry.ETag)
+	if isNotModified(ctx, entry.ETag) {
+		ctx.Response().Abort(http.StatusNotModified, "")
+		return
+	}
+	ctx.Response().ContentType(entry.ContentType)
+	if method == http.MethodHead {
+		ctx.Response().Abort(entry.Status, "")
+		return
+	}
+	ctx.Response().Abort(entry.Status, entry.Content)
+}
+
+//isNotModified 检查请求的If-None-Match是否与ETag一致
+func isNotModified(ctx IMiddleContext, etag string) bool {
+	match := ctx.Request().Headers().GetString("If-None-Match")
+	if match == "" {
+		return false
+	}
+	for _, v := range strings.Split(match, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == etag || v == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+//getCacheUser 获取参与缓存键计算的用户信息
+func getCacheUser(ctx IMiddleContext) string {
+	if name := ctx.User().GetUserName(); name != "" {
+		return name
+	}
+	switch v := ctx.User().Auth().Request().(type) {
+	case nil, func() interface{}:
+		return ""
+	case string:
+		return v
+	default:
+		buff, _ := json.Marshal(v)
+		return string(buff)
+	}
+}
+
+func isRespCacheExcludeHeader(ctx IMiddleContext, k string) bool {
+	for _, h := range respCacheExcludeHeaders {
+		if strings.EqualFold(h, k) {
+			return true
+		}
+	}
+	if jwt, err := ctx.APPConf().GetJWTConf(); err == nil && !jwt.Disable {
+		return strings.EqualFold(jwt.Name, k)
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/micro-plat/hydra/conf/server/cache"
+	"github.com/micro-plat/hydra/conf/vars/cache/gocache"
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestRespCache_Head(t *testing.T) {
+	n := 0
+	engine := newTestEngineWithVars(t, "api", map[string]interface{}{
+		cache.TypeNodeName: cache.New(cache.WithRuleList(cache.NewRule("/order/*", 60))),
+	}, map[string]interface{}{
+		"cache/cache": gocache.New(),
+	}, RespCache(), func(ctx IMiddleContext) {
+		n++
+		ctx.Response().Abort(http.StatusOK, fmt.Sprintf("order%d", n))
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		want   string
+	}{
+		{name: "1. 首次请求", method: http.MethodGet, want: "order1"},
+		{name: "2. GET请求命中缓存", method: http.MethodGet, want: "order1"},
+		{name: "3. HEAD请求命中缓存只返回响应头", method: http.MethodHead, want: ""},
+	}
+	for _, tt := range tests {
+		w := serveTest(engine, httptest.NewRequest(tt.method, "/order/query", nil))
+		assert.Equal(t, http.StatusOK, w.Code, tt.name)
+		assert.Equal(t, tt.want, w.Body.String(), tt.name)
+		assert.NotEqual(t, "", w.Header().Get("ETag"), tt.name)
+	}
+	assert.Equal(t, 1, n, "业务处理次数")
+}
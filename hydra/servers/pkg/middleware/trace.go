@@ -1,5 +1,7 @@
 package middleware
 
+import "github.com/micro-plat/hydra/conf/vars/logging"
+
 //Trace 系统跟踪日志
 func Trace() Handler {
 	return func(ctx IMiddleContext) {
@@ -12,9 +14,10 @@ func Trace() Handler {
 
 		ctx.Response().AddSpecial("trace")
 
-		//1.打印请求参数
-		input := ctx.Request().GetMap()
-		ctx.Log().Debug("> trace.request:", input)
+		//1.打印请求参数与请求头，敏感信息脱敏
+		conf := logging.Current()
+		ctx.Log().Debug("> trace.request:", conf.RedactMap(ctx.Request().GetMap()))
+		ctx.Log().Debug("> trace.headers:", conf.RedactHeaders(ctx.Request().Headers()))
 
 		//2. 业务处理
 		ctx.Next()
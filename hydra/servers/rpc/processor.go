@@ -31,9 +31,15 @@ func NewProcessor(routers ...*router.Router) (p *Processor) {
 	p.Engine.Use(middleware.Recovery().DispFunc(RPC))
 	p.Engine.Use(middleware.Logging().DispFunc())
 	p.Engine.Use(middleware.Recovery().DispFunc())
+	p.Engine.Use(middleware.Prometheus().DispFunc()) //prometheus统计
+	p.Engine.Use(middleware.Profile().DispFunc())    //慢请求分析
+	p.Engine.Use(middleware.APM().DispFunc())        //链路跟踪
+	p.Engine.Use(middleware.Audit().DispFunc())      //请求审计
 
 	p.Engine.Use(middleware.Trace().DispFunc()) //跟踪信息
 	p.Engine.Use(middleware.Delay().DispFunc())
+	p.Engine.Use(middleware.Fault().DispFunc())       //故障注入
+	p.Engine.Use(middleware.Idempotency().DispFunc()) //幂等处理
 	p.Engine.Use(p.metric.Handle().DispFunc())
 	p.Engine.Use(middlewares.DispFunc()...)
 	p.addRouter(routers...)
@@ -164,6 +164,7 @@ func (r *RspServers) checkServer(path string) error {
 				return fmt.Errorf("[%s]服务器启动失败:%w", serverType, err)
 			}
 			r.servers[serverType] = srvr
+			running.Set(serverType, srvr)
 		} else {
 			return fmt.Errorf("服务器类型[%s]不支持或未注册", conf.GetServerConf().GetServerPath())
 		}
@@ -200,8 +201,9 @@ func (r *RspServers) Shutdown() {
 
 	//新协程关闭服务器
 	go func() {
-		for _, server := range r.servers {
+		for tp, server := range r.servers {
 			server.Shutdown()
+			running.Remove(tp)
 		}
 		close(cl)
 	}()
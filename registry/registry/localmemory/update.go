@@ -37,7 +37,7 @@ func (l *localMemory) Delete(path string) error {
 	defer l.lock.Unlock()
 	np := rpath
 	for k, nv := range l.nodes {
-		if strings.HasPrefix(k, np) {
+		if k == np || strings.HasPrefix(k, np+"/") {
 			delete(l.nodes, k)
 			l.notifyValueChange(path, nv)
 			l.notifyParentChange(path, version)
@@ -0,0 +1,30 @@
+package localmemory
+
+import (
+	"testing"
+
+	"github.com/micro-plat/lib4go/assert"
+)
+
+func TestLocalMemory_Delete(t *testing.T) {
+	l := NewLocalMemory()
+	for _, p := range []string{"/hydra/lease/task_1", "/hydra/lease/task_12", "/hydra/lease/task_1/a", "/hydra/lease/task_1a"} {
+		assert.Equal(t, nil, l.CreatePersistentNode(p, "1"), "创建节点", p)
+	}
+	assert.Equal(t, nil, l.Delete("/hydra/lease/task_1"), "删除节点")
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{name: "1. 删除指定节点", path: "/hydra/lease/task_1", want: false},
+		{name: "2. 删除子节点", path: "/hydra/lease/task_1/a", want: false},
+		{name: "3. 保留名称前缀相同的序列节点", path: "/hydra/lease/task_12", want: true},
+		{name: "4. 保留名称前缀相同的节点", path: "/hydra/lease/task_1a", want: true},
+	}
+	for _, tt := range tests {
+		b, err := l.Exists(tt.path)
+		assert.Equal(t, nil, err, tt.name)
+		assert.Equal(t, tt.want, b, tt.name)
+	}
+}
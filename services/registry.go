@@ -242,6 +242,14 @@ func (s *regist) GetFallback(serverType string, service string) (context.IHandle
 	return s.get(serverType).GetFallback(service)
 }
 
+//GetServices 获取服务器已注册的服务
+func (s *regist) GetServices(serverType string) []string {
+	if v, ok := s.servers[serverType]; ok {
+		return v.GetServices()
+	}
+	return nil
+}
+
 func (s *regist) get(tp string) *serverServices {
 	if v, ok := s.servers[tp]; ok {
 		return v